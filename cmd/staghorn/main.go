@@ -5,10 +5,11 @@ import (
 	"os"
 
 	"github.com/HartBrook/staghorn/internal/cli"
+	"github.com/HartBrook/staghorn/internal/errors"
 )
 
 func main() {
 	if err := cli.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
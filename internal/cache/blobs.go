@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/HartBrook/staghorn/internal/fsutil"
+)
+
+// BlobSHA returns the git blob SHA of content, matching the SHAs in GitHub trees.
+func BlobSHA(content string) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write([]byte(content))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ReadBlob returns a previously fetched file by its git blob SHA.
+// Returns false if the blob isn't cached or its content doesn't match the SHA.
+func (c *Cache) ReadBlob(sha string) (string, bool) {
+	if sha == "" {
+		return "", false
+	}
+
+	data, err := os.ReadFile(c.paths.BlobFile(sha))
+	if err != nil {
+		return "", false
+	}
+
+	content := string(data)
+	if BlobSHA(content) != sha {
+		return "", false
+	}
+	return content, true
+}
+
+// WriteBlob stores a fetched file under its git blob SHA.
+func (c *Cache) WriteBlob(sha, content string) error {
+	if sha == "" {
+		return nil
+	}
+
+	path := c.paths.BlobFile(sha)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(path, []byte(content), 0644)
+}
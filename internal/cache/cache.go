@@ -10,6 +10,7 @@ import (
 
 	"github.com/HartBrook/staghorn/internal/config"
 	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/fsutil"
 )
 
 // Cache manages local cached team configs.
@@ -78,7 +79,7 @@ func (c *Cache) Write(owner, repo, content string, meta *Metadata) error {
 	meta.Repo = repo
 
 	// Write content
-	if err := os.WriteFile(contentPath, []byte(content), 0644); err != nil {
+	if err := fsutil.WriteFile(contentPath, []byte(content), 0644); err != nil {
 		return err
 	}
 
@@ -87,7 +88,7 @@ func (c *Cache) Write(owner, repo, content string, meta *Metadata) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(metaPath, metaBytes, 0644)
+	return fsutil.WriteFile(metaPath, metaBytes, 0644)
 }
 
 // Exists checks if cache exists for a repo.
@@ -220,3 +220,39 @@ func TestGetMetadata(t *testing.T) {
 		t.Errorf("GetMetadata() ETag = %q, want %q", readMeta.ETag, "etag123")
 	}
 }
+
+func TestBlobSHA(t *testing.T) {
+	// Matches `printf 'hello\n' | git hash-object --stdin`
+	if got := BlobSHA("hello\n"); got != "ce013625030ba8dba906f756967f9e9ca394464a" {
+		t.Errorf("BlobSHA() = %q", got)
+	}
+}
+
+func TestBlobReadWrite(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New(config.NewPathsWithOverrides(tempDir, tempDir))
+
+	content := "# Review command"
+	sha := BlobSHA(content)
+
+	if _, ok := c.ReadBlob(sha); ok {
+		t.Fatal("ReadBlob() should miss before write")
+	}
+
+	if err := c.WriteBlob(sha, content); err != nil {
+		t.Fatalf("WriteBlob() error: %v", err)
+	}
+
+	got, ok := c.ReadBlob(sha)
+	if !ok || got != content {
+		t.Errorf("ReadBlob() = %q, %v; want %q, true", got, ok, content)
+	}
+
+	// A blob whose content doesn't match its SHA is treated as a miss
+	if err := c.WriteBlob("0000000000000000000000000000000000000000", content); err != nil {
+		t.Fatalf("WriteBlob() error: %v", err)
+	}
+	if _, ok := c.ReadBlob("0000000000000000000000000000000000000000"); ok {
+		t.Error("ReadBlob() should reject corrupted blob")
+	}
+}
@@ -12,6 +12,8 @@ type Metadata struct {
 	Repo        string    `json:"repo"`
 	ETag        string    `json:"etag,omitempty"`
 	SHA         string    `json:"sha,omitempty"`
+	Ref         string    `json:"ref,omitempty"`    // Branch, tag, or commit the content was fetched from
+	Commit      string    `json:"commit,omitempty"` // Commit the ref resolved to (empty if the provider can't resolve commits)
 	LastFetched time.Time `json:"last_fetched"`
 }
 
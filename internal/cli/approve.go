@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/manifest"
+	"github.com/HartBrook/staghorn/internal/skills"
+	"github.com/spf13/cobra"
+)
+
+// NewApproveCmd creates the approve command.
+func NewApproveCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "approve [skill...]",
+		Short: "Review and install skill changes held by sync",
+		Long: `Lists or approves skill changes that sync held back for review.
+
+Sync holds a team skill instead of installing it when the new version adds or
+changes hooks, adds tools to allowed-tools, or adds or changes an executable
+script. The previously installed version, if any, stays in place.
+
+Without arguments, lists held changes. With skill names (or --all), installs
+exactly the versions that were held from the cache.
+
+Set a per-source policy in the trusted list to change this:
+
+  trusted:
+    - repo: acme-corp
+      skills: allow   # review (default), allow, or deny`,
+		Example: `  staghorn approve              # List held skill changes
+  staghorn approve deploy       # Install the held version of the deploy skill
+  staghorn approve --all        # Install every held change`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := config.NewPaths()
+			if len(args) == 0 && !all {
+				return runApproveList(paths)
+			}
+			return runApprove(paths, args, all)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Approve every held skill change")
+
+	return cmd
+}
+
+// skillReview decides which skill changes sync may install and records the
+// ones it holds back for 'staghorn approve'.
+type skillReview struct {
+	cfg     *config.Config
+	path    string
+	pending *skills.Pending
+	held    []skills.Held
+	seen    map[string]bool
+}
+
+func newSkillReview(cfg *config.Config, paths *config.Paths) (*skillReview, error) {
+	pending, err := skills.LoadPending(paths.PendingSkillsFile())
+	if err != nil {
+		return nil, err
+	}
+	return &skillReview{cfg: cfg, path: paths.PendingSkillsFile(), pending: pending, seen: make(map[string]bool)}, nil
+}
+
+// admit reports whether skill may be installed over what's in claudeDir.
+// Personal and project skills are the user's own and are always admitted.
+func (r *skillReview) admit(skill *skills.Skill, claudeDir string) (bool, error) {
+	r.seen[skill.Name] = true
+	if skill.Source != skills.SourceTeam {
+		r.pending.Remove(skill.Name)
+		return true, nil
+	}
+
+	next, err := skills.ProfileOf(skill)
+	if err != nil {
+		return false, err
+	}
+	prev, err := skills.InstalledProfile(claudeDir, skill.Name)
+	if err != nil {
+		return false, err
+	}
+	risks := skills.Classify(prev, next)
+	if len(risks) == 0 {
+		r.pending.Remove(skill.Name)
+		return true, nil
+	}
+
+	repo := r.cfg.Source.RepoForSkill(skill.Name)
+	switch r.cfg.SkillPolicy(repo) {
+	case config.SkillPolicyAllow:
+		r.pending.Remove(skill.Name)
+		return true, nil
+	case config.SkillPolicyDeny:
+		r.pending.Remove(skill.Name)
+		printWarning("Skipping skill %s: %s (skills policy for %s is deny)", skill.Name, formatRisks(risks), repo)
+		return false, nil
+	}
+
+	fingerprint, err := skills.Fingerprint(skill)
+	if err != nil {
+		return false, err
+	}
+	h := skills.Held{Skill: skill.Name, Repo: repo, Fingerprint: fingerprint, Risks: risks, Held: time.Now()}
+	r.pending.Hold(h)
+	r.held = append(r.held, h)
+	return false, nil
+}
+
+// finish drops held changes for skills no longer synced, saves the pending
+// list, and tells the user what needs review.
+func (r *skillReview) finish() error {
+	for _, h := range append([]skills.Held{}, r.pending.Skills...) {
+		if !r.seen[h.Skill] {
+			r.pending.Remove(h.Skill)
+		}
+	}
+	if err := r.pending.Save(r.path); err != nil {
+		return fmt.Errorf("failed to save held skills: %w", err)
+	}
+
+	for _, h := range r.held {
+		printWarning("Held skill %s for review: %s", h.Skill, formatRisks(h.Risks))
+	}
+	if len(r.held) > 0 {
+		fmt.Printf("  %s Review with 'staghorn approve', then 'staghorn approve <skill>' to install\n", dim("Tip:"))
+	}
+	return nil
+}
+
+// formatRisks joins risks for a one-line summary.
+func formatRisks(risks []skills.Risk) string {
+	parts := make([]string, 0, len(risks))
+	for _, r := range risks {
+		parts = append(parts, r.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// installedSkillFiles returns the files currently installed for a skill,
+// relative to the skills directory.
+func installedSkillFiles(claudeDir, name string) []string {
+	var files []string
+	_ = filepath.WalkDir(filepath.Join(claudeDir, name), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if rel, err := filepath.Rel(claudeDir, path); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files
+}
+
+func runApproveList(paths *config.Paths) error {
+	pending, err := skills.LoadPending(paths.PendingSkillsFile())
+	if err != nil {
+		return err
+	}
+
+	if len(pending.Skills) == 0 {
+		fmt.Println("No skill changes are waiting for approval.")
+		return nil
+	}
+
+	fmt.Println("Skill changes held for review:")
+	for _, h := range pending.Skills {
+		fmt.Println()
+		fmt.Printf("  %s %s\n", warning(h.Skill), dim("from "+h.Repo+", held "+h.Held.Format("2006-01-02 15:04")))
+		for _, r := range h.Risks {
+			fmt.Printf("    - %s\n", r)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Review the skill in the source repo, then run %s\n", info("staghorn approve <skill>"))
+	return nil
+}
+
+func runApprove(paths *config.Paths, names []string, all bool) error {
+	lock, err := lockStaghorn(paths)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	pending, err := skills.LoadPending(paths.PendingSkillsFile())
+	if err != nil {
+		return err
+	}
+	if all {
+		names = nil
+		for _, h := range pending.Skills {
+			names = append(names, h.Skill)
+		}
+		if len(names) == 0 {
+			fmt.Println("No skill changes are waiting for approval.")
+			return nil
+		}
+	}
+
+	registry, err := loadClaudeSkills(cfg, paths)
+	if err != nil {
+		return err
+	}
+
+	claudeDir := paths.ClaudeSkillsDir()
+	if err := os.MkdirAll(claudeDir, 0755); err != nil {
+		return fmt.Errorf("failed to create Claude skills directory: %w", err)
+	}
+	m, err := manifest.Load(claudeDir)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		m = &manifest.Manifest{}
+	}
+
+	installed := 0
+	for _, name := range names {
+		h := pending.Get(name)
+		if h == nil {
+			return fmt.Errorf("no held change for skill %q; run 'staghorn approve' to list them", name)
+		}
+
+		// Only the reviewed version is installed; anything newer goes through sync again
+		skill := registry.Get(name)
+		fingerprint := ""
+		if skill != nil {
+			fingerprint, err = skills.Fingerprint(skill)
+			if err != nil {
+				return err
+			}
+		}
+		if fingerprint != h.Fingerprint {
+			printWarning("Skill %s changed since it was held; run 'staghorn sync' to review the new version", name)
+			continue
+		}
+
+		previous := installedSkillFiles(claudeDir, name)
+		if _, err := skills.SyncToClaude(skill, claudeDir); err != nil {
+			return fmt.Errorf("failed to install skill %s: %w", name, err)
+		}
+		current := skillFiles(skill)
+		if _, err := manifest.Remove(claudeDir, manifest.Stale(previous, current)); err != nil {
+			return err
+		}
+		m.Files = append(manifest.Stale(m.Files, previous), current...)
+
+		pending.Remove(name)
+		printSuccess("Approved and installed skill %s", name)
+		installed++
+	}
+
+	if err := pending.Save(paths.PendingSkillsFile()); err != nil {
+		return err
+	}
+	if installed == 0 {
+		return nil
+	}
+	if err := m.Save(claudeDir); err != nil {
+		return fmt.Errorf("failed to update manifest: %w", err)
+	}
+	recordGeneration(paths, nil)
+	return nil
+}
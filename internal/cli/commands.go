@@ -8,6 +8,7 @@ import (
 
 	"github.com/HartBrook/staghorn/internal/commands"
 	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/fsutil"
 	"github.com/HartBrook/staghorn/internal/starter"
 	"github.com/spf13/cobra"
 )
@@ -183,7 +184,7 @@ func runCommandsInitClaude(project bool) error {
 		}
 
 		content := commands.ConvertToClaude(cmd)
-		if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		if err := fsutil.WriteFile(outputPath, []byte(content), 0644); err != nil {
 			printWarning("Failed to write %s: %v", cmd.Name, err)
 			continue
 		}
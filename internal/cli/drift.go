@@ -0,0 +1,399 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/drift"
+	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/fsutil"
+	"github.com/spf13/cobra"
+)
+
+// rescueMarker precedes content rescued from a hand-edited generated file.
+const rescueMarker = "<!-- [staghorn] Rescued from %s -->"
+
+// NewRescueCmd creates the rescue command.
+func NewRescueCmd() *cobra.Command {
+	var discard bool
+
+	cmd := &cobra.Command{
+		Use:   "rescue [file...]",
+		Short: "Move hand edits of generated files into your own config",
+		Long: `Moves edits made directly to files staghorn generates into the config
+they're generated from, so the next sync keeps them.
+
+  ~/.claude/CLAUDE.md         added lines go to personal.md
+  ~/.claude/commands/*.md     the edited command becomes a personal command
+  ~/.claude/rules/*.md        the edited rule becomes a personal rule
+  ./CLAUDE.md                 edits go to .staghorn/project.md
+
+Sync and 'staghorn project edit' stop instead of overwriting edited files.
+Without arguments, rescues every edited file; 'staghorn info' lists them.
+Use --discard to let staghorn overwrite the edits instead.`,
+		Example: `  staghorn rescue                                # Rescue every edited file
+  staghorn rescue ~/.claude/commands/review.md   # Rescue one file
+  staghorn rescue --discard                      # Drop the edits on the next write`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRescue(config.NewPaths(), args, discard)
+		},
+	}
+
+	cmd.Flags().BoolVar(&discard, "discard", false, "Let staghorn overwrite the edits instead of moving them")
+
+	return cmd
+}
+
+// loadLedger loads the record of what staghorn last wrote to generated files.
+func loadLedger(paths *config.Paths) (*drift.Ledger, error) {
+	ledger, err := drift.Load(paths.DriftDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the record of generated files: %w", err)
+	}
+	return ledger, nil
+}
+
+// recordWrites notes the content just written to each generated file, so
+// hand edits made afterwards are caught before the next write.
+func recordWrites(paths *config.Paths, written map[string][]byte) {
+	if len(written) == 0 {
+		return
+	}
+	ledger, err := loadLedger(paths)
+	if err == nil {
+		for path, content := range written {
+			if err = ledger.Record(path, content); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = ledger.Save()
+	}
+	if err != nil {
+		printWarning("Failed to record generated files: %v", err)
+	}
+}
+
+// localEdits returns the generated files edited by hand since staghorn wrote
+// them. A project CLAUDE.md that matches its .staghorn/project.md isn't edited
+// even if it changed, since teammates regenerate and commit it.
+func localEdits(paths *config.Paths, ledger *drift.Ledger) []string {
+	var edited []string
+	for _, path := range ledger.Drifted() {
+		if project := projectPathsFor(paths, path); project != nil && projectInSync(project) {
+			continue
+		}
+		edited = append(edited, path)
+	}
+	return edited
+}
+
+// projectPathsFor returns the project paths when path is a project's generated
+// CLAUDE.md, or nil.
+func projectPathsFor(paths *config.Paths, path string) *config.ProjectPaths {
+	if path == paths.ClaudeMD() || filepath.Base(path) != "CLAUDE.md" {
+		return nil
+	}
+	return config.NewProjectPaths(filepath.Dir(path))
+}
+
+// projectInSync reports whether a project's CLAUDE.md is exactly what its
+// .staghorn/project.md generates.
+func projectInSync(project *config.ProjectPaths) bool {
+	expected, err := projectOutput(project)
+	if err != nil {
+		return false
+	}
+	output, err := os.ReadFile(project.OutputMD)
+	return err == nil && string(output) == expected
+}
+
+// isWithin reports whether path is inside dir.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+// syncWrites reports whether a sync run with opts writes path.
+func syncWrites(paths *config.Paths, opts *syncOptions, path string) bool {
+	switch {
+	case path == paths.ClaudeMD():
+		return opts.shouldApplyConfig()
+	case isWithin(paths.ClaudeCommandsDir(), path):
+		return !opts.applyOnly && opts.shouldSyncClaudeCommands()
+	case isWithin(paths.ClaudeRulesDir(), path):
+		return !opts.applyOnly && opts.shouldSyncClaudeRules()
+	}
+	return false
+}
+
+// syncLocalEdits returns the hand-edited files a sync run with opts would overwrite.
+func syncLocalEdits(paths *config.Paths, opts *syncOptions) ([]string, error) {
+	ledger, err := loadLedger(paths)
+	if err != nil {
+		return nil, err
+	}
+	var edited []string
+	for _, path := range localEdits(paths, ledger) {
+		if syncWrites(paths, opts, path) {
+			edited = append(edited, path)
+		}
+	}
+	return edited, nil
+}
+
+// checkLocalEdits stops a sync that would overwrite files edited by hand.
+func checkLocalEdits(paths *config.Paths, opts *syncOptions) error {
+	edited, err := syncLocalEdits(paths, opts)
+	if err != nil {
+		return err
+	}
+	if len(edited) > 0 {
+		return errors.LocalEdits(displayPaths(edited))
+	}
+	return nil
+}
+
+// checkFileEdits stops a write that would overwrite path if it was edited by hand.
+func checkFileEdits(paths *config.Paths, path string) error {
+	ledger, err := loadLedger(paths)
+	if err != nil {
+		return err
+	}
+	if ledger.IsDrifted(path) {
+		return errors.LocalEdits([]string{displayPath(path)})
+	}
+	return nil
+}
+
+// checkProjectEdits stops regenerating a project CLAUDE.md edited by hand.
+func checkProjectEdits(project *config.ProjectPaths) error {
+	paths := config.NewPaths()
+	ledger, err := loadLedger(paths)
+	if err != nil {
+		return err
+	}
+	if ledger.IsDrifted(project.OutputMD) && !projectInSync(project) {
+		return errors.LocalEdits([]string{relativePath(project.OutputMD)})
+	}
+	return nil
+}
+
+// displayPaths shortens each path for display.
+func displayPaths(paths []string) []string {
+	shown := make([]string, 0, len(paths))
+	for _, path := range paths {
+		shown = append(shown, displayPath(path))
+	}
+	return shown
+}
+
+func runRescue(paths *config.Paths, files []string, discard bool) error {
+	lock, err := lockStaghorn(paths)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	ledger, err := loadLedger(paths)
+	if err != nil {
+		return err
+	}
+	edited := localEdits(paths, ledger)
+
+	if len(files) > 0 {
+		var selected []string
+		for _, file := range files {
+			path, err := filepath.Abs(expandHome(file))
+			if err != nil {
+				return err
+			}
+			if !containsString(edited, path) {
+				return fmt.Errorf("%s has no hand edits to rescue", file)
+			}
+			selected = append(selected, path)
+		}
+		edited = selected
+	}
+
+	if len(edited) == 0 {
+		fmt.Println("No generated files have hand edits.")
+		return nil
+	}
+
+	var projects []*config.ProjectPaths
+	for _, path := range edited {
+		if discard {
+			printSuccess("Discarding edits to %s; the next write replaces them", displayPath(path))
+		} else {
+			project, err := rescueFile(paths, ledger, path)
+			if err != nil {
+				return err
+			}
+			if project != nil {
+				projects = append(projects, project)
+			}
+		}
+		ledger.Forget(path)
+	}
+	if err := ledger.Save(); err != nil {
+		return fmt.Errorf("failed to update the record of generated files: %w", err)
+	}
+
+	// Regenerate project files now so they pick up the rescued edits
+	for _, project := range projects {
+		if err := generateProjectOutput(project); err != nil {
+			return err
+		}
+		printSuccess("Regenerated %s", relativePath(project.OutputMD))
+	}
+
+	if !discard && len(projects) < len(edited) {
+		fmt.Printf("  %s Run 'staghorn sync --force' to regenerate ~/.claude/ with your edits in place\n", dim("Tip:"))
+	}
+	return nil
+}
+
+// expandHome replaces a leading ~ with the home directory.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~"); ok && (rest == "" || os.IsPathSeparator(rest[0])) {
+		if home, err := os.UserHomeDir(); err == nil {
+			return home + rest
+		}
+	}
+	return path
+}
+
+// rescueFile moves the edits in path into the config it's generated from.
+// Returns the project paths when path is a project CLAUDE.md to regenerate.
+func rescueFile(paths *config.Paths, ledger *drift.Ledger, path string) (*config.ProjectPaths, error) {
+	switch {
+	case path == paths.ClaudeMD():
+		return nil, rescueClaudeMD(paths, ledger, path)
+	case isWithin(paths.ClaudeCommandsDir(), path):
+		return nil, rescueAsPersonal(path, paths.ClaudeCommandsDir(), paths.PersonalCommands, "command")
+	case isWithin(paths.ClaudeRulesDir(), path):
+		return nil, rescueAsPersonal(path, paths.ClaudeRulesDir(), paths.PersonalRules, "rule")
+	}
+	if project := projectPathsFor(paths, path); project != nil {
+		return project, rescueProject(ledger, project)
+	}
+	return nil, fmt.Errorf("don't know where to move edits to %s; copy them elsewhere and run 'staghorn rescue --discard'", displayPath(path))
+}
+
+// rescueClaudeMD appends the lines added to ~/.claude/CLAUDE.md to personal.md.
+func rescueClaudeMD(paths *config.Paths, ledger *drift.Ledger, path string) error {
+	original, err := ledger.Original(path)
+	if err != nil {
+		return err
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	added, removed := drift.Edits(string(original), string(current))
+	if len(added) > 0 {
+		existing, _ := os.ReadFile(paths.PersonalMD)
+		if err := os.MkdirAll(paths.ConfigDir, 0755); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+		if err := fsutil.WriteFile(paths.PersonalMD, []byte(appendRescued(string(existing), displayPath(path), added)), 0644); err != nil {
+			return fmt.Errorf("failed to write personal config: %w", err)
+		}
+		printSuccess("Moved %d edited block(s) from %s to %s", len(added), displayPath(path), displayPath(paths.PersonalMD))
+	}
+	if removed > 0 {
+		printWarning("%d line(s) deleted from %s can't be moved; drop team sections with <!-- staghorn:remove \"Section\" --> in personal.md", removed, displayPath(path))
+	}
+	return nil
+}
+
+// appendRescued appends rescued blocks to existing markdown under a marker.
+func appendRescued(existing, from string, blocks []string) string {
+	content := strings.TrimRight(existing, "\n")
+	if content != "" {
+		content += "\n\n"
+	}
+	return content + fmt.Sprintf(rescueMarker, from) + "\n\n" + strings.Join(blocks, "\n\n") + "\n"
+}
+
+// rescueAsPersonal turns an edited Claude command or rule into a personal one,
+// which takes precedence over the team version. An existing personal file is
+// backed up first.
+func rescueAsPersonal(path, claudeDir, personalDir, kind string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(claudeDir, path)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(personalDir, rel)
+
+	if existing, err := os.ReadFile(target); err == nil {
+		if err := fsutil.WriteFile(target+".backup", existing, 0644); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", displayPath(target), err)
+		}
+		printWarning("Backed up the previous personal %s to %s", kind, displayPath(target+".backup"))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create personal %s directory: %w", kind, err)
+	}
+	if err := fsutil.WriteFile(target, []byte(stripManagedHeader(string(content))), 0644); err != nil {
+		return fmt.Errorf("failed to write personal %s: %w", kind, err)
+	}
+	printSuccess("Saved edited %s %s as %s", kind, displayPath(path), displayPath(target))
+	return nil
+}
+
+// stripManagedHeader removes the staghorn managed header line, so a rescued
+// file isn't mistaken for one staghorn generated.
+func stripManagedHeader(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "<!-- Managed by staghorn") {
+			rest := lines[i+1:]
+			if len(rest) > 0 && strings.TrimSpace(rest[0]) == "" {
+				rest = rest[1:]
+			}
+			return strings.Join(lines[:i], "") + strings.Join(rest, "")
+		}
+	}
+	return content
+}
+
+// rescueProject moves edits in a project CLAUDE.md into .staghorn/project.md.
+// If CLAUDE.md is generated from project.md alone and project.md hasn't changed
+// since, the edited file becomes the new project.md; otherwise added lines are
+// appended to it.
+func rescueProject(ledger *drift.Ledger, project *config.ProjectPaths) error {
+	sources, err := loadProjectSources(project)
+	if err != nil {
+		return err
+	}
+	current, err := os.ReadFile(project.OutputMD)
+	if err != nil {
+		return err
+	}
+	expected := sources.render()
+	var updated string
+	if original, err := ledger.Original(project.OutputMD); err == nil && string(original) == expected && sources.isPlain() {
+		updated = strings.TrimSpace(strings.TrimPrefix(string(current), projectHeader)) + "\n"
+	} else {
+		added, _ := drift.Edits(expected, string(current))
+		updated = appendRescued(sources.project, "CLAUDE.md", added)
+	}
+
+	if err := fsutil.WriteFile(project.SourceMD, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write project.md: %w", err)
+	}
+	printSuccess("Moved edits from %s to %s", relativePath(project.OutputMD), relativePath(project.SourceMD))
+	return nil
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncQuietly runs a full sync with its output captured.
+func syncQuietly(t *testing.T) error {
+	t.Helper()
+	_, err := captureStdout(func() error { return runSync(context.Background(), &syncOptions{}) })
+	return err
+}
+
+func TestSync_StopsOnHandEdits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewPaths()
+
+	writeLocalSource(t, map[string]string{
+		"CLAUDE.md":          "## Team\n\nUse tabs.",
+		"commands/review.md": "---\nname: review\ndescription: Review code\n---\nReview it",
+		"rules/security.md":  "Never log secrets.",
+	})
+	require.NoError(t, syncQuietly(t))
+
+	claudeMD := paths.ClaudeMD()
+	command := filepath.Join(paths.ClaudeCommandsDir(), "review.md")
+	original, err := os.ReadFile(claudeMD)
+	require.NoError(t, err)
+
+	// A second sync of untouched files goes through
+	require.NoError(t, syncQuietly(t))
+
+	edited := string(original) + "\n## Mine\n\nKeep replies short.\n"
+	require.NoError(t, os.WriteFile(claudeMD, []byte(edited), 0644))
+	commandContent, err := os.ReadFile(command)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(command, append(commandContent, "Check the tests too.\n"...), 0644))
+
+	err = syncQuietly(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "edited by hand")
+	assert.Contains(t, err.Error(), "CLAUDE.md")
+	assert.Contains(t, err.Error(), "review.md")
+
+	after, err := os.ReadFile(claudeMD)
+	require.NoError(t, err)
+	assert.Equal(t, edited, string(after), "edits must survive the stopped sync")
+
+	// A rules-only sync doesn't touch the edited files
+	_, err = captureStdout(func() error { return runSync(context.Background(), &syncOptions{rulesOnly: true}) })
+	assert.NoError(t, err)
+
+	// Status lists them
+	out, err := captureStdout(func() error { return showStatus(false) })
+	require.NoError(t, err)
+	assert.Contains(t, out, "2 generated file(s) changed by hand")
+
+	// A dry run notes where the real sync would stop
+	plan := dryRunPlan(t)
+	assert.Len(t, plan.Notes, 2)
+
+	_, err = captureStdout(func() error { return runRescue(paths, nil, false) })
+	require.NoError(t, err)
+
+	personal, err := os.ReadFile(paths.PersonalMD)
+	require.NoError(t, err)
+	assert.Contains(t, string(personal), "Rescued from")
+	assert.Contains(t, string(personal), "## Mine\n\nKeep replies short.")
+	assert.NotContains(t, string(personal), "Use tabs.", "only the added lines move")
+
+	personalCommand, err := os.ReadFile(filepath.Join(paths.PersonalCommands, "review.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(personalCommand), "Check the tests too.")
+	assert.NotContains(t, string(personalCommand), "Managed by staghorn")
+
+	// The next sync regenerates everything with the edits in place
+	require.NoError(t, syncQuietly(t))
+	after, err = os.ReadFile(claudeMD)
+	require.NoError(t, err)
+	assert.Contains(t, string(after), "Keep replies short.")
+	after, err = os.ReadFile(command)
+	require.NoError(t, err)
+	assert.Contains(t, string(after), "Source: personal")
+	assert.Contains(t, string(after), "Check the tests too.")
+}
+
+func TestSync_StopsOnHandEditsAfterCacheCleared(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewPaths()
+	writeLocalSource(t, map[string]string{"CLAUDE.md": "## Team\n\nUse tabs."})
+	require.NoError(t, syncQuietly(t))
+
+	require.NoError(t, os.WriteFile(paths.ClaudeMD(), []byte("# Mine\n"), 0644))
+	require.NoError(t, os.RemoveAll(paths.CacheDir))
+
+	err := syncQuietly(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "edited by hand")
+}
+
+func TestRescue_Discard(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewPaths()
+
+	writeLocalSource(t, map[string]string{"CLAUDE.md": "## Team\n\nUse tabs."})
+	require.NoError(t, syncQuietly(t))
+	require.NoError(t, os.WriteFile(paths.ClaudeMD(), []byte("scratch"), 0644))
+	require.Error(t, syncQuietly(t))
+
+	_, err := captureStdout(func() error { return runRescue(paths, []string{"~/.claude/CLAUDE.md"}, true) })
+	require.NoError(t, err)
+	assert.NoFileExists(t, paths.PersonalMD)
+
+	require.NoError(t, syncQuietly(t))
+	after, err := os.ReadFile(paths.ClaudeMD())
+	require.NoError(t, err)
+	assert.Contains(t, string(after), "Use tabs.")
+	assert.NotContains(t, string(after), "scratch")
+
+	err = runRescue(paths, []string{paths.ClaudeMD()}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no hand edits")
+}
+
+func TestRollback_StopsOnHandEdits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewPaths()
+
+	sourceDir := writeLocalSource(t, map[string]string{"CLAUDE.md": "## Team\n\nUse tabs."})
+	require.NoError(t, syncQuietly(t))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "CLAUDE.md"), []byte("## Team\n\nUse spaces."), 0644))
+	require.NoError(t, syncQuietly(t))
+
+	require.NoError(t, os.WriteFile(paths.ClaudeMD(), []byte("scratch"), 0644))
+	_, err := captureStdout(func() error { return runRollback(paths, 0) })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "edited by hand")
+
+	_, err = captureStdout(func() error { return runRescue(paths, nil, true) })
+	require.NoError(t, err)
+	_, err = captureStdout(func() error { return runRollback(paths, 0) })
+	require.NoError(t, err)
+
+	// The restored file is tracked again, so the next sync isn't blocked
+	require.NoError(t, syncQuietly(t))
+}
+
+func TestProjectEdits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := config.NewProjectPaths(t.TempDir())
+	require.NoError(t, os.MkdirAll(project.StaghornDir, 0755))
+	require.NoError(t, os.WriteFile(project.SourceMD, []byte("# Project\n\n- Use make"), 0644))
+	require.NoError(t, generateProjectOutput(project))
+	require.NoError(t, checkProjectEdits(project))
+
+	t.Run("regenerated by a teammate", func(t *testing.T) {
+		source := []byte("# Project\n\n- Use make\n- Use bazel")
+		require.NoError(t, os.WriteFile(project.SourceMD, source, 0644))
+		output, err := projectOutput(project)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(project.OutputMD, []byte(output), 0644))
+		assert.NoError(t, checkProjectEdits(project))
+	})
+
+	t.Run("edited by hand", func(t *testing.T) {
+		require.NoError(t, generateProjectOutput(project))
+		output, err := os.ReadFile(project.OutputMD)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(project.OutputMD, append(output, "- Run the linter\n"...), 0644))
+
+		err = checkProjectEdits(project)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "edited by hand")
+
+		_, err = captureStdout(func() error { return runRescue(config.NewPaths(), nil, false) })
+		require.NoError(t, err)
+
+		source, err := os.ReadFile(project.SourceMD)
+		require.NoError(t, err)
+		assert.Equal(t, "# Project\n\n- Use make\n- Use bazel\n- Run the linter\n", string(source))
+		assert.NoError(t, checkProjectEdits(project))
+		assert.True(t, projectInSync(project))
+	})
+}
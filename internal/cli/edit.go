@@ -143,6 +143,7 @@ func editLanguage(langID string, noApply bool) error {
 	if err := applyConfig(cfg, paths, owner, repo); err != nil {
 		return err
 	}
+	recordGeneration(paths, nil)
 
 	return nil
 }
@@ -204,6 +205,7 @@ func editPersonal(noApply bool) error {
 	if err := applyConfig(cfg, paths, owner, repo); err != nil {
 		return err
 	}
+	recordGeneration(paths, nil)
 
 	return nil
 }
@@ -217,6 +219,13 @@ func editProject(noApply bool) error {
 		return fmt.Errorf("project not initialized\nRun 'staghorn project init' first")
 	}
 
+	// Catch hand edits to ./CLAUDE.md before they're overwritten
+	if !noApply {
+		if err := checkProjectEdits(projectPaths); err != nil {
+			return err
+		}
+	}
+
 	// Open editor
 	if err := openEditor(projectPaths.SourceMD); err != nil {
 		return err
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/HartBrook/staghorn/internal/config"
@@ -470,14 +471,21 @@ func loadEvals() ([]*eval.Eval, error) {
 	if config.Exists() {
 		cfg, err := config.Load()
 		if err == nil {
-			owner, repo, err := cfg.DefaultOwnerRepo()
-			if err == nil {
-				teamEvalsDir := paths.TeamEvalsDir(owner, repo)
-				teamEvals, err := eval.LoadFromDirectory(teamEvalsDir, eval.SourceTeam)
+			var entries map[string]string
+			if cfg.Source.Multi != nil {
+				entries = cfg.Source.Multi.Evals
+			}
+			for _, src := range teamItemSources(cfg, entries, paths.TeamEvalsDir) {
+				teamEvals, err := eval.LoadFromDirectory(src.dir, eval.SourceTeam)
 				if err != nil {
 					warnings = append(warnings, fmt.Sprintf("team evals: %v", err))
 				}
-				allEvals = append(allEvals, teamEvals...)
+				for _, e := range teamEvals {
+					name := strings.TrimSuffix(filepath.Base(e.FilePath), filepath.Ext(e.FilePath))
+					if cfg.Source.RepoForEval(name) == src.repo {
+						allEvals = append(allEvals, e)
+					}
+				}
 			}
 		}
 	}
@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/generation"
+	"github.com/HartBrook/staghorn/internal/lockfile"
+	"github.com/HartBrook/staghorn/internal/manifest"
+	"github.com/HartBrook/staghorn/internal/merge"
+	"github.com/spf13/cobra"
+)
+
+// NewHistoryCmd creates the history command.
+func NewHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "List snapshots of previously applied configs",
+		Long: `Lists the generations recorded each time sync applied config to ~/.claude/.
+
+Each generation is a snapshot of CLAUDE.md and every command, rule, and skill
+staghorn installed, along with the source commits it came from. Use
+'staghorn rollback' to restore one.`,
+		Example: `  staghorn history`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory(config.NewPaths())
+		},
+	}
+}
+
+// NewRollbackCmd creates the rollback command.
+func NewRollbackCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback [generation]",
+		Short: "Restore a previously applied config",
+		Long: `Restores ~/.claude/ to a generation listed by 'staghorn history'.
+Without an argument, rolls back to the generation before the current one.
+
+Rollback doesn't change the cache or your sources, so the next sync applies
+the latest upstream config again. Pin your source to a ref to stay on an older version.`,
+		Example: `  staghorn rollback     # Restore the previous generation
+  staghorn rollback 3   # Restore generation 3`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := 0
+			if len(args) == 1 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n < 1 {
+					return fmt.Errorf("invalid generation %q", args[0])
+				}
+				id = n
+			}
+			return runRollback(config.NewPaths(), id)
+		},
+	}
+}
+
+// newGenerationStore returns the store of generations applied to the Claude directory.
+func newGenerationStore(paths *config.Paths) *generation.Store {
+	return generation.NewStore(paths.GenerationsDir(), filepath.Dir(paths.ClaudeMD()))
+}
+
+// managedClaudeDirs returns the Claude directories sync installs tracked files into.
+func managedClaudeDirs(paths *config.Paths) []string {
+	return []string{paths.ClaudeCommandsDir(), paths.ClaudeRulesDir(), paths.ClaudeSkillsDir()}
+}
+
+// managedClaudeFiles returns the files staghorn manages in the Claude directory,
+// relative to it: the merged CLAUDE.md and every file in the commands, rules and
+// skills manifests.
+func managedClaudeFiles(paths *config.Paths) ([]string, error) {
+	var files []string
+
+	if content, err := os.ReadFile(paths.ClaudeMD()); err == nil && strings.Contains(string(content), merge.HeaderManagedPrefix) {
+		files = append(files, filepath.Base(paths.ClaudeMD()))
+	}
+
+	for _, dir := range managedClaudeDirs(paths) {
+		m, err := manifest.Load(dir)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			continue
+		}
+		for _, rel := range m.Files {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+				continue // Tracked but already gone
+			}
+			files = append(files, filepath.Base(dir)+"/"+rel)
+		}
+	}
+
+	return files, nil
+}
+
+// recordGeneration snapshots the managed Claude files after an apply.
+// Sources come from the repos just synced, or the lockfile when none were.
+func recordGeneration(paths *config.Paths, contexts []*repoContext) {
+	files, err := managedClaudeFiles(paths)
+	if err != nil {
+		printWarning("Failed to record generation: %v", err)
+		return
+	}
+
+	var sources []generation.Source
+	for _, rc := range contexts {
+		sources = append(sources, generation.Source{Repo: rc.fullName(), Ref: rc.branch, Commit: rc.commit})
+	}
+	if len(contexts) == 0 {
+		if lock, err := lockfile.Load(paths.LockFile); err == nil {
+			for _, s := range lock.Sources {
+				sources = append(sources, generation.Source{Repo: s.Repo, Ref: s.Ref, Commit: s.Commit})
+			}
+		}
+	}
+
+	g, err := newGenerationStore(paths).Record(files, sources, "")
+	if err != nil {
+		printWarning("Failed to record generation: %v", err)
+		return
+	}
+	if g != nil {
+		fmt.Printf("  %s Saved as generation %d (undo with 'staghorn rollback')\n", dim("History:"), g.ID)
+	}
+}
+
+func runHistory(paths *config.Paths) error {
+	gens, err := newGenerationStore(paths).List()
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	if len(gens) == 0 {
+		fmt.Println("No generations recorded yet.")
+		fmt.Println()
+		fmt.Printf("  Run %s to apply config and record one.\n", info("staghorn sync"))
+		return nil
+	}
+
+	fmt.Println("Generations (newest first):")
+	fmt.Println()
+	for i := len(gens) - 1; i >= 0; i-- {
+		g := gens[i]
+		line := fmt.Sprintf("  %3d  %s  %d files", g.ID, g.Created.Format("2006-01-02 15:04"), len(g.Files))
+		if sources := formatGenerationSources(g.Sources); sources != "" {
+			line += "  " + dim(sources)
+		}
+		if g.Note != "" {
+			line += "  " + dim("("+g.Note+")")
+		}
+		if i == len(gens)-1 {
+			line += "  " + success("current")
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	fmt.Printf("Restore one with %s\n", info("staghorn rollback <generation>"))
+	return nil
+}
+
+// formatGenerationSources renders sources as repo@ref (short commit).
+func formatGenerationSources(sources []generation.Source) string {
+	parts := make([]string, 0, len(sources))
+	for _, s := range sources {
+		part := s.Repo
+		if s.Ref != "" {
+			part += "@" + s.Ref
+		}
+		if s.Commit != "" {
+			part += " " + lockfile.ShortSHA(s.Commit)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runRollback(paths *config.Paths, id int) error {
+	lock, err := lockStaghorn(paths)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	store := newGenerationStore(paths)
+	gens, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+	if len(gens) == 0 {
+		return fmt.Errorf("no generations recorded; run 'staghorn sync' first")
+	}
+
+	var target *generation.Generation
+	if id == 0 {
+		if len(gens) < 2 {
+			return fmt.Errorf("no earlier generation to roll back to")
+		}
+		target = gens[len(gens)-2]
+	} else {
+		target, err = store.Get(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Rollback replaces the same files a full sync does, so the same edits block it
+	edited, err := syncLocalEdits(paths, &syncOptions{})
+	if err != nil {
+		return err
+	}
+	if len(edited) > 0 {
+		return errors.LocalEdits(displayPaths(edited))
+	}
+
+	current, err := managedClaudeFiles(paths)
+	if err != nil {
+		return err
+	}
+	if err := store.Restore(target, current); err != nil {
+		return fmt.Errorf("failed to restore generation %d: %w", target.ID, err)
+	}
+
+	// Keep manifests in step so the next sync prunes what this generation installed
+	for _, dir := range managedClaudeDirs(paths) {
+		prefix := filepath.Base(dir) + "/"
+		var files []string
+		for _, rel := range target.Files {
+			if name, ok := strings.CutPrefix(rel, prefix); ok {
+				files = append(files, name)
+			}
+		}
+		if prev, _ := manifest.Load(dir); prev == nil && len(files) == 0 {
+			continue
+		}
+		if err := (&manifest.Manifest{Files: files}).Save(dir); err != nil {
+			return fmt.Errorf("failed to update manifest in %s: %w", dir, err)
+		}
+	}
+
+	// What was restored is staghorn's output, so later edits to it are caught
+	claudeRoot := filepath.Dir(paths.ClaudeMD())
+	restored := make(map[string][]byte)
+	for _, rel := range target.Files {
+		path := filepath.Join(claudeRoot, filepath.FromSlash(rel))
+		if !syncWrites(paths, &syncOptions{}, path) {
+			continue
+		}
+		if content, err := os.ReadFile(path); err == nil {
+			restored[path] = content
+		}
+	}
+	recordWrites(paths, restored)
+
+	g, err := store.Record(target.Files, target.Sources, fmt.Sprintf("rollback to %d", target.ID))
+	if err != nil {
+		printWarning("Failed to record generation: %v", err)
+	}
+
+	printSuccess("Rolled back to generation %d (%d files)", target.ID, len(target.Files))
+	if sources := formatGenerationSources(target.Sources); sources != "" {
+		printInfo("Sources", sources)
+	}
+	if g != nil {
+		printInfo("Saved as", fmt.Sprintf("generation %d", g.ID))
+	}
+	fmt.Printf("  %s The next 'staghorn sync' reapplies the latest upstream config\n", dim("Note:"))
+	return nil
+}
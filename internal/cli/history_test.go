@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollback(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	paths := config.NewPaths()
+
+	sourceDir := writeLocalSource(t, map[string]string{
+		"CLAUDE.md":          "## Team\n\nUse tabs.",
+		"commands/review.md": "---\nname: review\ndescription: Review code\n---\nReview it",
+	})
+	require.NoError(t, runSync(context.Background(), &syncOptions{}))
+
+	// A bad update upstream: new wording and an extra command
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "CLAUDE.md"), []byte("## Team\n\nUse spaces."), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "commands", "debug.md"), []byte("---\nname: debug\ndescription: Debug\n---\nDebug it"), 0644))
+	require.NoError(t, runSync(context.Background(), &syncOptions{}))
+
+	gens, err := newGenerationStore(paths).List()
+	require.NoError(t, err)
+	require.Len(t, gens, 2)
+	assert.Contains(t, gens[1].Files, "commands/debug.md")
+
+	// Syncing again without upstream changes doesn't add a generation
+	require.NoError(t, runSync(context.Background(), &syncOptions{force: true}))
+	gens, err = newGenerationStore(paths).List()
+	require.NoError(t, err)
+	require.Len(t, gens, 2)
+
+	require.NoError(t, runRollback(paths, 0))
+
+	claudeMD, err := os.ReadFile(paths.ClaudeMD())
+	require.NoError(t, err)
+	assert.Contains(t, string(claudeMD), "Use tabs.")
+	assert.FileExists(t, filepath.Join(paths.ClaudeCommandsDir(), "review.md"))
+	assert.NoFileExists(t, filepath.Join(paths.ClaudeCommandsDir(), "debug.md"))
+
+	m, err := manifest.Load(paths.ClaudeCommandsDir())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"review.md"}, m.Files)
+
+	gens, err = newGenerationStore(paths).List()
+	require.NoError(t, err)
+	require.Len(t, gens, 3)
+	assert.Equal(t, "rollback to 1", gens[2].Note)
+
+	// Rolling back again undoes the rollback
+	require.NoError(t, runRollback(paths, 0))
+	claudeMD, err = os.ReadFile(paths.ClaudeMD())
+	require.NoError(t, err)
+	assert.Contains(t, string(claudeMD), "Use spaces.")
+	assert.FileExists(t, filepath.Join(paths.ClaudeCommandsDir(), "debug.md"))
+}
+
+func TestRollback_NoHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewPaths()
+
+	err := runRollback(paths, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no generations recorded")
+}
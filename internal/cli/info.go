@@ -12,6 +12,7 @@ import (
 	"github.com/HartBrook/staghorn/internal/config"
 	"github.com/HartBrook/staghorn/internal/github"
 	"github.com/HartBrook/staghorn/internal/language"
+	"github.com/HartBrook/staghorn/internal/lockfile"
 	"github.com/HartBrook/staghorn/internal/merge"
 	"github.com/HartBrook/staghorn/internal/optimize"
 	"github.com/HartBrook/staghorn/internal/skills"
@@ -31,8 +32,9 @@ func NewInfoCmd() *cobra.Command {
 	opts := &infoOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "info",
-		Short: "Show current config state",
+		Use:     "info",
+		Aliases: []string{"status"},
+		Short:   "Show current config state",
 		Long: `Displays information about your staghorn configuration.
 
 By default, shows a compact status overview. Use flags to customize output.`,
@@ -102,9 +104,8 @@ func showContent(opts *infoOptions) error {
 				return fmt.Errorf("team CLAUDE.md not found: %w", err)
 			}
 		} else {
-			// Read from cache
-			c := cache.New(paths)
-			teamContent, _, err := c.Read(owner, repo)
+			// Read from cache, one layer per base repo
+			teamLayers, err := loadTeamLayers(cfg, paths)
 			if err != nil {
 				if layer == "team" {
 					return err
@@ -112,10 +113,7 @@ func showContent(opts *infoOptions) error {
 				// For "all", continue without team layer
 				printWarning("Team config not cached, run `staghorn sync` to fetch")
 			} else {
-				layers = append(layers, merge.Layer{
-					Content: teamContent,
-					Source:  "team",
-				})
+				layers = append(layers, teamLayers...)
 			}
 		}
 	}
@@ -184,7 +182,7 @@ func showContent(opts *infoOptions) error {
 	// Merge and output
 	mergeOpts := merge.MergeOptions{
 		AnnotateSources: opts.sources,
-		SourceRepo:      fmt.Sprintf("%s/%s", owner, repo),
+		SourceRepo:      cfg.SourceRepo(),
 		Languages:       activeLanguages,
 		LanguageFiles:   languageFiles,
 	}
@@ -192,9 +190,32 @@ func showContent(opts *infoOptions) error {
 	output := merge.MergeWithLanguages(layers, mergeOpts)
 	fmt.Println(output)
 
+	// List sections that personal or project directives replaced or removed
+	if opts.sources {
+		printOverrides(merge.ParseOverrides(output))
+	}
+
 	return nil
 }
 
+// printOverrides lists sections changed by staghorn:replace/remove directives.
+func printOverrides(overrides []merge.Override) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(dim("Overrides:"))
+	for _, o := range overrides {
+		switch o.Action {
+		case merge.OverrideBlocked:
+			fmt.Printf("  %s %s\n", o.Section, warning(fmt.Sprintf("locked, %s directive ignored", o.Source)))
+		default:
+			fmt.Printf("  %s %s\n", o.Section, dim(fmt.Sprintf("%s by %s", o.Action, o.Source)))
+		}
+	}
+}
+
 // showStatus displays the configuration state (replaces `status` command)
 func showStatus(verbose bool) error {
 	paths := config.NewPaths()
@@ -232,9 +253,13 @@ func showCompactStatus(cfg *config.Config, paths *config.Paths, owner, repo stri
 
 	// Team status
 	sourceStatus := warning("not synced")
+	sourceLabel := cfg.SourceRepo()
 	if c.Exists(owner, repo) {
 		meta, err := c.GetMetadata(owner, repo)
 		if err == nil {
+			if spec, err := cfg.DefaultRepoSpec(); err == nil && meta.Ref != "" {
+				sourceLabel = fmt.Sprintf("%s@%s", spec.FullName(), meta.Ref)
+			}
 			if meta.IsStale(cfg.Cache.TTLDuration()) {
 				sourceStatus = fmt.Sprintf("%s %s", meta.Age(), warning("(stale)"))
 			} else {
@@ -316,14 +341,23 @@ func showCompactStatus(cfg *config.Config, paths *config.Paths, owner, repo stri
 		tokenStatus = warning(tokenStatus)
 	}
 
+	// Generated files edited by hand
+	var edited []string
+	if ledger, err := loadLedger(paths); err == nil {
+		edited = localEdits(paths, ledger)
+	}
+
 	// Output
-	fmt.Printf("  %s: %s/%s (%s)\n", dim("Source"), owner, repo, sourceStatus)
+	fmt.Printf("  %s: %s (%s)\n", dim("Source"), sourceLabel, sourceStatus)
 	fmt.Printf("  %s: %s\n", dim("Personal"), personalStatus)
 	fmt.Printf("  %s: %s\n", dim("Project"), projectStatus)
 	fmt.Printf("  %s: %s\n", dim("Languages"), langStatus)
 	fmt.Printf("  %s: %s\n", dim("Commands"), cmdStatus)
 	fmt.Printf("  %s: %s\n", dim("Skills"), skillStatus)
 	fmt.Printf("  %s: %s\n", dim("Size"), tokenStatus)
+	if len(edited) > 0 {
+		fmt.Printf("  %s: %s\n", dim("Edited"), warning(fmt.Sprintf("%d generated file(s) changed by hand", len(edited))))
+	}
 
 	// Suggest optimization if large
 	if mergedTokens > 3000 {
@@ -331,13 +365,32 @@ func showCompactStatus(cfg *config.Config, paths *config.Paths, owner, repo stri
 		fmt.Printf("  %s Config exceeds 3,000 tokens. Consider running %s.\n", warningIcon, info("staghorn optimize"))
 	}
 
+	if len(edited) > 0 {
+		fmt.Println()
+		for _, path := range edited {
+			fmt.Printf("  %s %s\n", warningIcon, displayPath(path))
+		}
+		fmt.Printf("  Sync won't overwrite these. Run %s to keep the edits.\n", info("staghorn rescue"))
+	}
+
 	return nil
 }
 
 func showVerboseStatus(cfg *config.Config, paths *config.Paths, owner, repo string) error {
 	fmt.Println("Source config:")
-	printInfo("Repository", fmt.Sprintf("%s/%s", owner, repo))
-	printInfo("Path", config.DefaultPath)
+	spec, err := cfg.DefaultRepoSpec()
+	if err == nil {
+		printInfo("Repository", spec.FullName())
+		printInfo("Path", spec.SourcePath(config.DefaultPath))
+	} else {
+		printInfo("Repository", fmt.Sprintf("%s/%s", owner, repo))
+		printInfo("Path", config.DefaultPath)
+	}
+	if err == nil && spec.IsPinned() {
+		printInfo("Pinned ref", spec.Ref)
+	} else {
+		printInfo("Pinned ref", dim("none (default branch)"))
+	}
 
 	// Cache status
 	c := cache.New(paths)
@@ -345,6 +398,12 @@ func showVerboseStatus(cfg *config.Config, paths *config.Paths, owner, repo stri
 	if c.Exists(owner, repo) {
 		meta, err := c.GetMetadata(owner, repo)
 		if err == nil {
+			if meta.Ref != "" {
+				printInfo("Synced ref", meta.Ref)
+			}
+			if meta.SHA != "" {
+				printInfo("SHA", lockfile.ShortSHA(meta.SHA))
+			}
 			stale := meta.IsStale(cfg.Cache.TTLDuration())
 			ageStr := meta.Age()
 			if stale {
@@ -415,6 +474,18 @@ func showVerboseStatus(cfg *config.Config, paths *config.Paths, owner, repo stri
 		}
 	}
 
+	// Generated files edited by hand
+	if ledger, err := loadLedger(paths); err == nil {
+		if edited := localEdits(paths, ledger); len(edited) > 0 {
+			fmt.Println()
+			fmt.Println("Hand edits:")
+			for _, path := range edited {
+				fmt.Printf("  %s %s\n", warningIcon, displayPath(path))
+			}
+			fmt.Printf("          Run %s to move them into your own config.\n", info("staghorn rescue"))
+		}
+	}
+
 	// Auth status
 	fmt.Println()
 	fmt.Println("Authentication:")
@@ -466,10 +537,9 @@ func findProjectConfig() string {
 func calculateMergedTokens(cfg *config.Config, paths *config.Paths, owner, repo string, activeLanguages []string) int {
 	var layers []merge.Layer
 
-	// Team layer
-	c := cache.New(paths)
-	if teamContent, _, err := c.Read(owner, repo); err == nil && teamContent != "" {
-		layers = append(layers, merge.Layer{Content: teamContent, Source: "team"})
+	// Team layers
+	if teamLayers, err := loadTeamLayers(cfg, paths); err == nil {
+		layers = append(layers, teamLayers...)
 	}
 
 	// Personal layer
@@ -502,7 +572,7 @@ func calculateMergedTokens(cfg *config.Config, paths *config.Paths, owner, repo
 	}
 
 	mergeOpts := merge.MergeOptions{
-		SourceRepo:    fmt.Sprintf("%s/%s", owner, repo),
+		SourceRepo:    cfg.SourceRepo(),
 		Languages:     activeLanguages,
 		LanguageFiles: languageFiles,
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/HartBrook/staghorn/internal/errors"
 	"github.com/HartBrook/staghorn/internal/github"
 	"github.com/HartBrook/staghorn/internal/language"
+	"github.com/HartBrook/staghorn/internal/provider"
 	"github.com/HartBrook/staghorn/internal/starter"
 	"github.com/spf13/cobra"
 )
@@ -23,7 +24,7 @@ const (
 
 // NewInitCmd creates the init command.
 func NewInitCmd() *cobra.Command {
-	var fromRepo string
+	var fromRepo, host string
 
 	cmd := &cobra.Command{
 		Use:   "init",
@@ -39,22 +40,30 @@ You can also install directly from a specific repository:
   staghorn init --from owner/repo`,
 		Example: `  staghorn init
   staghorn init --from staghorn-io/python-standards
-  staghorn init --from https://github.com/acme/claude-standards`,
+  staghorn init --from acme/claude-standards@v1.4.0
+  staghorn init --from https://github.com/acme/claude-standards
+  staghorn init --from acme/monorepo//platform/ai-standards
+  staghorn init --host ghe.example.com --from acme/claude-standards`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if host == "" {
+				host = existingGitHubHost()
+			}
 			if fromRepo != "" {
-				return runInitFrom(cmd.Context(), fromRepo)
+				return runInitFrom(cmd.Context(), fromRepo, host)
 			}
-			return runInit(cmd, args)
+			return runInit(cmd, host)
 		},
 	}
 
 	cmd.Flags().StringVar(&fromRepo, "from", "", "Install directly from owner/repo")
+	cmd.Flags().StringVar(&host, "host", "", "GitHub Enterprise Server hostname (default: github.com)")
 
 	return cmd
 }
 
 // runInitFrom handles direct installation from a specific repo.
-func runInitFrom(ctx context.Context, repoStr string) error {
+// host is the GitHub Enterprise host for unprefixed repos ("" for github.com).
+func runInitFrom(ctx context.Context, repoStr, host string) error {
 	paths := config.NewPaths()
 
 	// Check if already configured
@@ -68,16 +77,25 @@ func runInitFrom(ctx context.Context, repoStr string) error {
 		fmt.Println()
 	}
 
-	// Parse and validate repo
-	owner, repo, err := config.ParseRepo(repoStr)
+	// A relative local path is relative to here, but config resolves it against its own directory
+	if wd, err := os.Getwd(); err == nil {
+		repoStr = config.ResolveLocalPath(repoStr, wd)
+	}
+
+	// A URL on the GitHub Enterprise host is a GitHub source
+	repoStr = config.ResolveGitHubURL(repoStr, host)
+
+	// Parse and validate repo (an optional @ref pins the source)
+	spec, err := config.ParseRepoSpec(repoStr)
 	if err != nil {
 		return err
 	}
 
-	fullRepo := owner + "/" + repo
+	fullRepo := spec.FullName()
 
-	// Try to create a client (prefer authenticated, fall back to unauthenticated for public repos)
-	client, err := createClient()
+	// Prefer an authenticated GitHub client, falling back to unauthenticated for public repos
+	factory := &provider.Factory{GitHub: createClient, GitHubHost: host, GitDir: paths.GitCacheDir()}
+	p, err := factory.For(spec)
 	if err != nil {
 		return err
 	}
@@ -85,7 +103,7 @@ func runInitFrom(ctx context.Context, repoStr string) error {
 	fmt.Printf("Verifying access to %s...\n", fullRepo)
 
 	// Check if repo exists
-	exists, err := client.RepoExists(ctx, owner, repo)
+	exists, err := p.RepoExists(ctx, spec.Owner, spec.Repo)
 	if err != nil {
 		return errors.GitHubFetchFailed(fullRepo, err)
 	}
@@ -94,23 +112,23 @@ func runInitFrom(ctx context.Context, repoStr string) error {
 	}
 
 	// Check if CLAUDE.md exists
-	fileExists, err := client.FileExists(ctx, owner, repo, config.DefaultPath, "")
-	if err != nil {
-		printWarning("Could not verify %s exists: %v", config.DefaultPath, err)
-	} else if !fileExists {
-		printWarning("%s not found in repository", config.DefaultPath)
+	if _, err := p.FetchFile(ctx, spec.Owner, spec.Repo, spec.SourcePath(config.DefaultPath), spec.Ref); provider.IsNotFound(err) {
+		printWarning("%s not found in repository", spec.SourcePath(config.DefaultPath))
 		if !promptYesNo("Continue anyway?") {
 			return nil
 		}
+	} else if err != nil {
+		printWarning("Could not verify %s exists: %v", config.DefaultPath, err)
 	}
 
 	printSuccess("Repository verified")
 
 	// Check trust and warn if needed
-	cfg := config.NewSimpleConfig(fullRepo)
+	cfg := config.NewSimpleConfig(spec.String())
+	cfg.GitHub.Host = host
 	if !cfg.IsTrustedSource(fullRepo) {
 		fmt.Println()
-		fmt.Println(config.TrustWarning(fullRepo))
+		fmt.Println(config.TrustWarning(fullRepo, spec.WebURL(host)))
 		if !promptYesNo("Proceed with installation?") {
 			return nil
 		}
@@ -139,6 +157,7 @@ func runInitFrom(ctx context.Context, repoStr string) error {
 	}
 
 	// Offer language selection BEFORE applying (so sync respects the selection)
+	owner, repo := spec.CacheKey()
 	offerLanguageConfigs(paths, owner, repo)
 
 	// Offer starter commands
@@ -156,7 +175,7 @@ func runInitFrom(ctx context.Context, repoStr string) error {
 	return nil
 }
 
-func runInit(cmd *cobra.Command, args []string) error {
+func runInit(cmd *cobra.Command, host string) error {
 	paths := config.NewPaths()
 
 	// Check if already configured
@@ -183,9 +202,9 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	switch choice {
 	case "1", "":
-		return initFromPublic(cmd.Context(), paths)
+		return initFromPublic(cmd.Context(), paths, host)
 	case "2":
-		return initFromRepo(cmd.Context(), paths)
+		return initFromRepo(cmd.Context(), paths, host)
 	case "3":
 		return initFresh(paths)
 	default:
@@ -194,18 +213,13 @@ func runInit(cmd *cobra.Command, args []string) error {
 }
 
 // initFromPublic handles browsing and selecting a public config.
-func initFromPublic(ctx context.Context, paths *config.Paths) error {
+func initFromPublic(ctx context.Context, paths *config.Paths, host string) error {
 	fmt.Println()
 	fmt.Println("Searching for public configs...")
 
-	// Try unauthenticated first for public repos
-	client, err := github.NewUnauthenticatedClient()
+	client, err := newSearchClient(host)
 	if err != nil {
-		// Fall back to authenticated
-		client, err = github.NewClient()
-		if err != nil {
-			return fmt.Errorf("failed to create GitHub client: %w", err)
-		}
+		return err
 	}
 
 	results, err := client.SearchConfigs(ctx, "")
@@ -263,7 +277,7 @@ func initFromPublic(ctx context.Context, paths *config.Paths) error {
 			return fmt.Errorf("invalid selection: %d", selectedIdx)
 		}
 		selected := results[selectedIdx-1]
-		return runInitFrom(ctx, selected.FullName())
+		return runInitFrom(ctx, selected.FullName(), host)
 	}
 
 	// Treat as search query
@@ -295,7 +309,7 @@ func initFromPublic(ctx context.Context, paths *config.Paths) error {
 		if _, err := fmt.Sscanf(input, "%d", &selectedIdx); err == nil {
 			if selectedIdx >= 1 && selectedIdx <= displayCount {
 				selected := results[selectedIdx-1]
-				return runInitFrom(ctx, selected.FullName())
+				return runInitFrom(ctx, selected.FullName(), host)
 			}
 		}
 		return fmt.Errorf("invalid selection: please enter a number between 1 and %d", displayCount)
@@ -305,7 +319,7 @@ func initFromPublic(ctx context.Context, paths *config.Paths) error {
 }
 
 // initFromRepo handles connecting to any repository (public or private).
-func initFromRepo(ctx context.Context, paths *config.Paths) error {
+func initFromRepo(ctx context.Context, paths *config.Paths, host string) error {
 	fmt.Println()
 	repoURL := promptString("Repository (e.g., owner/repo or https://github.com/owner/repo):")
 	if repoURL == "" {
@@ -313,7 +327,7 @@ func initFromRepo(ctx context.Context, paths *config.Paths) error {
 	}
 
 	// Use runInitFrom which handles both public and private repos
-	return runInitFrom(ctx, repoURL)
+	return runInitFrom(ctx, repoURL, host)
 }
 
 // initFresh sets up staghorn with just starter commands, no remote source.
@@ -365,21 +379,47 @@ func initFresh(paths *config.Paths) error {
 
 // Helper functions
 
-func createClient() (*github.Client, error) {
+func createClient(host string) (*github.Client, error) {
 	// Try authenticated first
-	client, err := github.NewClient()
+	client, err := github.NewClientForHost(host)
 	if err == nil {
 		return client, nil
 	}
 
 	// Try with token from env
-	token := github.GetTokenFromEnv()
+	token := github.GetTokenFromEnvForHost(host)
 	if token != "" {
-		return github.NewClientWithToken(token)
+		return github.NewClientWithTokenForHost(host, token)
 	}
 
 	// Fall back to unauthenticated for public repos
-	return github.NewUnauthenticatedClient()
+	return github.NewUnauthenticatedClientForHost(host)
+}
+
+// newSearchClient creates a client for searching public configs on host,
+// trying unauthenticated access first.
+func newSearchClient(host string) (*github.Client, error) {
+	client, err := github.NewUnauthenticatedClientForHost(host)
+	if err != nil {
+		// Fall back to authenticated
+		client, err = github.NewClientForHost(host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// existingGitHubHost returns github.host from the current config, if any.
+func existingGitHubHost() string {
+	if !config.Exists() {
+		return ""
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.GitHubHost()
 }
 
 func offerStarterCommands(paths *config.Paths) {
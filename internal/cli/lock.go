@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/fsutil"
+)
+
+// lockTimeout is how long a command waits for another staghorn process to finish.
+var lockTimeout = 2 * time.Minute
+
+// lockStaghorn takes the advisory lock guarding the staghorn config and cache
+// directories, waiting for another running staghorn process to finish first.
+func lockStaghorn(paths *config.Paths) (*fsutil.Lock, error) {
+	lock, err := fsutil.Acquire(paths.RunLockFile(), lockTimeout, func(holder int) {
+		if holder > 0 {
+			fmt.Printf("Waiting for another staghorn process (pid %d) to finish...\n", holder)
+		} else {
+			fmt.Println("Waiting for another staghorn process to finish...")
+		}
+	})
+	if err != nil {
+		return nil, errors.Busy(err)
+	}
+	return lock, nil
+}
+
+// recoverInterruptedRun removes temp files left by writes that never finished
+// and reports whether the previous sync was interrupted. Call with the lock held.
+func recoverInterruptedRun(paths *config.Paths) bool {
+	dirs := []string{paths.CacheDir, paths.ConfigDir}
+	dirs = append(dirs, managedClaudeDirs(paths)...)
+	for _, dir := range dirs {
+		_, _ = fsutil.RemoveTemp(dir) // Leftovers are harmless, just clutter
+	}
+
+	// The Claude directory holds far more than staghorn's files, so only look beside CLAUDE.md
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(paths.ClaudeMD()), "*"))
+	for _, path := range matches {
+		if fsutil.IsTemp(path) {
+			_ = os.Remove(path)
+		}
+	}
+
+	_, err := os.Stat(paths.SyncMarkerFile())
+	return err == nil
+}
+
+// beginSync marks a sync as in progress until finishSync is called.
+func beginSync(paths *config.Paths) error {
+	if err := os.MkdirAll(paths.CacheDir, 0755); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(paths.SyncMarkerFile(), []byte(strconv.Itoa(os.Getpid())+"\n"), 0644)
+}
+
+// finishSync clears the in-progress marker once a sync returns, whether or not it succeeded.
+func finishSync(paths *config.Paths) error {
+	if err := os.Remove(paths.SyncMarkerFile()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// isRunState reports whether path is the run lock or in-progress marker, which
+// describe the running process rather than synced content.
+func isRunState(paths *config.Paths, path string) bool {
+	return path == paths.RunLockFile() || path == paths.SyncMarkerFile()
+}
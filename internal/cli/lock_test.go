@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/fsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSync_RecoversInterruptedRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewPaths()
+
+	writeLocalSource(t, map[string]string{
+		"CLAUDE.md":          "## Team\n\nUse tabs.",
+		"commands/review.md": "---\nname: review\ndescription: Review code\n---\nReview it",
+	})
+	require.NoError(t, runSync(context.Background(), &syncOptions{}))
+	assert.NoFileExists(t, paths.SyncMarkerFile())
+
+	// Simulate a sync killed mid-write: marker still present, temp files beside targets
+	require.NoError(t, beginSync(paths))
+	var leftovers []string
+	for _, path := range []string{paths.ClaudeMD(), filepath.Join(paths.ClaudeCommandsDir(), "review.md")} {
+		tmp, err := fsutil.CreateTemp(path)
+		require.NoError(t, err)
+		require.NoError(t, tmp.Close())
+		leftovers = append(leftovers, tmp.Name())
+	}
+
+	out, err := captureStdout(func() error {
+		return runSync(context.Background(), &syncOptions{})
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "previous sync did not finish")
+
+	for _, path := range leftovers {
+		assert.NoFileExists(t, path)
+	}
+	assert.NoFileExists(t, paths.SyncMarkerFile())
+}
+
+func TestRunSync_ClearsMarkerOnError(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewPaths()
+	writeLocalSource(t, map[string]string{"CLAUDE.md": "## Team\n\nUse tabs."})
+
+	// Nothing is cached yet, so applying fails after the sync has started
+	_, err := captureStdout(func() error {
+		return runSync(context.Background(), &syncOptions{applyOnly: true})
+	})
+	require.Error(t, err)
+	assert.NoFileExists(t, paths.SyncMarkerFile())
+
+	out, err := captureStdout(func() error {
+		return runSync(context.Background(), &syncOptions{})
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, out, "previous sync did not finish")
+}
+
+func TestRunSync_WaitsForLock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewPaths()
+	writeLocalSource(t, map[string]string{"CLAUDE.md": "## Team\n\nUse tabs."})
+
+	held, err := fsutil.Acquire(paths.RunLockFile(), time.Second, nil)
+	require.NoError(t, err)
+	defer func() { _ = held.Release() }()
+
+	prev := lockTimeout
+	lockTimeout = 200 * time.Millisecond
+	defer func() { lockTimeout = prev }()
+
+	out, err := captureStdout(func() error {
+		return runSync(context.Background(), &syncOptions{})
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "another staghorn process")
+	assert.Contains(t, out, "Waiting for another staghorn process")
+	assert.NoFileExists(t, paths.ClaudeMD())
+}
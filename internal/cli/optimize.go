@@ -8,6 +8,7 @@ import (
 
 	"github.com/HartBrook/staghorn/internal/cache"
 	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/fsutil"
 	"github.com/HartBrook/staghorn/internal/language"
 	"github.com/HartBrook/staghorn/internal/merge"
 	"github.com/HartBrook/staghorn/internal/optimize"
@@ -225,13 +226,9 @@ func getContentToOptimize(cfg *config.Config, paths *config.Paths, owner, repo,
 		// Build merged content
 		var layers []merge.Layer
 
-		// Team layer
-		teamContent, _, err := c.Read(owner, repo)
-		if err == nil && teamContent != "" {
-			layers = append(layers, merge.Layer{
-				Content: teamContent,
-				Source:  "team",
-			})
+		// Team layers
+		if teamLayers, err := loadTeamLayers(cfg, paths); err == nil {
+			layers = append(layers, teamLayers...)
 		}
 
 		// Personal layer
@@ -266,7 +263,7 @@ func getContentToOptimize(cfg *config.Config, paths *config.Paths, owner, repo,
 		}
 
 		mergeOpts := merge.MergeOptions{
-			SourceRepo:    fmt.Sprintf("%s/%s", owner, repo),
+			SourceRepo:    cfg.SourceRepo(),
 			Languages:     activeLanguages,
 			LanguageFiles: languageFiles,
 		}
@@ -290,7 +287,7 @@ func applyOptimization(paths *config.Paths, owner, repo, layer, content, project
 		// Check if we're in a source repo
 		if isSourceRepo {
 			sourcePaths := config.NewSourceRepoPaths(projectRoot)
-			if err := os.WriteFile(sourcePaths.ClaudeMD, []byte(content), config.DefaultFileMode); err != nil {
+			if err := fsutil.WriteFile(sourcePaths.ClaudeMD, []byte(content), config.DefaultFileMode); err != nil {
 				return fmt.Errorf("failed to write CLAUDE.md: %w", err)
 			}
 			return nil
@@ -317,7 +314,7 @@ func applyOptimization(paths *config.Paths, owner, repo, layer, content, project
 
 	case "personal":
 		// Update the personal.md file
-		if err := os.WriteFile(paths.PersonalMD, []byte(content), config.DefaultFileMode); err != nil {
+		if err := fsutil.WriteFile(paths.PersonalMD, []byte(content), config.DefaultFileMode); err != nil {
 			return fmt.Errorf("failed to write personal config: %w", err)
 		}
 		return nil
@@ -328,7 +325,7 @@ func applyOptimization(paths *config.Paths, owner, repo, layer, content, project
 			return fmt.Errorf("cannot apply to project layer: not in a project directory")
 		}
 		projectPaths := config.NewProjectPaths(projectRoot)
-		if err := os.WriteFile(projectPaths.SourceMD, []byte(content), config.DefaultFileMode); err != nil {
+		if err := fsutil.WriteFile(projectPaths.SourceMD, []byte(content), config.DefaultFileMode); err != nil {
 			return fmt.Errorf("failed to write project config: %w", err)
 		}
 		return nil
@@ -0,0 +1,311 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/HartBrook/staghorn/internal/cache"
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/lockfile"
+	"github.com/HartBrook/staghorn/internal/merge"
+	"github.com/HartBrook/staghorn/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// maxListedCommits bounds how many commit messages are printed per source.
+const maxListedCommits = 10
+
+// NewOutdatedCmd creates the outdated command.
+func NewOutdatedCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "outdated",
+		Short: "Check sources for upstream changes since the last sync",
+		Long: `Compares the commit each configured source was last synced at with the
+commit its branch (or pinned ref) points to upstream, without fetching any files.
+
+Exits with status 1 if any source has changed and 2 if any source couldn't
+be checked, so scripts and CI can tell the two apart.`,
+		Example: `  staghorn outdated`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runOutdated(cmd.Context(), config.NewPaths())
+		},
+	}
+}
+
+// sourceStatus is how a configured source compares with upstream.
+type sourceStatus struct {
+	name       string
+	local      bool              // Local sources are read on every sync, so never outdated
+	ref        string            // Pinned ref or default branch
+	synced     string            // Commit last synced (empty if unknown)
+	neverSync  bool              // Source has never been synced
+	refChanged string            // Previously synced ref, if the pinned ref has changed since
+	upstream   string            // Commit the ref points to upstream
+	commits    []provider.Commit // New upstream commits (nil if the provider can't list them)
+	err        error
+}
+
+// isOutdated reports whether a sync would fetch a different commit.
+func (s *sourceStatus) isOutdated() bool {
+	if s.err != nil || s.local || s.upstream == "" {
+		return false
+	}
+	return s.neverSync || s.refChanged != "" || s.synced != s.upstream
+}
+
+func runOutdated(ctx context.Context, paths *config.Paths) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	c := cache.New(paths)
+	lock, _ := lockfile.Load(paths.LockFile) // No lockfile until the first full sync
+	factory := newProviderFactory(cfg, paths)
+
+	repos := cfg.Source.AllRepos()
+	statuses := make([]*sourceStatus, len(repos))
+	runConcurrently(len(repos), cfg.SyncConcurrency(), func(i int) {
+		statuses[i] = checkSource(ctx, factory, c, lock, repos[i])
+	})
+
+	fmt.Printf("Checked %d source(s)\n\n", len(statuses))
+	outdated, failed := 0, 0
+	for _, s := range statuses {
+		printSourceStatus(s)
+		if s.err != nil {
+			failed++
+		}
+		if s.isOutdated() {
+			outdated++
+		}
+	}
+
+	// A failed check can hide changes, so it outranks the ones found
+	if failed > 0 {
+		fmt.Println()
+		return errors.CheckFailed(failed, outdated)
+	}
+	if outdated > 0 {
+		fmt.Println()
+		return errors.UpdatesAvailable(outdated)
+	}
+	fmt.Println()
+	printSuccess("All sources are up to date")
+	return nil
+}
+
+// checkSource resolves a source's ref upstream and compares it with the last sync.
+func checkSource(ctx context.Context, factory *provider.Factory, c *cache.Cache, lock *lockfile.Lockfile, repoStr string) *sourceStatus {
+	s := &sourceStatus{name: repoStr}
+
+	spec, err := config.ParseRepoSpec(repoStr)
+	if err != nil {
+		s.err = fmt.Errorf("invalid repo %s: %w", repoStr, err)
+		return s
+	}
+	s.name = spec.FullName()
+	if spec.Provider == config.ProviderLocal {
+		s.local = true
+		return s
+	}
+
+	p, err := factory.For(spec)
+	if err != nil {
+		s.err = err
+		return s
+	}
+	compareSource(ctx, p, spec, c, lock, s)
+	return s
+}
+
+// compareSource fills in s with the upstream and last synced commits of spec.
+func compareSource(ctx context.Context, p provider.SourceProvider, spec *config.RepoSpec, c *cache.Cache, lock *lockfile.Lockfile, s *sourceStatus) {
+	resolver, ok := p.(provider.CommitResolver)
+	if !ok {
+		s.err = fmt.Errorf("%s can't resolve commits", spec.String())
+		return
+	}
+
+	s.ref, s.err = resolveRef(ctx, p, spec)
+	if s.err != nil {
+		return
+	}
+	s.upstream, s.err = resolver.ResolveCommit(ctx, spec.Owner, spec.Repo, s.ref)
+	if s.err != nil {
+		return
+	}
+
+	syncedRef, synced, ok := lastSynced(c, lock, spec)
+	if !ok {
+		s.neverSync = true
+		return
+	}
+	s.synced = synced
+	if spec.IsPinned() && syncedRef != spec.Ref {
+		s.refChanged = syncedRef
+	}
+
+	if s.synced != "" && s.synced != s.upstream {
+		if lister, ok := p.(provider.CommitLister); ok {
+			// Unlisted commits (e.g. after a force push) still count as outdated
+			s.commits, _ = lister.CompareCommits(ctx, spec.Owner, spec.Repo, s.synced, s.upstream)
+		}
+	}
+}
+
+// lastSynced returns the ref and commit a source was last synced at. Cache
+// metadata is preferred; sources that only provide commands, languages, or
+// skills have no cached config, so the lockfile is checked next. The commit
+// is empty for caches written before commits were recorded.
+func lastSynced(c *cache.Cache, lock *lockfile.Lockfile, spec *config.RepoSpec) (ref, commit string, ok bool) {
+	owner, repo := spec.CacheKey()
+	meta, err := c.GetMetadata(owner, repo)
+	if err == nil && meta.Commit != "" {
+		return meta.Ref, meta.Commit, true
+	}
+	if lock != nil {
+		if locked := lock.FindSource(spec.FullName()); locked != nil {
+			return locked.Ref, locked.Commit, true
+		}
+	}
+	if err == nil {
+		return meta.Ref, "", true
+	}
+	return "", "", false
+}
+
+func printSourceStatus(s *sourceStatus) {
+	switch {
+	case s.err != nil:
+		fmt.Printf("  %s %s: %v\n", errorIcon, s.name, s.err)
+	case s.local:
+		fmt.Printf("  %s %s %s\n", successIcon, s.name, dim("(local, read on every sync)"))
+	case s.neverSync:
+		fmt.Printf("  %s %s %s not synced yet\n", warningIcon, s.name, dim("("+s.ref+")"))
+	case s.refChanged != "":
+		fmt.Printf("  %s %s pinned to %s, last synced at %s\n", warningIcon, s.name, s.ref, s.refChanged)
+	case s.synced == "":
+		fmt.Printf("  %s %s %s now at %s, last synced commit unknown\n", warningIcon, s.name, dim("("+s.ref+")"), lockfile.ShortSHA(s.upstream))
+	case s.synced == s.upstream:
+		fmt.Printf("  %s %s %s up to date at %s\n", successIcon, s.name, dim("("+s.ref+")"), lockfile.ShortSHA(s.upstream))
+	default:
+		fmt.Printf("  %s %s %s %s → %s%s\n", warningIcon, s.name, dim("("+s.ref+")"),
+			lockfile.ShortSHA(s.synced), lockfile.ShortSHA(s.upstream), commitCount(s.commits))
+		printCommits(s.commits)
+	}
+}
+
+// commitCount describes how many commits were listed, or nothing if they couldn't be.
+func commitCount(commits []provider.Commit) string {
+	if commits == nil {
+		return ""
+	}
+	return fmt.Sprintf(", %d new commit(s)", len(commits))
+}
+
+// printCommits prints the newest commits, one line each.
+func printCommits(commits []provider.Commit) {
+	shown := commits
+	if len(shown) > maxListedCommits {
+		shown = shown[len(shown)-maxListedCommits:]
+		fmt.Printf("      %s\n", dim(fmt.Sprintf("... %d earlier commit(s)", len(commits)-maxListedCommits)))
+	}
+	for _, commit := range shown {
+		fmt.Printf("      %s %s\n", dim(lockfile.ShortSHA(commit.SHA)), firstLine(commit.Message))
+	}
+}
+
+// firstLine returns the subject line of a commit message.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
+
+// notePreviousCommits records the commit each source was last synced at, so
+// the sync can report what changed upstream.
+func notePreviousCommits(c *cache.Cache, paths *config.Paths, contexts []*repoContext) {
+	lock, _ := lockfile.Load(paths.LockFile)
+	for _, rc := range contexts {
+		if rc.spec != nil {
+			_, rc.previous, _ = lastSynced(c, lock, rc.spec)
+		}
+	}
+}
+
+// noteConfigChanges compares a freshly fetched CLAUDE.md with the cached copy
+// it is about to replace.
+func (rc *repoContext) noteConfigChanges(c *cache.Cache, content string) {
+	before, _, err := c.Read(rc.owner, rc.repo)
+	if err != nil {
+		return // First sync, so there's nothing to compare with
+	}
+	if changes := merge.DiffSections(before, content); !changes.IsEmpty() {
+		rc.sections = &changes
+	}
+}
+
+// reportUpstreamChanges prints the commits each source gained since the last
+// sync and which CLAUDE.md sections changed.
+func reportUpstreamChanges(ctx context.Context, contexts []*repoContext) {
+	printed := false
+	for _, rc := range contexts {
+		moved := rc.previous != "" && rc.commit != "" && rc.previous != rc.commit
+		if !moved && rc.sections == nil {
+			continue
+		}
+		if !printed {
+			fmt.Println()
+			fmt.Println("Upstream changes:")
+			printed = true
+		}
+
+		if !moved {
+			fmt.Printf("  %s\n", rc.fullName())
+		} else {
+			commits := rc.newCommits(ctx)
+			fmt.Printf("  %s %s → %s%s\n", rc.fullName(), lockfile.ShortSHA(rc.previous), lockfile.ShortSHA(rc.commit), commitCount(commits))
+			printCommits(commits)
+		}
+		if rc.sections != nil {
+			fmt.Printf("    %s %s\n", dim(config.DefaultPath+":"), formatSectionChanges(*rc.sections))
+		}
+	}
+}
+
+// newCommits lists the commits between the previous and current sync, or nil
+// if the provider can't list them.
+func (rc *repoContext) newCommits(ctx context.Context) []provider.Commit {
+	lister, ok := rc.provider.(provider.CommitLister)
+	if !ok {
+		return nil
+	}
+	owner, repo := rc.remote()
+	commits, err := lister.CompareCommits(ctx, owner, repo, rc.previous, rc.commit)
+	if err != nil {
+		return nil
+	}
+	return commits
+}
+
+// formatSectionChanges renders section changes on one line, e.g.
+// "+ Security, ~ Code Style, - Legacy".
+func formatSectionChanges(changes merge.SectionChanges) string {
+	var parts []string
+	if changes.Preamble {
+		parts = append(parts, warning("~")+" (intro)")
+	}
+	for _, h := range changes.Added {
+		parts = append(parts, success("+")+" "+h)
+	}
+	for _, h := range changes.Changed {
+		parts = append(parts, warning("~")+" "+h)
+	}
+	for _, h := range changes.Removed {
+		parts = append(parts, danger("-")+" "+h)
+	}
+	return strings.Join(parts, ", ")
+}
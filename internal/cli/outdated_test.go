@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/HartBrook/staghorn/internal/cache"
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/lockfile"
+	"github.com/HartBrook/staghorn/internal/merge"
+	"github.com/HartBrook/staghorn/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUpstream is a provider whose refs resolve to fixed commits.
+type fakeUpstream struct {
+	provider.SourceProvider
+	refs    map[string]string // ref -> commit
+	commits []provider.Commit // Returned by CompareCommits
+}
+
+func (f *fakeUpstream) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	return "main", nil
+}
+
+func (f *fakeUpstream) ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error) {
+	return f.refs[ref], nil
+}
+
+func (f *fakeUpstream) CompareCommits(ctx context.Context, owner, repo, base, head string) ([]provider.Commit, error) {
+	return f.commits, nil
+}
+
+func TestCompareSource(t *testing.T) {
+	upstream := &fakeUpstream{
+		refs:    map[string]string{"main": "c3", "v2": "c2"},
+		commits: []provider.Commit{{SHA: "c2", Message: "Add security section"}, {SHA: "c3", Message: "Tighten review rules\n\nDetails"}},
+	}
+
+	check := func(t *testing.T, repoStr string, meta *cache.Metadata, lock *lockfile.Lockfile) *sourceStatus {
+		t.Helper()
+		paths := config.NewPathsWithOverrides(t.TempDir(), t.TempDir())
+		c := cache.New(paths)
+		spec, err := config.ParseRepoSpec(repoStr)
+		require.NoError(t, err)
+		if meta != nil {
+			owner, repo := spec.CacheKey()
+			require.NoError(t, c.Write(owner, repo, "# Team", meta))
+		}
+
+		s := &sourceStatus{name: spec.FullName()}
+		compareSource(context.Background(), upstream, spec, c, lock, s)
+		require.NoError(t, s.err)
+		return s
+	}
+
+	t.Run("up to date", func(t *testing.T) {
+		s := check(t, "acme/standards", &cache.Metadata{Ref: "main", Commit: "c3"}, nil)
+		assert.False(t, s.isOutdated())
+		assert.Nil(t, s.commits)
+	})
+
+	t.Run("new commits upstream", func(t *testing.T) {
+		s := check(t, "acme/standards", &cache.Metadata{Ref: "main", Commit: "c1"}, nil)
+		assert.True(t, s.isOutdated())
+		assert.Equal(t, "c1", s.synced)
+		assert.Equal(t, "c3", s.upstream)
+		assert.Len(t, s.commits, 2)
+	})
+
+	t.Run("never synced", func(t *testing.T) {
+		s := check(t, "acme/standards", nil, nil)
+		assert.True(t, s.isOutdated())
+		assert.True(t, s.neverSync)
+	})
+
+	t.Run("lockfile covers sources without a cached config", func(t *testing.T) {
+		lock := &lockfile.Lockfile{}
+		lock.SetSource(&lockfile.Source{Repo: "acme/standards", Ref: "main", Commit: "c3"})
+		s := check(t, "acme/standards", nil, lock)
+		assert.False(t, s.isOutdated())
+	})
+
+	t.Run("pinned ref is resolved instead of the default branch", func(t *testing.T) {
+		s := check(t, "acme/standards@v2", &cache.Metadata{Ref: "v2", Commit: "c2"}, nil)
+		assert.Equal(t, "v2", s.ref)
+		assert.False(t, s.isOutdated())
+	})
+
+	t.Run("changed pin is outdated", func(t *testing.T) {
+		s := check(t, "acme/standards@v2", &cache.Metadata{Ref: "v1", Commit: "c2"}, nil)
+		assert.Equal(t, "v1", s.refChanged)
+		assert.True(t, s.isOutdated())
+	})
+}
+
+func TestRunOutdated_LocalSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeLocalSource(t, map[string]string{"CLAUDE.md": "## Team\n\nUse tabs."})
+
+	out, err := captureStdout(func() error { return runOutdated(context.Background(), config.NewPaths()) })
+	require.NoError(t, err)
+	assert.Contains(t, out, "read on every sync")
+	assert.Contains(t, out, "All sources are up to date")
+}
+
+func TestRunOutdated_ReportsFailedChecks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	sourceDir := writeLocalSource(t, map[string]string{"CLAUDE.md": "## Team\n\nUse tabs."})
+
+	// Nothing listens on port 1, so the remote can't be checked
+	cfg := config.NewSimpleConfig("")
+	cfg.Source = config.Source{Multi: &config.SourceConfig{
+		Default:  sourceDir,
+		Commands: map[string]string{"review": "git+http://127.0.0.1:1/standards.git"},
+	}}
+	require.NoError(t, config.Save(cfg))
+
+	out, err := captureStdout(func() error { return runOutdated(context.Background(), config.NewPaths()) })
+	require.Error(t, err)
+	assert.Equal(t, 2, errors.ExitCode(err))
+	assert.Contains(t, err.Error(), "failed to check 1 source(s)")
+	assert.Contains(t, out, "read on every sync", "the sources that could be checked are still listed")
+}
+
+func TestReportUpstreamChanges(t *testing.T) {
+	rc := &repoContext{
+		owner:    "acme",
+		repo:     "standards",
+		previous: "c1000000000",
+		commit:   "c3000000000",
+		provider: &fakeUpstream{commits: []provider.Commit{{SHA: "c2000000000", Message: "Add security section"}}},
+		sections: &merge.SectionChanges{Added: []string{"Security"}, Removed: []string{"Legacy"}},
+	}
+
+	out, err := captureStdout(func() error {
+		reportUpstreamChanges(context.Background(), []*repoContext{rc})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "acme/standards c1000000 → c3000000, 1 new commit(s)")
+	assert.Contains(t, out, "c2000000 Add security section")
+	assert.Contains(t, out, "CLAUDE.md: + Security, - Legacy")
+
+	// Nothing changed, nothing printed
+	out, err = captureStdout(func() error {
+		reportUpstreamChanges(context.Background(), []*repoContext{{owner: "acme", repo: "standards", previous: "c1", commit: "c1"}})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestSync_ReportsChangedSections(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sourceDir := writeLocalSource(t, map[string]string{
+		"CLAUDE.md": "## Style\n\nUse tabs.\n\n## Legacy\n\nOld rules.",
+	})
+	require.NoError(t, syncQuietly(t))
+
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "CLAUDE.md"), []byte("## Style\n\nUse spaces.\n\n## Security\n\nNo secrets."), 0644))
+	out, err := captureStdout(func() error { return runSync(context.Background(), &syncOptions{}) })
+	require.NoError(t, err)
+	assert.Contains(t, out, "Upstream changes:")
+	assert.Contains(t, out, "CLAUDE.md: + Security, ~ Style, - Legacy")
+}
@@ -0,0 +1,478 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/manifest"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// syncPlan describes what a sync would change, computed by a dry run.
+type syncPlan struct {
+	Targets    []*targetPlan `json:"targets"`
+	ConfigDiff string        `json:"config_diff,omitempty"` // Unified diff of ~/.claude/CLAUDE.md
+	Notes      []string      `json:"notes,omitempty"`       // Steps a real sync would stop to ask about
+	Warnings   []string      `json:"warnings,omitempty"`    // Warnings printed while planning
+}
+
+// targetPlan lists the files sync would change in one target directory.
+// Paths are slash-separated and relative to Dir.
+type targetPlan struct {
+	Dir      string   `json:"dir"`
+	Added    []string `json:"added,omitempty"`
+	Modified []string `json:"modified,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+}
+
+// isEmpty reports whether the target has no changes.
+func (t *targetPlan) isEmpty() bool {
+	return len(t.Added) == 0 && len(t.Modified) == 0 && len(t.Removed) == 0
+}
+
+// runSyncDryRun runs a full sync against a staged copy of the cache, lockfile
+// and Claude Code directory, then reports how the staged tree differs from the real one.
+// Apart from the run lock, nothing outside the staging directory is written.
+func runSyncDryRun(ctx context.Context, opts *syncOptions, paths *config.Paths) error {
+	if opts.output != "text" && opts.output != "json" {
+		return fmt.Errorf("invalid output format %q: use text or json", opts.output)
+	}
+
+	// A concurrent sync could change the cache and ~/.claude while they're staged
+	lock, err := lockStaghorn(paths)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	stageDir, err := os.MkdirTemp("", "staghorn-dry-run-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(stageDir) }()
+
+	plan := &syncPlan{}
+
+	// A real sync would stop to ask before replacing this file; plan as if the user agreed
+	var skip []string
+	if existing, err := os.ReadFile(paths.ClaudeMD()); err == nil {
+		if reason := migrationReason(cfg, string(existing)); reason != "" {
+			plan.Notes = append(plan.Notes, reason+"; sync will ask before replacing it")
+			skip = append(skip, filepath.Base(paths.ClaudeMD()))
+		}
+	}
+
+	// A real sync would stop rather than overwrite hand edits
+	edited, err := syncLocalEdits(paths, opts)
+	if err != nil {
+		return err
+	}
+	for _, path := range edited {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("%s was edited by hand; sync will stop until you run 'staghorn rescue'", displayPath(path)))
+	}
+
+	staged, err := stagePaths(paths, stageDir, skip)
+	if err != nil {
+		return err
+	}
+
+	// Always fetch, so the plan reflects upstream rather than a fresh cache
+	stagedOpts := *opts
+	stagedOpts.dryRun = false
+	stagedOpts.force = true
+
+	log, err := captureStdout(func() error {
+		return syncWithPaths(ctx, &stagedOpts, staged)
+	})
+	if err != nil {
+		return err
+	}
+	plan.Warnings = warningLines(log)
+
+	if err := plan.compare(paths, staged); err != nil {
+		return err
+	}
+
+	if opts.output == "json" {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	plan.print()
+	return nil
+}
+
+// stagePaths copies everything sync writes into stageDir and returns paths
+// pointing there. Personal config is read in place since sync never writes it;
+// the config directory itself moves to the stage so history recorded by the
+// staged sync is thrown away with it. Top-level Claude files named in skip are
+// left out of the stage.
+func stagePaths(paths *config.Paths, stageDir string, skip []string) (*config.Paths, error) {
+	staged := *paths
+	staged.ConfigDir = filepath.Join(stageDir, "config")
+	staged.CacheDir = filepath.Join(stageDir, "cache")
+	staged.LockFile = filepath.Join(stageDir, filepath.Base(paths.LockFile))
+	staged.ClaudeDir = filepath.Join(stageDir, "claude")
+
+	entries, err := os.ReadDir(paths.CacheDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+	if err := os.MkdirAll(staged.CacheDir, 0755); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		src := filepath.Join(paths.CacheDir, entry.Name())
+		dst := filepath.Join(staged.CacheDir, entry.Name())
+
+		// The staged sync takes its own lock
+		if isRunState(paths, src) {
+			continue
+		}
+
+		// Blobs and git clones are copied too: fetching into them in place would
+		// change the real cache, and a copy still saves downloading everything again
+		if err := copyPath(src, dst); err != nil {
+			return nil, fmt.Errorf("failed to stage cache: %w", err)
+		}
+	}
+
+	if err := copyPath(paths.LockFile, staged.LockFile); err != nil {
+		return nil, fmt.Errorf("failed to stage lockfile: %w", err)
+	}
+
+	claudeFiles := map[string]string{
+		paths.ClaudeMD():          staged.ClaudeMD(),
+		paths.ClaudeCommandsDir(): staged.ClaudeCommandsDir(),
+		paths.ClaudeRulesDir():    staged.ClaudeRulesDir(),
+		paths.ClaudeSkillsDir():   staged.ClaudeSkillsDir(),
+	}
+	for src, dst := range claudeFiles {
+		if containsString(skip, filepath.Base(src)) {
+			continue
+		}
+		if err := copyPath(src, dst); err != nil {
+			return nil, fmt.Errorf("failed to stage %s: %w", displayPath(src), err)
+		}
+	}
+
+	return &staged, nil
+}
+
+// isFetchCache reports whether path is one of the cache's content-addressed
+// fetch caches, which hold downloads rather than sync output.
+func isFetchCache(paths *config.Paths, path string) bool {
+	return path == paths.GitCacheDir() || path == paths.BlobDir()
+}
+
+// copyPath copies a file or directory tree from src to dst. A missing src is not an error.
+func copyPath(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == src {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0644)
+	})
+}
+
+// captureStdout runs fn with stdout redirected and returns what it printed.
+func captureStdout(fn func() error) (string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fnErr := fn()
+
+	os.Stdout = stdout
+	_ = w.Close()
+	captured := <-done
+	_ = r.Close()
+
+	return captured, fnErr
+}
+
+// warningLines extracts the messages printed by printWarning from captured output.
+func warningLines(output string) []string {
+	var warnings []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if msg, ok := strings.CutPrefix(scanner.Text(), warningIcon+" "); ok {
+			warnings = append(warnings, msg)
+		}
+	}
+	return warnings
+}
+
+// compare fills in the plan's targets and config diff from the staged sync.
+func (p *syncPlan) compare(paths, staged *config.Paths) error {
+	// Merged config, with a unified diff
+	before, _ := os.ReadFile(paths.ClaudeMD())
+	after, _ := os.ReadFile(staged.ClaudeMD())
+	claudeTarget := &targetPlan{Dir: displayPath(filepath.Dir(paths.ClaudeMD()))}
+	claudeTarget.addFile(filepath.Base(paths.ClaudeMD()), paths.ClaudeMD(), staged.ClaudeMD())
+	p.add(claudeTarget)
+	if string(before) != string(after) {
+		diff, err := unifiedDiff(displayPath(paths.ClaudeMD()), string(before), string(after))
+		if err != nil {
+			return err
+		}
+		p.ConfigDiff = diff
+	}
+
+	// Claude Code commands, rules and skills
+	for _, dir := range [][2]string{
+		{paths.ClaudeCommandsDir(), staged.ClaudeCommandsDir()},
+		{paths.ClaudeRulesDir(), staged.ClaudeRulesDir()},
+		{paths.ClaudeSkillsDir(), staged.ClaudeSkillsDir()},
+	} {
+		target, err := planDir(dir[0], dir[1])
+		if err != nil {
+			return err
+		}
+		p.add(target)
+	}
+
+	// Cache, one target per cached directory
+	cacheTargets, err := planCache(paths, staged)
+	if err != nil {
+		return err
+	}
+	for _, target := range cacheTargets {
+		p.add(target)
+	}
+
+	lockTarget := &targetPlan{Dir: displayPath(filepath.Dir(paths.LockFile))}
+	lockTarget.addFile(filepath.Base(paths.LockFile), paths.LockFile, staged.LockFile)
+	p.add(lockTarget)
+
+	return nil
+}
+
+// add appends target to the plan if it has changes.
+func (p *syncPlan) add(target *targetPlan) {
+	if !target.isEmpty() {
+		p.Targets = append(p.Targets, target)
+	}
+}
+
+// addFile classifies a single file by comparing its real and staged copies.
+func (t *targetPlan) addFile(rel, realPath, stagedPath string) {
+	before, beforeErr := os.ReadFile(realPath)
+	after, afterErr := os.ReadFile(stagedPath)
+
+	switch {
+	case beforeErr != nil && afterErr == nil:
+		t.Added = append(t.Added, rel)
+	case beforeErr == nil && afterErr != nil:
+		t.Removed = append(t.Removed, rel)
+	case beforeErr == nil && string(before) != string(after):
+		t.Modified = append(t.Modified, rel)
+	}
+}
+
+// planDir compares every file under a real directory with its staged counterpart.
+func planDir(realDir, stagedDir string) (*targetPlan, error) {
+	target := &targetPlan{Dir: displayPath(realDir)}
+
+	realFiles, err := manifest.Scan(realDir)
+	if err != nil {
+		return nil, err
+	}
+	stagedFiles, err := manifest.Scan(stagedDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rel := range unionSorted(realFiles, stagedFiles) {
+		native := filepath.FromSlash(rel)
+		target.addFile(rel, filepath.Join(realDir, native), filepath.Join(stagedDir, native))
+	}
+	return target, nil
+}
+
+// planCache compares the cache with its staged copy. Files at the top of the
+// cache form one target and each cached directory forms its own. Metadata
+// sidecars change on every fetch, run state isn't staged, and the fetch
+// caches only ever gain downloads, so all of them are skipped.
+func planCache(paths, staged *config.Paths) ([]*targetPlan, error) {
+	names := make(map[string]bool)
+	for _, dir := range []string{paths.CacheDir, staged.CacheDir} {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			names[entry.Name()] = true
+		}
+	}
+
+	root := &targetPlan{Dir: displayPath(paths.CacheDir)}
+	targets := []*targetPlan{root}
+	for _, name := range sortedKeys(names) {
+		realPath := filepath.Join(paths.CacheDir, name)
+		stagedPath := filepath.Join(staged.CacheDir, name)
+		if isFetchCache(paths, realPath) || isRunState(paths, realPath) || strings.HasSuffix(name, ".meta.json") {
+			continue
+		}
+
+		if isDir(realPath) || isDir(stagedPath) {
+			target, err := planDir(realPath, stagedPath)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, target)
+			continue
+		}
+		root.addFile(name, realPath, stagedPath)
+	}
+	return targets, nil
+}
+
+// isDir reports whether path exists and is a directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// unionSorted returns the distinct entries of a and b in sorted order.
+func unionSorted(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		set[s] = true
+	}
+	return sortedKeys(set)
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// unifiedDiff returns a unified diff between two versions of the file at name.
+func unifiedDiff(name, before, after string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: name,
+		ToFile:   name,
+		FromDate: "current",
+		ToDate:   "after sync",
+		Context:  3,
+	})
+}
+
+// print renders the plan for the terminal.
+func (p *syncPlan) print() {
+	fmt.Println("Dry run: no files were changed.")
+
+	for _, note := range p.Notes {
+		fmt.Println()
+		printWarning("%s", note)
+	}
+
+	if len(p.Targets) == 0 {
+		fmt.Println()
+		printSuccess("Already up to date")
+	}
+
+	added, modified, removed := 0, 0, 0
+	for _, target := range p.Targets {
+		fmt.Println()
+		fmt.Println(info(target.Dir))
+		for _, f := range target.Added {
+			fmt.Printf("  %s %s\n", success("+"), f)
+		}
+		for _, f := range target.Modified {
+			fmt.Printf("  %s %s\n", warning("~"), f)
+		}
+		for _, f := range target.Removed {
+			fmt.Printf("  %s %s\n", danger("-"), f)
+		}
+		added += len(target.Added)
+		modified += len(target.Modified)
+		removed += len(target.Removed)
+	}
+
+	if p.ConfigDiff != "" {
+		fmt.Println()
+		for _, line := range strings.Split(strings.TrimSuffix(p.ConfigDiff, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+				fmt.Println(dim(line))
+			case strings.HasPrefix(line, "@@"):
+				fmt.Println(info(line))
+			case strings.HasPrefix(line, "+"):
+				fmt.Println(success(line))
+			case strings.HasPrefix(line, "-"):
+				fmt.Println(danger(line))
+			default:
+				fmt.Println(line)
+			}
+		}
+	}
+
+	if len(p.Warnings) > 0 {
+		fmt.Println()
+		for _, w := range p.Warnings {
+			printWarning("%s", w)
+		}
+	}
+
+	if len(p.Targets) > 0 {
+		fmt.Println()
+		fmt.Printf("%d to add, %d to modify, %d to remove. Run %s to apply.\n", added, modified, removed, info("staghorn sync"))
+	}
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLocalSource lays out a source repo on disk and configures it as the sync source.
+func writeLocalSource(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	sourceDir := filepath.Join(t.TempDir(), "standards")
+	for path, content := range files {
+		full := filepath.Join(sourceDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	paths := config.NewPaths()
+	require.NoError(t, config.SaveTo(config.NewSimpleConfig("file://"+sourceDir), paths.ConfigFile))
+	return sourceDir
+}
+
+// dryRunPlan runs sync --dry-run --output json and decodes the plan.
+func dryRunPlan(t *testing.T) *syncPlan {
+	t.Helper()
+
+	out, err := captureStdout(func() error {
+		return runSync(context.Background(), &syncOptions{dryRun: true, output: "json"})
+	})
+	require.NoError(t, err)
+
+	var plan syncPlan
+	require.NoError(t, json.Unmarshal([]byte(out), &plan), out)
+	return &plan
+}
+
+// findTarget returns the plan target for dir, or nil.
+func findTarget(plan *syncPlan, dir string) *targetPlan {
+	for _, target := range plan.Targets {
+		if target.Dir == dir {
+			return target
+		}
+	}
+	return nil
+}
+
+func TestRunSyncDryRun(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	sourceDir := writeLocalSource(t, map[string]string{
+		"CLAUDE.md":             "## Team\n\nUse tabs.",
+		"commands/review.md":    "---\nname: review\ndescription: Review code\n---\nReview it",
+		"commands/debug.md":     "---\nname: debug\ndescription: Debug code\n---\nDebug it",
+		"rules/security.md":     "Never log secrets.",
+		"skills/react/SKILL.md": "---\nname: react\ndescription: React help\n---\nUse hooks.",
+	})
+
+	t.Run("first sync adds everything without writing", func(t *testing.T) {
+		plan := dryRunPlan(t)
+
+		claude := findTarget(plan, filepath.Join("~", ".claude"))
+		require.NotNil(t, claude)
+		assert.Equal(t, []string{"CLAUDE.md"}, claude.Added)
+
+		commands := findTarget(plan, filepath.Join("~", ".claude", "commands"))
+		require.NotNil(t, commands)
+		assert.Equal(t, []string{"debug.md", "review.md"}, commands.Added)
+
+		skills := findTarget(plan, filepath.Join("~", ".claude", "skills"))
+		require.NotNil(t, skills)
+		assert.Equal(t, []string{"react/SKILL.md"}, skills.Added)
+
+		assert.Contains(t, plan.ConfigDiff, "+Use tabs.")
+
+		assert.NoDirExists(t, filepath.Join(home, ".claude"))
+		assert.NoFileExists(t, config.NewPaths().LockFile)
+		assert.NoDirExists(t, config.NewPaths().GenerationsDir())
+
+		// Only the run lock, taken so a concurrent sync can't change what's staged
+		cached, err := os.ReadDir(filepath.Join(home, ".cache", "staghorn"))
+		require.NoError(t, err)
+		require.Len(t, cached, 1)
+		assert.Equal(t, filepath.Base(config.NewPaths().RunLockFile()), cached[0].Name())
+	})
+
+	require.NoError(t, runSync(context.Background(), &syncOptions{}))
+
+	t.Run("no changes after a sync", func(t *testing.T) {
+		plan := dryRunPlan(t)
+		assert.Empty(t, plan.Targets)
+		assert.Empty(t, plan.ConfigDiff)
+	})
+
+	t.Run("upstream edits and deletions", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "CLAUDE.md"), []byte("## Team\n\nUse spaces."), 0644))
+		require.NoError(t, os.Remove(filepath.Join(sourceDir, "commands", "debug.md")))
+
+		before, err := os.ReadFile(filepath.Join(home, ".claude", "CLAUDE.md"))
+		require.NoError(t, err)
+
+		plan := dryRunPlan(t)
+
+		claude := findTarget(plan, filepath.Join("~", ".claude"))
+		require.NotNil(t, claude)
+		assert.Equal(t, []string{"CLAUDE.md"}, claude.Modified)
+
+		commands := findTarget(plan, filepath.Join("~", ".claude", "commands"))
+		require.NotNil(t, commands)
+		assert.Equal(t, []string{"debug.md"}, commands.Removed)
+
+		assert.Contains(t, plan.ConfigDiff, "-Use tabs.")
+		assert.Contains(t, plan.ConfigDiff, "+Use spaces.")
+
+		after, err := os.ReadFile(filepath.Join(home, ".claude", "CLAUDE.md"))
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+		assert.FileExists(t, filepath.Join(home, ".claude", "commands", "debug.md"))
+	})
+}
+
+func TestRunSyncDryRun_UnmanagedConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeLocalSource(t, map[string]string{"CLAUDE.md": "## Team\n\nUse tabs."})
+
+	existing := filepath.Join(home, ".claude", "CLAUDE.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(existing), 0755))
+	require.NoError(t, os.WriteFile(existing, []byte("# Mine"), 0644))
+
+	plan := dryRunPlan(t)
+	require.Len(t, plan.Notes, 1)
+	assert.Contains(t, plan.Notes[0], "not managed by staghorn")
+	assert.Contains(t, plan.ConfigDiff, "-# Mine")
+
+	content, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "# Mine", string(content))
+}
+
+func TestRunSync_OutputRequiresDryRun(t *testing.T) {
+	err := runSync(context.Background(), &syncOptions{output: "json"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--dry-run")
+
+	err = runSync(context.Background(), &syncOptions{dryRun: true, output: "yaml"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid output format")
+}
+
+func TestPlanDir(t *testing.T) {
+	realDir := t.TempDir()
+	stagedDir := t.TempDir()
+
+	write := func(dir, rel, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write(realDir, "same.md", "same")
+	write(stagedDir, "same.md", "same")
+	write(realDir, "sub/changed.md", "old")
+	write(stagedDir, "sub/changed.md", "new")
+	write(realDir, "gone.md", "gone")
+	write(stagedDir, "new.md", "new")
+
+	target, err := planDir(realDir, stagedDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new.md"}, target.Added)
+	assert.Equal(t, []string{"sub/changed.md"}, target.Modified)
+	assert.Equal(t, []string{"gone.md"}, target.Removed)
+}
+
+func TestStagePaths_CopiesFetchCaches(t *testing.T) {
+	root := t.TempDir()
+	paths := config.NewPathsWithOverrides(filepath.Join(root, "config"), filepath.Join(root, "cache"))
+	require.NoError(t, os.MkdirAll(paths.BlobDir(), 0755))
+	require.NoError(t, os.WriteFile(paths.BlobFile("abc"), []byte("cached"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(paths.GitCacheDir(), "repo"), 0755))
+
+	staged, err := stagePaths(paths, filepath.Join(root, "stage"), nil)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(staged.BlobFile("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, "cached", string(content))
+	assert.DirExists(t, filepath.Join(staged.GitCacheDir(), "repo"))
+
+	// Fetches during the dry run land in the copies
+	require.NoError(t, os.WriteFile(staged.BlobFile("def"), []byte("new"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(staged.GitCacheDir(), "other"), 0755))
+	assert.NoFileExists(t, paths.BlobFile("def"))
+	assert.NoDirExists(t, filepath.Join(paths.GitCacheDir(), "other"))
+}
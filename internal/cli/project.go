@@ -8,7 +8,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/HartBrook/staghorn/internal/commands"
 	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/fsutil"
+	"github.com/HartBrook/staghorn/internal/language"
+	"github.com/HartBrook/staghorn/internal/merge"
+	"github.com/HartBrook/staghorn/internal/rules"
+	"github.com/HartBrook/staghorn/internal/skills"
 	"github.com/spf13/cobra"
 )
 
@@ -33,6 +40,15 @@ const defaultProjectTemplate = `# Project Guidelines
 - [Add any project-specific notes for Claude]
 `
 
+// templateProjectStub is the initial .staghorn/project.md for a project created
+// from a team template. The template is kept in .staghorn/template.md and merged
+// beneath it, so this file only holds what the project adds or overrides.
+const templateProjectStub = `# Project Guidelines
+
+<!-- Sections here are merged on top of the team template in .staghorn/template.md.
+A section with the same heading as a template section is added to it. -->
+`
+
 // NewProjectCmd creates the project parent command.
 func NewProjectCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -46,6 +62,8 @@ This provides a consistent experience across all three layers (team, personal, p
 
 	cmd.AddCommand(NewProjectInitCmd())
 	cmd.AddCommand(NewProjectEditCmd())
+	cmd.AddCommand(NewProjectSyncCmd())
+	cmd.AddCommand(NewProjectUpgradeCmd())
 	cmd.AddCommand(NewProjectInfoCmd())
 	cmd.AddCommand(NewProjectTemplatesCmd())
 
@@ -88,29 +106,28 @@ func runProjectInit(templateName string) error {
 		return fmt.Errorf("failed to create .staghorn directory: %w", err)
 	}
 
-	// Determine content: template or default
-	var content string
+	// Check if templates are available and offer selection
+	if templateName == "" {
+		templateName = offerTemplateSelection()
+	}
+
+	// Determine content: a template merged beneath a stub, or the default
+	content := defaultProjectTemplate
 	if templateName != "" {
-		// Explicit template requested
 		templateContent, err := loadTemplate(templateName)
 		if err != nil {
 			return err
 		}
-		content = templateContent
-		printSuccess("Using template: %s", templateName)
-	} else {
-		// Check if templates are available and offer selection
-		templateName = offerTemplateSelection()
-		if templateName != "" {
-			templateContent, err := loadTemplate(templateName)
-			if err != nil {
-				return err
-			}
-			content = templateContent
-			printSuccess("Using template: %s", templateName)
-		} else {
-			content = defaultProjectTemplate
+		if err := fsutil.WriteFile(projectPaths.TemplateMD, []byte(templateContent), 0644); err != nil {
+			return fmt.Errorf("failed to write template.md: %w", err)
+		}
+		projectCfg := &config.ProjectConfig{}
+		projectCfg.SetTemplate(templateName, templateContent)
+		if err := config.SaveProjectConfig(projectCfg, projectPaths.ConfigFile); err != nil {
+			return err
 		}
+		content = templateProjectStub
+		printSuccess("Using template: %s", templateName)
 	}
 
 	// Write project.md
@@ -147,13 +164,7 @@ func offerTemplateSelection() string {
 		return ""
 	}
 
-	owner, repo, err := cfg.DefaultOwnerRepo()
-	if err != nil {
-		return ""
-	}
-
-	templatesDir := paths.TeamTemplatesDir(owner, repo)
-	templates := listAvailableTemplates(templatesDir)
+	templates, templatePaths := listTeamTemplates(cfg, paths)
 
 	if len(templates) == 0 {
 		return ""
@@ -164,7 +175,7 @@ func offerTemplateSelection() string {
 	fmt.Printf("Your team has %d project templates available:\n", len(templates))
 	fmt.Println()
 	for i, name := range templates {
-		desc := getTemplateDescription(filepath.Join(templatesDir, name+".md"))
+		desc := getTemplateDescription(templatePaths[name])
 		fmt.Printf("  %d. %-20s %s\n", i+1, info(name), dim(desc))
 	}
 	fmt.Printf("  %d. %-20s %s\n", len(templates)+1, info("default"), dim("Start with minimal template"))
@@ -208,24 +219,22 @@ func loadTemplate(name string) (string, error) {
 		return "", err
 	}
 
-	owner, repo, err := cfg.DefaultOwnerRepo()
-	if err != nil {
+	if _, _, err := cfg.DefaultOwnerRepo(); err != nil {
 		return "", err
 	}
 
-	// Look for template file
-	templatesDir := paths.TeamTemplatesDir(owner, repo)
-	templatePath := filepath.Join(templatesDir, name+".md")
-
-	content, err := os.ReadFile(templatePath)
-	if os.IsNotExist(err) {
+	// Look for template file in the source that owns it
+	available, templatePaths := listTeamTemplates(cfg, paths)
+	templatePath, ok := templatePaths[name]
+	if !ok {
 		// List available templates for helpful error
-		available := listAvailableTemplates(templatesDir)
 		if len(available) == 0 {
 			return "", fmt.Errorf("template '%s' not found\nNo templates available. Run 'staghorn sync' to fetch team templates.", name)
 		}
 		return "", fmt.Errorf("template '%s' not found\nAvailable templates: %s", name, strings.Join(available, ", "))
 	}
+
+	content, err := os.ReadFile(templatePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read template: %w", err)
 	}
@@ -251,6 +260,28 @@ func listAvailableTemplates(templatesDir string) []string {
 	return names
 }
 
+// listTeamTemplates returns the names of the cached team templates, sorted,
+// and the file each is read from. A template configured under
+// source.templates comes from its own repo's cache.
+func listTeamTemplates(cfg *config.Config, paths *config.Paths) ([]string, map[string]string) {
+	var entries map[string]string
+	if cfg.Source.Multi != nil {
+		entries = cfg.Source.Multi.Templates
+	}
+
+	templatePaths := make(map[string]string)
+	names := make(map[string]bool)
+	for _, src := range teamItemSources(cfg, entries, paths.TeamTemplatesDir) {
+		for _, name := range listAvailableTemplates(src.dir) {
+			if cfg.Source.RepoForTemplate(name) == src.repo {
+				templatePaths[name] = filepath.Join(src.dir, name+".md")
+				names[name] = true
+			}
+		}
+	}
+	return sortedKeys(names), templatePaths
+}
+
 // NewProjectEditCmd creates the 'project edit' command.
 func NewProjectEditCmd() *cobra.Command {
 	var noApply bool
@@ -258,9 +289,10 @@ func NewProjectEditCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "edit",
 		Short: "Edit project config (auto-applies on save)",
-		Long: `Opens .staghorn/project.md in your editor and automatically applies changes.
+		Long: `Opens .staghorn/project.md in your editor and automatically applies changes,
+regenerating ./CLAUDE.md and the project's .claude/ rules, commands, and skills.
 
-Use --no-apply to edit without generating ./CLAUDE.md.`,
+Use --no-apply to edit without generating anything.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runProjectEditWithApply(noApply)
 		},
@@ -280,6 +312,13 @@ func runProjectEditWithApply(noApply bool) error {
 		return fmt.Errorf("project not initialized\nRun 'staghorn project init' first")
 	}
 
+	// Catch hand edits to ./CLAUDE.md before they're overwritten
+	if !noApply {
+		if err := checkProjectEdits(paths); err != nil {
+			return err
+		}
+	}
+
 	// Open editor
 	if err := openEditor(paths.SourceMD); err != nil {
 		return err
@@ -301,6 +340,257 @@ func runProjectEditWithApply(noApply bool) error {
 
 	printSuccess("Applied to %s", relativePath(paths.OutputMD))
 
+	return syncProjectClaude(paths, newProjectPruner(true))
+}
+
+// NewProjectSyncCmd creates the 'project sync' command.
+func NewProjectSyncCmd() *cobra.Command {
+	var noPrune bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Generate ./CLAUDE.md and .claude/ from .staghorn/",
+		Long: `Regenerates ./CLAUDE.md from .staghorn/project.md and installs project rules,
+commands, and skills from .staghorn/ into .claude/rules/, .claude/commands/, and
+.claude/skills/, so a repo can commit .staghorn/ as its single source of truth.
+
+Files in .claude/ that staghorn doesn't manage are left alone. Files a previous
+project sync installed are removed once their source is deleted from .staghorn/.`,
+		Example: `  staghorn project sync
+  staghorn project sync --no-prune`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProjectSync(noPrune)
+		},
+	}
+
+	cmd.Flags().BoolVar(&noPrune, "no-prune", false, "Keep files whose source was removed from .staghorn/")
+
+	return cmd
+}
+
+func runProjectSync(noPrune bool) error {
+	projectRoot := findProjectRoot()
+	if projectRoot == "" {
+		return fmt.Errorf("no project root found (looking for .git or .staghorn directory)")
+	}
+	paths := config.NewProjectPaths(projectRoot)
+
+	if _, err := os.Stat(paths.StaghornDir); os.IsNotExist(err) {
+		return fmt.Errorf("project not initialized\nRun 'staghorn project init' first")
+	}
+
+	// Projects that only keep rules, commands, or skills have no project.md
+	if _, err := os.Stat(paths.SourceMD); err == nil {
+		if err := checkProjectEdits(paths); err != nil {
+			return err
+		}
+		if err := generateProjectOutput(paths); err != nil {
+			return err
+		}
+		printSuccess("Applied to %s", relativePath(paths.OutputMD))
+	}
+
+	return syncProjectClaude(paths, newProjectPruner(!noPrune))
+}
+
+// newProjectPruner creates a pruner for a project's .claude/ directories. Only
+// files listed by a previous project sync are removed; directories aren't seeded
+// from their managed files, since they may hold starter commands and skills
+// installed with --claude-project.
+func newProjectPruner(enabled bool) *pruner {
+	return &pruner{enabled: enabled}
+}
+
+// syncProjectClaude installs the project's rules, commands, and skills from
+// .staghorn/ into the project's .claude/ directory.
+func syncProjectClaude(paths *config.ProjectPaths, pr *pruner) error {
+	ruleRegistry, err := rules.LoadRegistry("", "", paths.RulesDir)
+	if err := warnSkippedRules(err); err != nil {
+		return fmt.Errorf("failed to load project rules: %w", err)
+	}
+	ruleCount, _, err := installClaudeRules(ruleRegistry, config.ProjectClaudeRulesDir(paths.Root), pr)
+	if err != nil {
+		return fmt.Errorf("failed to sync project rules: %w", err)
+	}
+
+	commandRegistry, err := commands.LoadRegistry("", "", paths.CommandsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load project commands: %w", err)
+	}
+	commandCount, _, err := installClaudeCommands(commandRegistry, config.ProjectClaudeCommandsDir(paths.Root), pr)
+	if err != nil {
+		return fmt.Errorf("failed to sync project commands: %w", err)
+	}
+
+	skillRegistry, err := skills.LoadRegistry("", "", paths.SkillsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load project skills: %w", err)
+	}
+	skillCount, err := installClaudeSkills(skillRegistry, config.ProjectClaudeSkillsDir(paths.Root), pr, nil)
+	if err != nil {
+		return fmt.Errorf("failed to sync project skills: %w", err)
+	}
+
+	if ruleCount > 0 {
+		printSuccess("Synced %d rules to %s", ruleCount, relativePath(config.ProjectClaudeRulesDir(paths.Root)))
+	}
+	if commandCount > 0 {
+		printSuccess("Synced %d commands to %s", commandCount, relativePath(config.ProjectClaudeCommandsDir(paths.Root)))
+	}
+	if skillCount > 0 {
+		printSuccess("Synced %d skills to %s", skillCount, relativePath(config.ProjectClaudeSkillsDir(paths.Root)))
+	}
+	if len(pr.removed) > 0 {
+		printSuccess("Pruned %d file(s) removed from .staghorn/", len(pr.removed))
+		for _, path := range pr.removed {
+			fmt.Printf("  %s %s\n", dim("-"), relativePath(path))
+		}
+	}
+	return nil
+}
+
+// NewProjectUpgradeCmd creates the 'project upgrade' command.
+func NewProjectUpgradeCmd() *cobra.Command {
+	var templateName string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the project to the latest team template",
+		Long: `Replaces .staghorn/template.md with the latest version of the team template
+the project was created from, keeping the project's edits in .staghorn/project.md.
+
+The upgrade is a three-way merge, section by section: additions to a template
+section carry over to its new content, and a conflict is reported where the
+project replaces or removes a section the template changed, adds to a section
+the template removed, or defines a section the template added.
+
+Projects created before templates were recorded keep the template in
+project.md. Use --template to adopt one: sections matching the template move
+out of project.md, and differing sections are kept as replacements.
+
+Exits with status 1 if there are conflicts to review. Run 'staghorn sync' first
+to fetch the latest templates.`,
+		Example: `  staghorn project upgrade
+  staghorn project upgrade --dry-run
+  staghorn project upgrade --template=backend-service`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProjectUpgrade(templateName, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&templateName, "template", "t", "", "Template to upgrade to (defaults to the recorded template)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what the upgrade would change without writing anything")
+
+	return cmd
+}
+
+func runProjectUpgrade(templateName string, dryRun bool) error {
+	projectRoot := findProjectRoot()
+	if projectRoot == "" {
+		return fmt.Errorf("no project root found (looking for .git or .staghorn directory)")
+	}
+	paths := config.NewProjectPaths(projectRoot)
+
+	if _, err := os.Stat(paths.SourceMD); os.IsNotExist(err) {
+		return fmt.Errorf("project not initialized\nRun 'staghorn project init' first")
+	}
+
+	projectCfg, err := config.LoadProjectConfig(paths.ConfigFile)
+	if err != nil {
+		return err
+	}
+	if templateName == "" {
+		templateName = projectCfg.Template
+	}
+	if templateName == "" {
+		return fmt.Errorf("no template recorded for this project\nRun 'staghorn project upgrade --template=<name>' to adopt one")
+	}
+
+	next, err := loadTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	sources, err := loadProjectSources(paths)
+	if err != nil {
+		return err
+	}
+
+	// Projects with template.md keep their edits in project.md; older ones
+	// copied the template into project.md and have it rebased instead
+	project := sources.project
+	var conflicts []merge.Conflict
+	if sources.template != "" {
+		if sources.templateEdit {
+			return fmt.Errorf("%s was edited by hand, so the upgrade can't tell those edits from the template's\nMove them into %s (a section with <!-- staghorn:replace --> overrides the template's), then retry",
+				relativePath(paths.TemplateMD), relativePath(paths.SourceMD))
+		}
+		if sources.template == next && projectCfg.Template == templateName {
+			if projectCfg.TemplateHash == "" {
+				projectCfg.SetTemplate(templateName, next)
+				if !dryRun {
+					if err := config.SaveProjectConfig(projectCfg, paths.ConfigFile); err != nil {
+						return err
+					}
+				}
+			}
+			printSuccess("Already up to date with template %s", templateName)
+			return nil
+		}
+
+		fmt.Printf("Upgrading to template %s\n", info(templateName))
+		if changes := merge.DiffSections(sources.template, next); !changes.IsEmpty() {
+			fmt.Printf("  %s %s\n", dim("Template:"), formatSectionChanges(changes))
+		}
+		conflicts = merge.CheckUpgrade(sources.template, next, project)
+	} else {
+		fmt.Printf("Adopting template %s\n", info(templateName))
+		project, conflicts = merge.Rebase(project, next)
+	}
+
+	if len(conflicts) > 0 {
+		fmt.Println()
+		fmt.Println("Conflicts:")
+		for _, c := range conflicts {
+			section := c.Section
+			if section == "" {
+				section = "(intro)"
+			}
+			fmt.Printf("  %s %s: %s\n", warningIcon, section, c.Reason)
+		}
+	}
+	fmt.Println()
+
+	if dryRun {
+		fmt.Println(dim("Dry run: nothing was written."))
+	} else {
+		if err := checkProjectEdits(paths); err != nil {
+			return err
+		}
+		if err := fsutil.WriteFile(paths.TemplateMD, []byte(next), 0644); err != nil {
+			return fmt.Errorf("failed to write template.md: %w", err)
+		}
+		if project != sources.project {
+			if err := fsutil.WriteFile(paths.SourceMD, []byte(project), 0644); err != nil {
+				return fmt.Errorf("failed to write project.md: %w", err)
+			}
+		}
+		projectCfg.SetTemplate(templateName, next)
+		if err := config.SaveProjectConfig(projectCfg, paths.ConfigFile); err != nil {
+			return err
+		}
+		if err := generateProjectOutput(paths); err != nil {
+			return err
+		}
+		printSuccess("Upgraded to template %s", templateName)
+		printSuccess("Applied to %s", relativePath(paths.OutputMD))
+	}
+
+	if len(conflicts) > 0 {
+		return errors.UpgradeConflicts(len(conflicts))
+	}
 	return nil
 }
 
@@ -336,14 +626,14 @@ func runProjectInfo(showContent bool) error {
 		return nil
 	}
 
+	sources, err := loadProjectSources(paths)
+	if err != nil {
+		return err
+	}
+
 	// If --content, show generated output
 	if showContent {
-		content, err := os.ReadFile(paths.SourceMD)
-		if err != nil {
-			return fmt.Errorf("failed to read project.md: %w", err)
-		}
-		fmt.Print(projectHeader)
-		fmt.Println(strings.TrimSpace(string(content)))
+		fmt.Print(sources.render())
 		return nil
 	}
 
@@ -356,6 +646,20 @@ func runProjectInfo(showContent bool) error {
 	sourceLines := bytes.Count(sourceContent, []byte("\n")) + 1
 
 	fmt.Printf("  %s: %s (%d lines)\n", dim("Source"), relativePath(paths.SourceMD), sourceLines)
+	if sources.template != "" {
+		name := sources.templateName
+		if name == "" {
+			name = "unnamed"
+		}
+		edited := ""
+		if sources.templateEdit {
+			edited = " " + warning("edited by hand")
+		}
+		fmt.Printf("  %s: %s (%s)%s\n", dim("Template"), name, relativePath(paths.TemplateMD), edited)
+	}
+	if len(sources.languages) > 0 {
+		fmt.Printf("  %s: %s\n", dim("Languages"), strings.Join(sources.languages, ", "))
+	}
 	fmt.Printf("  %s: %s\n", dim("Output"), relativePath(paths.OutputMD))
 
 	// Check output status
@@ -379,18 +683,101 @@ func runProjectInfo(showContent bool) error {
 	return nil
 }
 
+// projectSources is everything a project CLAUDE.md is generated from.
+type projectSources struct {
+	project       string                              // .staghorn/project.md
+	template      string                              // .staghorn/template.md (empty if the project has none)
+	templateName  string                              // Team template the project was created from
+	templateEdit  bool                                // template.md was edited since it was copied
+	languages     []string                            // Detected languages that have a project language file
+	languageFiles map[string][]*language.LanguageFile // Project language files by language ID
+}
+
+// loadProjectSources reads project.md, the project's template, and the files in
+// .staghorn/languages/ for languages detected in the project.
+func loadProjectSources(paths *config.ProjectPaths) (*projectSources, error) {
+	source, err := os.ReadFile(paths.SourceMD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project.md: %w", err)
+	}
+	projectCfg, err := config.LoadProjectConfig(paths.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	s := &projectSources{project: string(source), templateName: projectCfg.Template}
+
+	template, err := os.ReadFile(paths.TemplateMD)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read template.md: %w", err)
+	}
+	s.template = string(template)
+	s.templateEdit = s.template != "" && projectCfg.IsTemplateEdited(s.template)
+
+	detected, err := language.Detect(paths.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect languages: %w", err)
+	}
+	s.languageFiles, err = language.LoadLanguageFiles(detected, "", "", paths.LanguagesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project language configs: %w", err)
+	}
+	for _, lang := range detected {
+		if _, ok := s.languageFiles[lang]; ok {
+			s.languages = append(s.languages, lang)
+		}
+	}
+
+	return s, nil
+}
+
+// isPlain reports whether the output comes from project.md alone.
+func (s *projectSources) isPlain() bool {
+	return strings.TrimSpace(s.template) == "" && len(s.languages) == 0
+}
+
+// render returns the generated CLAUDE.md. A plain project gets project.md as
+// written; otherwise the template, project.md, and language files are merged
+// like the global config, with provenance markers.
+func (s *projectSources) render() string {
+	if s.isPlain() {
+		return projectHeader + strings.TrimSpace(s.project) + "\n"
+	}
+
+	var layers []merge.Layer
+	if strings.TrimSpace(s.template) != "" {
+		layers = append(layers, merge.Layer{Content: s.template, Source: "team"})
+	}
+	layers = append(layers, merge.Layer{Content: s.project, Source: "project"})
+
+	merged := merge.MergeWithLanguages(layers, merge.MergeOptions{
+		AnnotateSources: true,
+		OmitHeader:      true, // The date in the managed header would change the output daily
+		Languages:       s.languages,
+		LanguageFiles:   s.languageFiles,
+	})
+	return projectHeader + merged + "\n"
+}
+
+// projectOutput returns the CLAUDE.md a project's sources generate.
+func projectOutput(paths *config.ProjectPaths) (string, error) {
+	sources, err := loadProjectSources(paths)
+	if err != nil {
+		return "", err
+	}
+	return sources.render(), nil
+}
+
 // generateProjectOutput writes the generated CLAUDE.md file.
 func generateProjectOutput(paths *config.ProjectPaths) error {
-	content, err := os.ReadFile(paths.SourceMD)
+	output, err := projectOutput(paths)
 	if err != nil {
-		return fmt.Errorf("failed to read project.md: %w", err)
+		return err
 	}
 
-	output := projectHeader + strings.TrimSpace(string(content)) + "\n"
-
-	if err := os.WriteFile(paths.OutputMD, []byte(output), 0644); err != nil {
+	if err := fsutil.WriteFile(paths.OutputMD, []byte(output), 0644); err != nil {
 		return fmt.Errorf("failed to write CLAUDE.md: %w", err)
 	}
+	recordWrites(config.NewPaths(), map[string][]byte{paths.OutputMD: []byte(output)})
 
 	// Set modification time to now to ensure it's newer than source
 	now := time.Now()
@@ -427,13 +814,11 @@ func runProjectTemplates() error {
 		return err
 	}
 
-	owner, repo, err := cfg.DefaultOwnerRepo()
-	if err != nil {
+	if _, _, err := cfg.DefaultOwnerRepo(); err != nil {
 		return err
 	}
 
-	templatesDir := paths.TeamTemplatesDir(owner, repo)
-	templates := listAvailableTemplates(templatesDir)
+	templates, templatePaths := listTeamTemplates(cfg, paths)
 
 	if len(templates) == 0 {
 		fmt.Println("No templates available.")
@@ -448,8 +833,7 @@ func runProjectTemplates() error {
 
 	for _, name := range templates {
 		// Try to read description from first line of template
-		templatePath := filepath.Join(templatesDir, name+".md")
-		desc := getTemplateDescription(templatePath)
+		desc := getTemplateDescription(templatePaths[name])
 		fmt.Printf("  %-20s %s\n", info(name), desc)
 	}
 
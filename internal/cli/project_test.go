@@ -10,6 +10,7 @@ import (
 )
 
 func TestGenerateProjectOutput(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
 	// Create temp directory
 	tempDir := t.TempDir()
 	paths := config.NewProjectPaths(tempDir)
@@ -59,6 +60,7 @@ func TestGenerateProjectOutput(t *testing.T) {
 }
 
 func TestGenerateProjectOutput_TrimsWhitespace(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
 	tempDir := t.TempDir()
 	paths := config.NewProjectPaths(tempDir)
 
@@ -98,6 +100,100 @@ Content here
 	}
 }
 
+func TestGenerateProjectOutput_MergesTemplateAndLanguages(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewProjectPaths(t.TempDir())
+
+	files := map[string]string{
+		paths.TemplateMD: "# Backend Service\n\n## Testing\n\n- Table-driven tests\n\n## Deploys\n\n- Use the pipeline",
+		paths.SourceMD:   "# Project\n\n## Testing\n\n- Run make test\n\n## Context\n\n- Billing API",
+		filepath.Join(paths.LanguagesDir, "go.md"):     "## Errors\n\n- Wrap with %w",
+		filepath.Join(paths.LanguagesDir, "python.md"): "## Style\n\n- Use black",
+		filepath.Join(paths.Root, "go.mod"):            "module example.com/billing",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := generateProjectOutput(paths); err != nil {
+		t.Fatalf("generateProjectOutput failed: %v", err)
+	}
+	output, err := os.ReadFile(paths.OutputMD)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	outputStr := string(output)
+
+	if !strings.HasPrefix(outputStr, projectHeader) {
+		t.Error("output should start with the project header")
+	}
+	for _, want := range []string{
+		"<!-- staghorn:source:team -->\n# Backend Service",
+		"- Table-driven tests\n\n<!-- staghorn:source:project -->\n### Project Additions\n\n- Run make test",
+		"## Deploys",
+		"## Context\n\n- Billing API",
+		"## Go\n\n<!-- staghorn:source:project:go -->\n### Errors",
+	} {
+		if !strings.Contains(outputStr, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, outputStr)
+		}
+	}
+	if strings.Contains(outputStr, "Use black") {
+		t.Error("output should skip language files for languages not detected in the project")
+	}
+	if strings.Contains(outputStr, "Managed by staghorn") {
+		t.Error("output should not carry the dated managed header")
+	}
+}
+
+func TestRunProjectInit_WithTemplate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeLocalSource(t, map[string]string{
+		"CLAUDE.md":            "## Team\n\nUse tabs.",
+		"templates/backend.md": "# Backend\n\n## Testing\n\n- Table-driven tests",
+	})
+	if err := syncQuietly(t); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(projectDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	origDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(origDir) }()
+	if err := os.Chdir(projectDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := captureStdout(func() error { return runProjectInit("backend") }); err != nil {
+		t.Fatalf("runProjectInit failed: %v", err)
+	}
+
+	paths := config.NewProjectPaths(projectDir)
+	template, err := os.ReadFile(paths.TemplateMD)
+	if err != nil || !strings.Contains(string(template), "Table-driven tests") {
+		t.Errorf("template.md should hold the team template, got %q (%v)", template, err)
+	}
+	projectCfg, err := config.LoadProjectConfig(paths.ConfigFile)
+	if err != nil || projectCfg.Template != "backend" {
+		t.Errorf("config.yaml should record the template, got %+v (%v)", projectCfg, err)
+	}
+	source, err := os.ReadFile(paths.SourceMD)
+	if err != nil || string(source) != templateProjectStub {
+		t.Errorf("project.md should start as the template stub, got %q (%v)", source, err)
+	}
+	output, err := os.ReadFile(paths.OutputMD)
+	if err != nil || !strings.Contains(string(output), "Table-driven tests") {
+		t.Errorf("CLAUDE.md should include the template, got %q (%v)", output, err)
+	}
+}
+
 func TestListAvailableTemplates(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -297,3 +393,169 @@ func TestTeamTemplatesDir(t *testing.T) {
 		t.Errorf("TeamTemplatesDir should contain 'myorg-myrepo-templates', got %s", result)
 	}
 }
+
+func TestRunProjectSync(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectDir := t.TempDir()
+	files := map[string]string{
+		".staghorn/project.md":            "# Project\n\n## Context\n\n- API service",
+		".staghorn/rules/api.md":          "---\npaths:\n  - \"api/**\"\n---\n\n# API Rules\n\n- Validate input",
+		".staghorn/commands/deploy.md":    "---\nname: deploy\ndescription: Deploy the service\n---\n\nDeploy it.",
+		".staghorn/skills/ship/SKILL.md":  "---\nname: ship\ndescription: Ship a release\n---\n\nShip it.",
+		".claude/commands/mine.md":        "My own command",
+		".claude/commands/code-review.md": "<!-- Managed by staghorn | Source: starter | Do not edit directly -->\n\nReview.",
+	}
+	for rel, content := range files {
+		path := filepath.Join(projectDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	origDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(origDir) }()
+	if err := os.Chdir(projectDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := captureStdout(func() error { return runProjectSync(false) }); err != nil {
+		t.Fatalf("runProjectSync failed: %v", err)
+	}
+
+	for _, rel := range []string{"CLAUDE.md", ".claude/rules/api.md", ".claude/commands/deploy.md", ".claude/skills/ship/SKILL.md"} {
+		content, err := os.ReadFile(filepath.Join(projectDir, rel))
+		if err != nil {
+			t.Errorf("%s should be generated: %v", rel, err)
+			continue
+		}
+		if rel != "CLAUDE.md" && !strings.Contains(string(content), "Source: project") {
+			t.Errorf("%s should be managed as a project file, got %q", rel, content)
+		}
+	}
+	rule, _ := os.ReadFile(filepath.Join(projectDir, ".claude/rules/api.md"))
+	if !strings.HasPrefix(string(rule), "---\npaths:") {
+		t.Errorf("rule should keep its path frontmatter, got %q", rule)
+	}
+
+	// Deleting a rule from .staghorn/ prunes it, leaving files installed by hand alone
+	if err := os.Remove(filepath.Join(projectDir, ".staghorn/rules/api.md")); err != nil {
+		t.Fatal(err)
+	}
+	out, err := captureStdout(func() error { return runProjectSync(false) })
+	if err != nil {
+		t.Fatalf("runProjectSync failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".claude/rules/api.md")); !os.IsNotExist(err) {
+		t.Error("rule removed from .staghorn/ should be pruned")
+	}
+	if !strings.Contains(out, "Pruned 1 file(s)") {
+		t.Errorf("prune should be reported, got %q", out)
+	}
+	for _, rel := range []string{".claude/commands/mine.md", ".claude/commands/code-review.md"} {
+		if _, err := os.Stat(filepath.Join(projectDir, rel)); err != nil {
+			t.Errorf("%s wasn't installed by project sync and should be kept: %v", rel, err)
+		}
+	}
+}
+
+func TestRunProjectUpgrade_NoProjectRoot(t *testing.T) {
+	// A working directory that no longer exists has no project root
+	gone := filepath.Join(t.TempDir(), "gone")
+	if err := os.Mkdir(gone, 0755); err != nil {
+		t.Fatal(err)
+	}
+	origDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(origDir) }()
+	if err := os.Chdir(gone); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	if findProjectRoot() != "" {
+		t.Skip("working directory is still resolvable on this platform")
+	}
+
+	err := runProjectUpgrade("", false)
+	if err == nil || !strings.Contains(err.Error(), "no project root found") {
+		t.Errorf("runProjectUpgrade() error = %v, want no project root found", err)
+	}
+}
+
+func TestRunProjectUpgrade(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sourceDir := writeLocalSource(t, map[string]string{
+		"CLAUDE.md":            "## Team\n\nUse tabs.",
+		"templates/backend.md": "# Backend\n\n## Testing\n\n- Table-driven tests\n\n## Review\n\n- Two approvals",
+	})
+	if err := syncQuietly(t); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(projectDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	origDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(origDir) }()
+	if err := os.Chdir(projectDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := captureStdout(func() error { return runProjectInit("backend") }); err != nil {
+		t.Fatalf("runProjectInit failed: %v", err)
+	}
+
+	paths := config.NewProjectPaths(projectDir)
+	project := "# Project\n\n## Testing\n\n- Run make test\n\n## Review\n<!-- staghorn:replace -->\n\n- One approval"
+	if err := os.WriteFile(paths.SourceMD, []byte(project), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The team improves both sections of the template
+	next := "# Backend\n\n## Testing\n\n- Table-driven tests with t.Run\n\n## Review\n\n- Two approvals and a green build"
+	if err := os.WriteFile(filepath.Join(sourceDir, "templates", "backend.md"), []byte(next), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := syncQuietly(t); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	out, err := captureStdout(func() error { return runProjectUpgrade("", false) })
+	if err == nil || !strings.Contains(err.Error(), "1 conflict(s)") {
+		t.Fatalf("expected one conflict, got %v", err)
+	}
+	if !strings.Contains(out, "Review: replaced by the project, changed in the template") {
+		t.Errorf("conflict should be reported, got %q", out)
+	}
+
+	template, _ := os.ReadFile(paths.TemplateMD)
+	if string(template) != next {
+		t.Errorf("template.md should be upgraded, got %q", template)
+	}
+	projectCfg, _ := config.LoadProjectConfig(paths.ConfigFile)
+	if projectCfg.IsTemplateEdited(next) {
+		t.Error("the recorded hash should match the upgraded template")
+	}
+	output, _ := os.ReadFile(paths.OutputMD)
+	for _, want := range []string{"Table-driven tests with t.Run", "Run make test", "One approval"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("CLAUDE.md should contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Once upgraded, there's nothing left to do
+	out, err = captureStdout(func() error { return runProjectUpgrade("", false) })
+	if err != nil || !strings.Contains(out, "Already up to date") {
+		t.Errorf("second upgrade should be a no-op, got %q (%v)", out, err)
+	}
+
+	// Hand edits to template.md stop the upgrade
+	if err := os.WriteFile(paths.TemplateMD, []byte(next+"\n\n## Mine\n\n- Edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := captureStdout(func() error { return runProjectUpgrade("", false) }); err == nil || !strings.Contains(err.Error(), "edited by hand") {
+		t.Errorf("expected hand edits to template.md to stop the upgrade, got %v", err)
+	}
+}
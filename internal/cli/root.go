@@ -41,6 +41,11 @@ configs on top, and outputs a merged file for Claude to consume.`,
 	// Add subcommands
 	rootCmd.AddCommand(NewInitCmd())
 	rootCmd.AddCommand(NewSyncCmd())
+	rootCmd.AddCommand(NewOutdatedCmd())
+	rootCmd.AddCommand(NewHistoryCmd())
+	rootCmd.AddCommand(NewRollbackCmd())
+	rootCmd.AddCommand(NewApproveCmd())
+	rootCmd.AddCommand(NewRescueCmd())
 	rootCmd.AddCommand(NewSearchCmd())
 	rootCmd.AddCommand(NewEditCmd())
 	rootCmd.AddCommand(NewInfoCmd())
@@ -50,6 +55,7 @@ configs on top, and outputs a merged file for Claude to consume.`,
 	rootCmd.AddCommand(NewRunCmd())
 	rootCmd.AddCommand(NewLanguagesCmd())
 	rootCmd.AddCommand(NewSkillsCmd())
+	rootCmd.AddCommand(NewRulesCmd())
 	rootCmd.AddCommand(NewTeamCmd())
 	rootCmd.AddCommand(NewEvalCmd())
 	rootCmd.AddCommand(NewVersionCmd())
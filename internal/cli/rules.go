@@ -0,0 +1,456 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/rules"
+	"github.com/HartBrook/staghorn/internal/starter"
+	"github.com/spf13/cobra"
+)
+
+// NewRulesCmd creates the rules command.
+func NewRulesCmd() *cobra.Command {
+	var source string
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "List rules or show which rules apply to a file",
+		Long: `Lists all rules from team, personal, and project sources.
+
+Rules are markdown files that Claude Code applies to files matching the glob
+patterns in their 'paths:' frontmatter, or to every file without one. A rule
+in a higher precedence source (project > personal > team) overrides the rule
+at the same path in a lower one.`,
+		Example: `  staghorn rules                       # List all rules
+  staghorn rules -v                    # List with every path pattern
+  staghorn rules info api/rest.md      # Show info for a specific rule
+  staghorn rules match src/api/user.go # Show which rules apply to a file`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRulesList(source, verbose)
+		},
+	}
+
+	cmd.Flags().StringVar(&source, "source", "", "Filter by source (team, personal, project)")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show every path pattern")
+
+	// Add subcommands
+	cmd.AddCommand(NewRulesInfoCmd())
+	cmd.AddCommand(NewRulesInitCmd())
+	cmd.AddCommand(NewRulesMatchCmd())
+
+	return cmd
+}
+
+// NewRulesInfoCmd creates the 'rules info' command.
+func NewRulesInfoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "info <path>",
+		Short: "Show info for a specific rule",
+		Long: `Shows a rule's source, the files it applies to, and the versions it
+overrides. The rule is named by its path within rules/, with or without .md.`,
+		Example: `  staghorn rules info security
+  staghorn rules info api/rest.md`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRuleInfo(args[0])
+		},
+	}
+}
+
+// NewRulesInitCmd creates the 'rules init' command to bootstrap starter rules.
+func NewRulesInitCmd() *cobra.Command {
+	var project bool
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Install starter rules",
+		Long: `Installs staghorn's built-in starter rules to your personal or project config.
+
+Starter rules cover security, testing, error handling, REST APIs, and React
+components. Rules that already exist will be skipped.`,
+		Example: `  staghorn rules init            # Install to ~/.config/staghorn/rules/
+  staghorn rules init --project  # Install to .staghorn/rules/`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRulesInit(project)
+		},
+	}
+
+	cmd.Flags().BoolVar(&project, "project", false, "Install to project directory (.staghorn/rules/)")
+
+	return cmd
+}
+
+// NewRulesMatchCmd creates the 'rules match' command.
+func NewRulesMatchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "match <file>",
+		Short: "Show which rules apply to a file",
+		Long: `Shows which rules' path patterns match a file, and the source whose
+version of each rule wins. Path-scoped rules that don't match are listed too,
+with their patterns, to help track down a rule that isn't being applied.
+
+The file is resolved against the current directory and matched relative to
+the project root. It doesn't need to exist.`,
+		Example: `  staghorn rules match src/api/users.go
+  staghorn rules match app/page.tsx`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRulesMatch(args[0])
+		},
+	}
+}
+
+// loadRuleRegistry loads rules from all sources.
+func loadRuleRegistry() (*rules.Registry, error) {
+	paths := config.NewPaths()
+
+	var cfg *config.Config
+	if config.Exists() {
+		if loaded, err := config.Load(); err == nil {
+			cfg = loaded
+		}
+	}
+
+	// Find project root
+	projectRulesDir := ""
+	if projectRoot := findProjectRoot(); projectRoot != "" {
+		projectRulesDir = config.ProjectRulesDir(projectRoot)
+	}
+
+	registry, err := loadTeamRuleRegistry(cfg, paths, projectRulesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rules: %w", err)
+	}
+	return registry, nil
+}
+
+// loadTeamRuleRegistry loads personal and project rules, plus the team rules
+// cached for every source that owns some of them. A nil cfg or empty
+// projectDir skips that source. Rules that fail to parse or have invalid
+// paths patterns are skipped with a warning rather than failing the load.
+func loadTeamRuleRegistry(cfg *config.Config, paths *config.Paths, projectDir string) (*rules.Registry, error) {
+	registry, err := rules.LoadRegistry("", paths.PersonalRules, projectDir)
+	if err := warnSkippedRules(err); err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return registry, nil
+	}
+
+	var entries map[string]string
+	if cfg.Source.Multi != nil {
+		entries = cfg.Source.Multi.Rules
+	}
+	for _, src := range teamItemSources(cfg, entries, paths.TeamRulesDir) {
+		teamRules, err := rules.LoadValidFromDirectory(src.dir, rules.SourceTeam)
+		if err := warnSkippedRules(err); err != nil {
+			return nil, err
+		}
+		for _, rule := range teamRules {
+			if cfg.Source.RepoForRule(filepath.ToSlash(rule.RelPath)) == src.repo {
+				registry.Add(rule)
+			}
+		}
+	}
+	return registry, nil
+}
+
+// warnSkippedRules prints a warning for each rule a load skipped, returning
+// err only if it is something other than per-file parse failures.
+func warnSkippedRules(err error) error {
+	parseErrs, ok := err.(*rules.ParseErrors)
+	if !ok {
+		return err
+	}
+	for _, e := range parseErrs.Errors {
+		printWarning("Skipping rule: %v", e)
+	}
+	return nil
+}
+
+func runRulesList(sourceFilter string, verbose bool) error {
+	registry, err := loadRuleRegistry()
+	if err != nil {
+		return err
+	}
+
+	if registry.Count() == 0 {
+		fmt.Println("No rules found.")
+		fmt.Println()
+		fmt.Println("Rules are markdown files Claude Code applies to matching files:")
+		fmt.Println()
+		fmt.Println(dim("  ---"))
+		fmt.Println(dim("  paths:"))
+		fmt.Println(dim("    - \"src/api/**/*.go\""))
+		fmt.Println(dim("  ---"))
+		fmt.Println(dim("  # API Rules"))
+		fmt.Println()
+		fmt.Println(dim("Rules can come from:"))
+		fmt.Println(dim("  - Team repo (rules/ directory, synced via 'staghorn sync')"))
+		fmt.Println(dim("  - Personal (~/.config/staghorn/rules/)"))
+		fmt.Println(dim("  - Project (.staghorn/rules/)"))
+		fmt.Println()
+		fmt.Printf("Run %s to install starter rules.\n", info("staghorn rules init"))
+		return nil
+	}
+
+	filtered := registry.All()
+	if sourceFilter != "" {
+		var src rules.Source
+		switch sourceFilter {
+		case "team":
+			src = rules.SourceTeam
+		case "personal":
+			src = rules.SourcePersonal
+		case "project":
+			src = rules.SourceProject
+		default:
+			return fmt.Errorf("invalid source: %s (use team, personal, or project)", sourceFilter)
+		}
+		filtered = filterRulesBySource(filtered, src)
+	}
+
+	if len(filtered) == 0 {
+		fmt.Println("No rules match the filter.")
+		return nil
+	}
+
+	printed := false
+	for _, group := range []struct {
+		title  string
+		source rules.Source
+	}{
+		{"TEAM RULES", rules.SourceTeam},
+		{"PERSONAL RULES", rules.SourcePersonal},
+		{"PROJECT RULES", rules.SourceProject},
+	} {
+		ruleList := filterRulesBySource(filtered, group.source)
+		if len(ruleList) == 0 {
+			continue
+		}
+		if printed {
+			fmt.Println()
+		}
+		printRuleGroup(group.title, ruleList, registry, verbose)
+		printed = true
+	}
+
+	return nil
+}
+
+func filterRulesBySource(ruleList []*rules.Rule, source rules.Source) []*rules.Rule {
+	var result []*rules.Rule
+	for _, r := range ruleList {
+		if r.Source == source {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func printRuleGroup(title string, ruleList []*rules.Rule, registry *rules.Registry, verbose bool) {
+	fmt.Println(dim(title))
+	for _, r := range ruleList {
+		scope := ruleScope(r)
+		// Truncate scope if too long (unless verbose)
+		if !verbose && len(scope) > 50 {
+			scope = scope[:47] + "..."
+		}
+
+		line := fmt.Sprintf("  %-24s %s", info(r.RelPath), scope)
+		if overridden := overriddenSources(registry, r); overridden != "" {
+			line += " " + dim("(overrides "+overridden+")")
+		}
+		fmt.Println(line)
+	}
+}
+
+// ruleScope describes the files a rule applies to.
+func ruleScope(r *rules.Rule) string {
+	if !r.HasPathScope() {
+		return "all files"
+	}
+	return strings.Join(r.Paths, ", ")
+}
+
+// overriddenSources lists the sources of the versions r overrides, or "".
+func overriddenSources(registry *rules.Registry, r *rules.Rule) string {
+	var sources []string
+	for _, v := range registry.AllVersions(r.RelPath) {
+		if v != r {
+			sources = append(sources, v.Source.Label())
+		}
+	}
+	return strings.Join(sources, ", ")
+}
+
+func runRuleInfo(relPath string) error {
+	registry, err := loadRuleRegistry()
+	if err != nil {
+		return err
+	}
+
+	relPath = filepath.ToSlash(relPath)
+	if !strings.HasSuffix(relPath, ".md") {
+		relPath += ".md"
+	}
+
+	rule := registry.Get(filepath.FromSlash(relPath))
+	if rule == nil {
+		return fmt.Errorf("rule '%s' not found", relPath)
+	}
+
+	fmt.Println(dim("Rule:"), info(filepath.ToSlash(rule.RelPath)))
+	fmt.Println(dim("Source:"), rule.Source.Label())
+	fmt.Println(dim("File:"), displayPath(rule.FilePath))
+
+	fmt.Println()
+	if rule.HasPathScope() {
+		fmt.Println(dim("Applies to:"))
+		for _, pattern := range rule.Paths {
+			fmt.Printf("  %s\n", pattern)
+		}
+	} else {
+		fmt.Println(dim("Applies to:"), "all files")
+	}
+
+	// Show if overridden
+	versions := registry.AllVersions(rule.RelPath)
+	if len(versions) > 1 {
+		fmt.Println()
+		fmt.Println(dim("Versions:"))
+		for _, v := range versions {
+			active := ""
+			if v == rule {
+				active = " (active)"
+			}
+			fmt.Printf("  %-10s %s%s\n", v.Source.Label(), ruleScope(v), active)
+		}
+	}
+
+	return nil
+}
+
+func runRulesInit(project bool) error {
+	paths := config.NewPaths()
+
+	var targetDir string
+	var targetLabel string
+	var next string
+
+	if project {
+		projectRoot := findProjectRoot()
+		if projectRoot == "" {
+			return fmt.Errorf("no project root found (looking for .git or .staghorn directory)")
+		}
+		targetDir = config.ProjectRulesDir(projectRoot)
+		targetLabel = ".staghorn/rules/"
+		next = "staghorn project sync"
+	} else {
+		targetDir = paths.PersonalRules
+		targetLabel = "~/.config/staghorn/rules/"
+		next = "staghorn sync"
+	}
+
+	fmt.Printf("Installing starter rules to %s\n", targetLabel)
+	fmt.Println()
+
+	// Show available rules
+	ruleNames := starter.RuleNames()
+	fmt.Printf("Available starter rules (%d):\n", len(ruleNames))
+	for _, name := range ruleNames {
+		fmt.Printf("  - %s\n", info(name))
+	}
+	fmt.Println()
+
+	// Install starter rules
+	count, installed, err := starter.BootstrapRulesWithSkip(targetDir, nil)
+	if err != nil {
+		return fmt.Errorf("failed to install starter rules: %w", err)
+	}
+
+	if count == 0 {
+		fmt.Println(dim("All starter rules already installed."))
+	} else {
+		printSuccess("Installed %d starter rules:", count)
+		for _, name := range installed {
+			fmt.Printf("  - %s\n", info(name))
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Run %s to apply them to Claude Code.\n", info(next))
+
+	return nil
+}
+
+func runRulesMatch(file string) error {
+	registry, err := loadRuleRegistry()
+	if err != nil {
+		return err
+	}
+
+	target := projectRelativePath(file)
+
+	var matched, unmatched []*rules.Rule
+	for _, r := range registry.All() {
+		if _, ok := r.Matches(target); ok {
+			matched = append(matched, r)
+		} else {
+			unmatched = append(unmatched, r)
+		}
+	}
+
+	if len(matched) == 0 {
+		fmt.Printf("No rules apply to %s\n", info(target))
+	} else {
+		fmt.Printf("Rules applied to %s:\n", info(target))
+		for _, r := range matched {
+			pattern, _ := r.Matches(target)
+			why := "all files"
+			if pattern != "" {
+				why = "matches " + pattern
+			}
+			line := fmt.Sprintf("  %-24s %-10s %s", info(filepath.ToSlash(r.RelPath)), r.Source.Label(), why)
+			if overridden := overriddenSources(registry, r); overridden != "" {
+				line += " " + dim("(overrides "+overridden+")")
+			}
+			fmt.Println(line)
+		}
+	}
+
+	if len(unmatched) > 0 {
+		fmt.Println()
+		fmt.Println(dim("Not applied:"))
+		for _, r := range unmatched {
+			fmt.Printf("  %-24s %-10s %s\n", info(filepath.ToSlash(r.RelPath)), r.Source.Label(), ruleScope(r))
+			// A lower precedence version matching explains a rule that used to apply
+			for _, v := range registry.AllVersions(r.RelPath) {
+				if _, ok := v.Matches(target); ok && v != r {
+					fmt.Printf("  %s\n", dim(fmt.Sprintf("  the %s version matches, but %s overrides it", v.Source.Label(), r.Source.Label())))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// projectRelativePath returns file, resolved against the current directory,
+// as a slash-separated path relative to the project root. Paths outside the
+// project, or with no project root, are returned as given.
+func projectRelativePath(file string) string {
+	if root := findProjectRoot(); root != "" {
+		if abs, err := filepath.Abs(file); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	return filepath.ToSlash(file)
+}
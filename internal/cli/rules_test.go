@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunRulesMatch(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	projectDir := t.TempDir()
+	files := map[string]string{
+		filepath.Join(home, ".config/staghorn/rules/api/rest.md"): "---\npaths:\n  - \"src/api/**\"\n---\n\n# REST",
+		filepath.Join(home, ".config/staghorn/rules/react.md"):    "---\npaths:\n  - \"src/components/**/*.tsx\"\n---\n\n# React",
+		filepath.Join(projectDir, ".staghorn/rules/api/rest.md"):  "---\npaths:\n  - \"api/**\"\n---\n\n# Project REST",
+		filepath.Join(projectDir, ".staghorn/rules/security.md"):  "# Security",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	origDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(origDir) }()
+	if err := os.Chdir(filepath.Join(projectDir, ".staghorn")); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := captureStdout(func() error { return runRulesMatch("../src/api/users.go") })
+	if err != nil {
+		t.Fatalf("runRulesMatch failed: %v", err)
+	}
+
+	applied, notApplied, _ := strings.Cut(out, "Not applied:")
+	if !strings.Contains(applied, "src/api/users.go") {
+		t.Errorf("file should be shown relative to the project root:\n%s", out)
+	}
+	if !strings.Contains(applied, "security.md") || !strings.Contains(applied, "all files") {
+		t.Errorf("unscoped rule should apply:\n%s", out)
+	}
+	if !strings.Contains(notApplied, "api/rest.md") || !strings.Contains(notApplied, "the personal version matches, but project overrides it") {
+		t.Errorf("overridden match should be explained:\n%s", out)
+	}
+	if !strings.Contains(notApplied, "react.md") {
+		t.Errorf("unmatched rule should be listed:\n%s", out)
+	}
+}
+
+func TestRunRuleInfo(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".config/staghorn/rules/api/rest.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("---\npaths:\n  - \"src/api/**\"\n---\n\n# REST"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(origDir) }()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := captureStdout(func() error { return runRuleInfo("api/rest") })
+	if err != nil {
+		t.Fatalf("runRuleInfo failed: %v", err)
+	}
+	if !strings.Contains(out, "personal") || !strings.Contains(out, "src/api/**") {
+		t.Errorf("info should show source and patterns:\n%s", out)
+	}
+
+	if _, err := captureStdout(func() error { return runRuleInfo("missing") }); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+}
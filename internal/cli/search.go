@@ -18,6 +18,7 @@ type searchOptions struct {
 	tag      string
 	language string
 	limit    int
+	host     string
 }
 
 // NewSearchCmd creates the search command.
@@ -36,7 +37,8 @@ You can filter by language or additional tags using the --lang and --tag flags.`
 		Example: `  staghorn search              # List all public configs
   staghorn search python       # Search for "python" in config name/description
   staghorn search --lang go    # Filter to configs supporting Go
-  staghorn search --tag web    # Filter by topic/tag`,
+  staghorn search --tag web    # Filter by topic/tag
+  staghorn search --host ghe.example.com  # Search a GitHub Enterprise Server`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			query := ""
 			if len(args) > 0 {
@@ -48,6 +50,7 @@ You can filter by language or additional tags using the --lang and --tag flags.`
 
 	cmd.Flags().StringVar(&opts.tag, "tag", "", "Filter by topic/tag")
 	cmd.Flags().StringVar(&opts.language, "lang", "", "Filter by language")
+	cmd.Flags().StringVar(&opts.host, "host", "", "GitHub Enterprise Server hostname (default: github.host or github.com)")
 	cmd.Flags().IntVar(&opts.limit, "limit", 20, "Maximum results to show")
 
 	return cmd
@@ -60,14 +63,13 @@ func runSearch(query string, opts *searchOptions) error {
 	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
 	defer cancel()
 
-	// Try unauthenticated first for public repos
-	client, err := github.NewUnauthenticatedClient()
+	host := opts.host
+	if host == "" {
+		host = existingGitHubHost()
+	}
+	client, err := newSearchClient(host)
 	if err != nil {
-		// Fall back to authenticated
-		client, err = github.NewClient()
-		if err != nil {
-			return fmt.Errorf("failed to create GitHub client: %w", err)
-		}
+		return err
 	}
 
 	results, err := client.SearchConfigs(ctx, query)
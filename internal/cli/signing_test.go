@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// minisignKey generates a key pair and returns the public key in minisign
+// format and a function that signs the checksums manifest in sourceDir.
+func minisignKey(t *testing.T) (string, func(sourceDir string)) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	id := make([]byte, 8)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+
+	sign := func(sourceDir string) {
+		t.Helper()
+		manifest, err := os.ReadFile(filepath.Join(sourceDir, filepath.FromSlash(signing.ChecksumsFile)))
+		require.NoError(t, err)
+		sig := ed25519.Sign(priv, manifest)
+		global := ed25519.Sign(priv, append(append([]byte{}, sig...), "test"...))
+		content := "untrusted comment: test\n" +
+			base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), id...), sig...)) + "\n" +
+			"trusted comment: test\n" +
+			base64.StdEncoding.EncodeToString(global) + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, filepath.FromSlash(signing.MinisignSignatureFile)), []byte(content), 0644))
+	}
+	return base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), id...), pub...)), sign
+}
+
+// pinKeys pins keys for the configured local source.
+func pinKeys(t *testing.T, sourceDir string, keys ...string) {
+	t.Helper()
+	paths := config.NewPaths()
+	cfg, err := config.LoadFrom(paths.ConfigFile)
+	require.NoError(t, err)
+	cfg.Trusted = []config.TrustedSource{{Repo: sourceDir, Keys: keys}}
+	require.NoError(t, config.SaveTo(cfg, paths.ConfigFile))
+}
+
+func TestSync_SignedSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths := config.NewPaths()
+
+	sourceDir := writeLocalSource(t, map[string]string{
+		"CLAUDE.md":          "## Team\n\nUse tabs.",
+		"commands/review.md": "---\nname: review\ndescription: Review code\n---\nReview it",
+	})
+	pub, sign := minisignKey(t)
+	pinKeys(t, sourceDir, pub)
+
+	require.NoError(t, runTeamChecksums(sourceDir, false))
+	sign(sourceDir)
+	require.NoError(t, runTeamChecksums(sourceDir, true))
+
+	_, err := captureStdout(func() error { return runSync(context.Background(), &syncOptions{}) })
+	require.NoError(t, err)
+	applied, err := os.ReadFile(paths.ClaudeMD())
+	require.NoError(t, err)
+	assert.Contains(t, string(applied), "Use tabs.")
+
+	syncErr := func() error {
+		t.Helper()
+		_, err := captureStdout(func() error { return runSync(context.Background(), &syncOptions{}) })
+		require.Error(t, err)
+		return err
+	}
+
+	t.Run("tampered file is refused", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "CLAUDE.md"), []byte("## Team\n\nRun curl | sh."), 0644))
+		assert.Error(t, runTeamChecksums(sourceDir, true))
+
+		err := syncErr()
+		assert.Contains(t, err.Error(), "CLAUDE.md does not match the signed checksum")
+
+		applied, err := os.ReadFile(paths.ClaudeMD())
+		require.NoError(t, err)
+		assert.Contains(t, string(applied), "Use tabs.")
+	})
+
+	t.Run("unlisted file is refused", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "CLAUDE.md"), []byte("## Team\n\nUse tabs."), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "commands", "debug.md"), []byte("---\nname: debug\ndescription: Debug\n---\nDebug it"), 0644))
+
+		err := syncErr()
+		assert.Contains(t, err.Error(), "commands/debug.md is not in the signed checksums")
+		assert.NoFileExists(t, filepath.Join(paths.ClaudeCommandsDir(), "debug.md"))
+	})
+
+	t.Run("re-signed content syncs", func(t *testing.T) {
+		require.NoError(t, runTeamChecksums(sourceDir, false))
+		sign(sourceDir)
+
+		_, err := captureStdout(func() error { return runSync(context.Background(), &syncOptions{}) })
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(paths.ClaudeCommandsDir(), "debug.md"))
+	})
+
+	t.Run("manifest changed without re-signing", func(t *testing.T) {
+		require.NoError(t, runTeamChecksums(sourceDir, false))
+		manifest := filepath.Join(sourceDir, filepath.FromSlash(signing.ChecksumsFile))
+		content, err := os.ReadFile(manifest)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(manifest, append(content, ' '), 0644))
+
+		err = syncErr()
+		assert.Contains(t, err.Error(), "invalid minisign signature")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		require.NoError(t, runTeamChecksums(sourceDir, false))
+		sign(sourceDir)
+		other, _ := minisignKey(t)
+		pinKeys(t, sourceDir, other)
+
+		err := syncErr()
+		assert.Contains(t, err.Error(), "not signed by a pinned key")
+	})
+
+	t.Run("unsigned source", func(t *testing.T) {
+		pinKeys(t, sourceDir, pub)
+		require.NoError(t, os.Remove(filepath.Join(sourceDir, filepath.FromSlash(signing.MinisignSignatureFile))))
+
+		err := syncErr()
+		assert.Contains(t, err.Error(), "no signature")
+	})
+}
+
+func TestSync_UnpinnedSourceSkipsVerification(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeLocalSource(t, map[string]string{"CLAUDE.md": "## Team\n\nUse tabs."})
+
+	_, err := captureStdout(func() error { return runSync(context.Background(), &syncOptions{}) })
+	require.NoError(t, err)
+}
@@ -7,17 +7,23 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/HartBrook/staghorn/internal/cache"
 	"github.com/HartBrook/staghorn/internal/commands"
 	"github.com/HartBrook/staghorn/internal/config"
 	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/fsutil"
 	"github.com/HartBrook/staghorn/internal/github"
 	"github.com/HartBrook/staghorn/internal/language"
+	"github.com/HartBrook/staghorn/internal/lockfile"
+	"github.com/HartBrook/staghorn/internal/manifest"
 	"github.com/HartBrook/staghorn/internal/merge"
 	"github.com/HartBrook/staghorn/internal/optimize"
+	"github.com/HartBrook/staghorn/internal/provider"
 	"github.com/HartBrook/staghorn/internal/rules"
+	"github.com/HartBrook/staghorn/internal/signing"
 	"github.com/HartBrook/staghorn/internal/skills"
 	"github.com/spf13/cobra"
 )
@@ -33,6 +39,15 @@ type syncOptions struct {
 	fetchOnly     bool
 	applyOnly     bool
 	claudeOnly    bool
+	frozen        bool
+	noPrune       bool
+	dryRun        bool
+	output        string // Dry-run plan format: "text" or "json"
+}
+
+// isPartial returns true if only a subset of content types is being synced.
+func (o *syncOptions) isPartial() bool {
+	return o.configOnly || o.commandsOnly || o.languagesOnly || o.rulesOnly || o.skillsOnly || o.claudeOnly
 }
 
 // shouldSyncConfig returns true if base config should be synced.
@@ -87,9 +102,263 @@ func (o *syncOptions) shouldSyncClaudeSkills() bool {
 
 // repoContext holds the branch info for a single repo.
 type repoContext struct {
-	owner  string
-	repo   string
-	branch string
+	owner  string // Cache key owner (see config.RepoSpec.CacheKey)
+	repo   string // Cache key repo
+	branch string // Pinned ref or the repo's default branch
+	commit string // Commit SHA the branch resolved to (empty if unresolved)
+
+	previous string                // Commit synced last time (empty if unknown), for the upstream changelog
+	sections *merge.SectionChanges // CLAUDE.md sections changed by this sync (nil if none or not fetched)
+
+	locked  *lockfile.Source   // Locked entry to verify against (frozen mode only)
+	fetched *lockfile.Recorder // Files fetched from this repo during the sync
+
+	spec     *config.RepoSpec        // Parsed source (nil in tests that only exercise caching)
+	provider provider.SourceProvider // Fetches files from the source's host
+
+	tree  *provider.Tree // Recursive tree at commit, used to skip unchanged files (nil if unavailable)
+	blobs *cache.Cache   // Blob cache for files already downloaded (nil disables reuse)
+
+	concurrency int     // Max parallel fetches from this repo
+	pruner      *pruner // Removes cached files deleted upstream (nil disables tracking)
+
+	checksums *signing.Checksums // Signed hashes fetched files must match (nil if no keys are pinned)
+	mu        sync.Mutex
+	unsigned  []string // Files rejected by checksums, reported once fetching is done
+}
+
+// fullName returns the source's display name, as recorded in the lockfile.
+func (rc *repoContext) fullName() string {
+	if rc.spec != nil {
+		return rc.spec.FullName()
+	}
+	return rc.owner + "/" + rc.repo
+}
+
+// remote returns the owner and repo to request from the provider.
+func (rc *repoContext) remote() (owner, repo string) {
+	if rc.spec != nil {
+		return rc.spec.Owner, rc.spec.Repo
+	}
+	return rc.owner, rc.repo
+}
+
+// sourcePath returns the repo path of a file in the source layout, which
+// differs when the source is rooted in a subdirectory of its repo. Paths
+// everywhere else, including the lockfile and checksums, are relative to
+// the source root.
+func (rc *repoContext) sourcePath(path string) string {
+	if rc.spec != nil {
+		return rc.spec.SourcePath(path)
+	}
+	return path
+}
+
+// fetchRef returns the ref to request files at. Fetching by resolved commit
+// keeps every file in a sync consistent even if the branch moves mid-sync.
+func (rc *repoContext) fetchRef() string {
+	if rc.commit != "" {
+		return rc.commit
+	}
+	return rc.branch
+}
+
+// loadTree fetches the repo tree once so directory listings and unchanged files
+// can be served without per-file API calls. Falls back to per-directory listings
+// if the provider can't list trees or the tree is truncated.
+func (rc *repoContext) loadTree(ctx context.Context, blobs *cache.Cache) {
+	rc.blobs = blobs
+
+	lister, ok := rc.provider.(provider.TreeLister)
+	if !ok {
+		return
+	}
+	owner, repo := rc.remote()
+	tree, err := lister.GetTree(ctx, owner, repo, rc.fetchRef())
+	if err != nil || tree.Truncated {
+		return
+	}
+	rc.tree = tree
+}
+
+// listDirectory lists a remote directory, from the tree when it has been loaded.
+// Returns nil, nil if the directory doesn't exist.
+func (rc *repoContext) listDirectory(ctx context.Context, path string) ([]provider.DirectoryEntry, error) {
+	var entries []provider.DirectoryEntry
+	if rc.tree != nil {
+		entries = rc.tree.List(rc.sourcePath(path))
+	} else {
+		owner, repo := rc.remote()
+		var err error
+		entries, err = rc.provider.ListDirectory(ctx, owner, repo, rc.sourcePath(path), rc.fetchRef())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rc.spec != nil && rc.spec.Subdir != "" {
+		for i := range entries {
+			entries[i].Path = strings.TrimPrefix(entries[i].Path, rc.spec.Subdir+"/")
+		}
+	}
+	return entries, nil
+}
+
+// fetchFile fetches a file from the repo and records it for the lockfile.
+// Files whose blob SHA is already cached are served locally without an API call.
+// In frozen mode, files that don't match the lockfile are rejected.
+func (rc *repoContext) fetchFile(ctx context.Context, path string) (*provider.FetchResult, error) {
+	if result, ok := rc.cachedFile(path); ok {
+		return result, rc.record(path, result)
+	}
+
+	owner, repo := rc.remote()
+	result, err := rc.provider.FetchFile(ctx, owner, repo, rc.sourcePath(path), rc.fetchRef())
+	if err != nil {
+		return nil, err
+	}
+	rc.storeBlob(result)
+
+	return result, rc.record(path, result)
+}
+
+// fetchConfigFile fetches the team CLAUDE.md, sending the cached ETag so an
+// unchanged file costs a 304 instead of a full download.
+func (rc *repoContext) fetchConfigFile(ctx context.Context, c *cache.Cache) (*provider.FetchResult, error) {
+	cachedContent, meta, err := c.Read(rc.owner, rc.repo)
+	if err != nil {
+		meta = &cache.Metadata{}
+	}
+
+	if result, ok := rc.cachedFile(config.DefaultPath); ok {
+		if result.SHA == meta.SHA {
+			result.ETag = meta.ETag // Still valid for the next conditional fetch
+		}
+		return result, rc.record(config.DefaultPath, result)
+	}
+
+	fetcher, ok := rc.provider.(provider.ConditionalFetcher)
+	if !ok || meta.ETag == "" || meta.SHA == "" {
+		return rc.fetchFile(ctx, config.DefaultPath)
+	}
+
+	owner, repo := rc.remote()
+	result, err := fetcher.FetchFileIfChanged(ctx, owner, repo, rc.sourcePath(config.DefaultPath), rc.fetchRef(), meta.ETag)
+	if err != nil {
+		return nil, err
+	}
+	if result.NotModified {
+		result.Content = cachedContent
+		result.SHA = meta.SHA
+	} else {
+		rc.storeBlob(result)
+	}
+
+	return result, rc.record(config.DefaultPath, result)
+}
+
+// cachedFile returns a file from the blob cache if the tree shows it is unchanged.
+func (rc *repoContext) cachedFile(path string) (*provider.FetchResult, bool) {
+	if rc.tree == nil || rc.blobs == nil {
+		return nil, false
+	}
+	entry := rc.tree.Find(rc.sourcePath(path))
+	if entry == nil {
+		return nil, false
+	}
+	content, ok := rc.blobs.ReadBlob(entry.SHA)
+	if !ok {
+		return nil, false
+	}
+	return &provider.FetchResult{Content: content, SHA: entry.SHA}, true
+}
+
+// storeBlob saves a downloaded file to the blob cache so later syncs can skip it.
+func (rc *repoContext) storeBlob(result *provider.FetchResult) {
+	if rc.blobs == nil {
+		return
+	}
+	// The blob cache is an optimization; a failed write just means a re-download next time
+	_ = rc.blobs.WriteBlob(result.SHA, result.Content)
+}
+
+// record notes a fetched file for the lockfile and verifies it against the
+// signed checksums (if keys are pinned) and, in frozen mode, the lockfile.
+func (rc *repoContext) record(path string, result *provider.FetchResult) error {
+	if rc.checksums != nil {
+		if err := rc.checksums.Check(path, []byte(result.Content)); err != nil {
+			rc.mu.Lock()
+			rc.unsigned = append(rc.unsigned, err.Error())
+			rc.mu.Unlock()
+			return errors.SignatureInvalid(rc.fullName(), err.Error())
+		}
+	}
+
+	if rc.fetched != nil {
+		rc.fetched.Record(path, result.SHA)
+	}
+
+	if rc.locked != nil {
+		if locked := rc.locked.FindFile(path); locked == nil || locked.SHA != result.SHA {
+			return errors.LockfileMismatch(fmt.Sprintf("%s: %s differs from the locked version", rc.fullName(), path))
+		}
+	}
+
+	return nil
+}
+
+// loadChecksums fetches the source's checksums manifest and verifies its
+// signature when keys are pinned for it. Every file fetched afterwards must
+// match the manifest.
+func (rc *repoContext) loadChecksums(ctx context.Context, cfg *config.Config) error {
+	pinned := cfg.SigningKeys(rc.fullName())
+	if len(pinned) == 0 {
+		return nil
+	}
+	keys, err := signing.ParseKeys(pinned)
+	if err != nil {
+		return errors.SignatureInvalid(rc.fullName(), err.Error())
+	}
+
+	owner, repo := rc.remote()
+	manifest, err := rc.provider.FetchFile(ctx, owner, repo, rc.sourcePath(signing.ChecksumsFile), rc.fetchRef())
+	if err != nil {
+		return errors.SignatureInvalid(rc.fullName(), fmt.Sprintf("no %s: %v", signing.ChecksumsFile, err))
+	}
+
+	verifyErr := fmt.Errorf("no signature (%s or %s)", signing.SSHSignatureFile, signing.MinisignSignatureFile)
+	for _, name := range []string{signing.SSHSignatureFile, signing.MinisignSignatureFile} {
+		sig, err := rc.provider.FetchFile(ctx, owner, repo, rc.sourcePath(name), rc.fetchRef())
+		if err != nil {
+			continue
+		}
+		if verifyErr = signing.VerifyAny([]byte(manifest.Content), []byte(sig.Content), keys); verifyErr == nil {
+			break
+		}
+	}
+	if verifyErr != nil {
+		return errors.SignatureInvalid(rc.fullName(), verifyErr.Error())
+	}
+
+	checksums, err := signing.ParseChecksums([]byte(manifest.Content))
+	if err != nil {
+		return errors.SignatureInvalid(rc.fullName(), err.Error())
+	}
+	rc.checksums = checksums
+	return nil
+}
+
+// checkSignedContent fails the sync if any fetched file didn't match its
+// source's signed checksums. It runs before anything is applied, so rejected
+// content never reaches Claude Code.
+func checkSignedContent(contexts []*repoContext) error {
+	for _, rc := range contexts {
+		if len(rc.unsigned) > 0 {
+			sort.Strings(rc.unsigned)
+			return errors.SignatureInvalid(rc.fullName(), strings.Join(rc.unsigned, ", "))
+		}
+	}
+	return nil
 }
 
 // NewSyncCmd creates the sync command.
@@ -105,6 +374,10 @@ func NewSyncCmd() *cobra.Command {
 This is the main command for keeping your Claude Code config up to date.`,
 		Example: `  staghorn sync
   staghorn sync --force
+  staghorn sync --frozen
+  staghorn sync --no-prune
+  staghorn sync --dry-run
+  staghorn sync --dry-run --output json
   staghorn sync --fetch-only
   staghorn sync --apply-only`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -122,28 +395,307 @@ This is the main command for keeping your Claude Code config up to date.`,
 	cmd.Flags().BoolVar(&opts.rulesOnly, "rules-only", false, "Only sync rules, skip config, commands, and languages")
 	cmd.Flags().BoolVar(&opts.skillsOnly, "skills-only", false, "Only sync skills, skip config, commands, languages, and rules")
 	cmd.Flags().BoolVar(&opts.claudeOnly, "claude-only", false, "Only sync commands, rules, and skills to ~/.claude/, skip config apply")
+	cmd.Flags().BoolVar(&opts.frozen, "frozen", false, "Install exactly the files in staghorn.lock, failing if they differ")
+	cmd.Flags().BoolVar(&opts.noPrune, "no-prune", false, "Keep previously synced files that were removed upstream")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show what sync would change without touching the cache or ~/.claude")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "text", "Dry-run output format: text or json")
 
 	return cmd
 }
 
+// pruner records which files sync installed into each target directory and
+// removes previously installed files that no longer exist upstream.
+type pruner struct {
+	enabled bool // False with --no-prune: stale files are kept but still tracked
+	seed    bool // Seed missing manifests from the files already in a directory
+
+	mu      sync.Mutex
+	removed []string // Paths removed during this sync
+}
+
+// newPruner creates a pruner. When enabled is false, nothing is removed.
+func newPruner(enabled bool) *pruner {
+	return &pruner{enabled: enabled, seed: true}
+}
+
+// track records current (slash-separated paths relative to dir) as the files now
+// installed in dir, removing files installed by a previous sync that aren't in current.
+// isManaged guards against removing files the user has taken over; nil treats every
+// file as staghorn's. Directories without a manifest are seeded from their contents
+// unless seeding is off, but only when staghorn owns them outright (isManaged is nil):
+// in directories shared with the user there's no telling which files were installed,
+// so the first sync only records a manifest and later syncs prune against it.
+func (p *pruner) track(dir string, current []string, isManaged func(rel string) bool) error {
+	if p == nil {
+		return nil
+	}
+
+	prev, err := manifest.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	var previous []string
+	if prev != nil {
+		previous = prev.Files
+	} else if p.seed && isManaged == nil {
+		scanned, err := manifest.Scan(dir)
+		if err != nil {
+			return err
+		}
+		previous = scanned
+	}
+
+	// Files the user has taken over are dropped from tracking, never removed
+	var stale []string
+	for _, f := range manifest.Stale(previous, current) {
+		if isManaged == nil || isManaged(f) {
+			stale = append(stale, f)
+		}
+	}
+
+	files := append([]string{}, current...)
+	if p.enabled {
+		removed, err := manifest.Remove(dir, stale)
+		p.mu.Lock()
+		for _, f := range removed {
+			p.removed = append(p.removed, filepath.Join(dir, filepath.FromSlash(f)))
+		}
+		p.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to prune %s: %w", dir, err)
+		}
+	} else {
+		files = append(files, stale...) // Keep tracking so a later sync can prune them
+	}
+
+	if prev == nil && len(files) == 0 {
+		return nil // Nothing installed and nothing to remember
+	}
+	return (&manifest.Manifest{Files: files}).Save(dir)
+}
+
+// report prints a summary of files removed during the sync.
+func (p *pruner) report() {
+	if p == nil || len(p.removed) == 0 {
+		return
+	}
+
+	printSuccess("Pruned %d file(s) removed upstream", len(p.removed))
+	for _, path := range p.removed {
+		fmt.Printf("  %s %s\n", dim("-"), displayPath(path))
+	}
+}
+
+// displayPath shortens paths under the home directory to start with ~.
+func displayPath(path string) string {
+	home, _ := os.UserHomeDir()
+	if home != "" {
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join("~", rel)
+		}
+	}
+	return path
+}
+
+// relPaths returns each job's local path relative to dir, slash-separated.
+func relPaths(dir string, jobs []fileJob) []string {
+	var rels []string
+	for _, job := range jobs {
+		if rel, err := filepath.Rel(dir, job.localPath); err == nil {
+			rels = append(rels, filepath.ToSlash(rel))
+		}
+	}
+	return rels
+}
+
+// fileJob is a remote file to fetch and write to a local path.
+type fileJob struct {
+	rc        *repoContext
+	path      string // Remote path in the repo
+	localPath string // Where to write the fetched content
+	itemType  string // Human-readable name for warnings (e.g., "rule")
+	name      string // Display name for warnings
+	group     string // Item the file belongs to (e.g., a skill name), for counting
+}
+
+// fetchOutcome is the result of fetching a single fileJob.
+type fetchOutcome struct {
+	result *provider.FetchResult
+	err    error
+}
+
+// runConcurrently calls fn for each index in [0, n) with at most limit calls in flight.
+func runConcurrently(n, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// fetchConcurrently fetches jobs with at most limit requests in flight.
+// Outcomes are returned in job order so warnings and counts stay deterministic.
+func fetchConcurrently(ctx context.Context, jobs []fileJob, limit int) []fetchOutcome {
+	outcomes := make([]fetchOutcome, len(jobs))
+	runConcurrently(len(jobs), limit, func(i int) {
+		result, err := jobs[i].rc.fetchFile(ctx, jobs[i].path)
+		outcomes[i] = fetchOutcome{result: result, err: err}
+	})
+	return outcomes
+}
+
+// fetchAndWrite fetches jobs concurrently, then writes them to disk in job order.
+// Failures are reported as warnings. Returns the jobs that were written.
+func fetchAndWrite(ctx context.Context, jobs []fileJob, limit int) []fileJob {
+	outcomes := fetchConcurrently(ctx, jobs, limit)
+
+	var written []fileJob
+	for i, job := range jobs {
+		if outcomes[i].err != nil {
+			printWarning("Failed to fetch %s %s: %v", job.itemType, job.name, outcomes[i].err)
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(job.localPath), 0755); err != nil {
+			printWarning("Failed to create directory for %s %s: %v", job.itemType, job.name, err)
+			continue
+		}
+
+		if err := fsutil.WriteFile(job.localPath, []byte(outcomes[i].result.Content), 0644); err != nil {
+			printWarning("Failed to write %s %s: %v", job.itemType, job.name, err)
+			continue
+		}
+
+		written = append(written, job)
+	}
+
+	return written
+}
+
+// countGroups returns the number of distinct groups among jobs.
+func countGroups(jobs []fileJob) int {
+	groups := make(map[string]bool)
+	for _, job := range jobs {
+		groups[job.group] = true
+	}
+	return len(groups)
+}
+
+// listFilesRecursive walks a remote directory and returns a job for every file
+// accepted by keep (nil keeps all files), mirroring the remote layout under localBase.
+// Returns nil, nil if the directory doesn't exist.
+func listFilesRecursive(ctx context.Context, rc *repoContext, remotePath, localBase, itemType string, keep func(name string) bool) ([]fileJob, error) {
+	entries, err := rc.listDirectory(ctx, remotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []fileJob
+	for _, entry := range entries {
+		localPath := filepath.Join(localBase, entry.Name)
+
+		switch entry.Type {
+		case "dir":
+			subJobs, err := listFilesRecursive(ctx, rc, entry.Path, localPath, itemType, keep)
+			if err != nil {
+				// Fail the whole listing so a partial view never prunes files that still exist
+				return nil, fmt.Errorf("%s subdirectory %s: %w", itemType, entry.Name, err)
+			}
+			jobs = append(jobs, subJobs...)
+		case "file":
+			if keep != nil && !keep(entry.Name) {
+				continue
+			}
+			jobs = append(jobs, fileJob{
+				rc:        rc,
+				path:      entry.Path,
+				localPath: localPath,
+				itemType:  itemType,
+				name:      entry.Name,
+			})
+		}
+	}
+
+	return jobs, nil
+}
+
+// isMarkdown reports whether a file name has a .md extension.
+func isMarkdown(name string) bool {
+	return strings.HasSuffix(name, ".md")
+}
+
 func runSync(ctx context.Context, opts *syncOptions) error {
 	paths := config.NewPaths()
 
+	// A dry run takes the lock itself and syncs into a staged copy
+	if opts.dryRun {
+		return runSyncDryRun(ctx, opts, paths)
+	}
+	if opts.output != "" && opts.output != "text" {
+		return fmt.Errorf("--output is only supported with --dry-run")
+	}
+
+	// Concurrent syncs would interleave writes to the cache and ~/.claude
+	lock, err := lockStaghorn(paths)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	interrupted := recoverInterruptedRun(paths)
+
+	// The cache may mix files from before and after the interrupted run
+	if interrupted {
+		printWarning("The previous sync did not finish; re-fetching everything")
+		forced := *opts
+		forced.force = true
+		opts = &forced
+	}
+
+	if err := beginSync(paths); err != nil {
+		return fmt.Errorf("failed to mark sync in progress: %w", err)
+	}
+
+	// A sync that returns, even with an error, finished every write it started;
+	// only a process that dies mid-write leaves the marker behind
+	err = syncWithPaths(ctx, opts, paths)
+	if finishErr := finishSync(paths); err == nil {
+		err = finishErr
+	}
+	return err
+}
+
+// syncWithPaths fetches sources into paths.CacheDir and applies them to the
+// Claude Code directory. Dry runs call it with staged paths.
+func syncWithPaths(ctx context.Context, opts *syncOptions, paths *config.Paths) error {
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
 
-	owner, repo, err := cfg.DefaultOwnerRepo()
+	spec, err := cfg.DefaultRepoSpec()
 	if err != nil {
 		return err
 	}
+	owner, repo := spec.CacheKey()
 
 	c := cache.New(paths)
 
 	// Check for multi-source configuration
-	// We need the GitHub client for multi-source, so check after client creation
+	// We need the providers for multi-source, so check after factory creation
 	isMultiSource := cfg.Source.IsMultiSource()
 
 	// Apply-only mode: skip fetch, just apply from cache
@@ -151,7 +703,14 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 		if !c.Exists(owner, repo) {
 			return errors.CacheNotFound(owner + "/" + repo)
 		}
-		return applyConfig(cfg, paths, owner, repo)
+		if err := checkLocalEdits(paths, opts); err != nil {
+			return err
+		}
+		if err := applyConfig(cfg, paths, owner, repo); err != nil {
+			return err
+		}
+		recordGeneration(paths, nil)
+		return nil
 	}
 
 	// Offline mode
@@ -167,56 +726,77 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 		return errors.CacheNotFound(owner + "/" + repo)
 	}
 
-	// Check if we need to sync
-	if !opts.force && c.Exists(owner, repo) {
+	// Frozen mode installs exactly what the lockfile records
+	var lock *lockfile.Lockfile
+	if opts.frozen {
+		lock, err = loadLockfileForFrozen(paths)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check if we need to sync (local sources are cheap to read, so always sync them)
+	if !opts.force && !opts.frozen && spec.Provider != config.ProviderLocal && c.Exists(owner, repo) {
 		meta, err := c.GetMetadata(owner, repo)
-		if err == nil && !meta.IsStale(cfg.Cache.TTLDuration()) {
+		refChanged := err == nil && spec.IsPinned() && meta.Ref != spec.Ref
+		if err == nil && !refChanged && !meta.IsStale(cfg.Cache.TTLDuration()) {
 			printSuccess("Cache is fresh (%s)", meta.Age())
 			fmt.Println("  Use --force to re-fetch anyway.")
 			return nil
 		}
-		// If metadata read failed or cache is stale, proceed with sync
+		// If metadata read failed, the pinned ref changed, or cache is stale, proceed with sync
 	}
 
-	// Create GitHub client
-	client, err := github.NewClient()
-	if err != nil {
-		// Try with token fallback
-		token := github.GetTokenFromEnv()
-		if token == "" {
-			return errors.GitHubAuthFailed(err)
-		}
-		client, err = github.NewClientWithToken(token)
-		if err != nil {
-			return errors.GitHubAuthFailed(err)
-		}
+	// Stop before fetching rather than after, since a fresh cache skips the apply
+	if err := checkLocalEdits(paths, opts); err != nil {
+		return err
 	}
 
+	// Providers are created per source host; the GitHub client only when needed
+	factory := newProviderFactory(cfg, paths)
+
 	// Use multi-source sync if configured
 	if isMultiSource {
-		return runMultiSourceSync(ctx, cfg, paths, opts, client, c)
+		return runMultiSourceSync(ctx, cfg, paths, opts, factory, c, lock)
 	}
 
-	// Determine branch (use default branch from repo)
-	branch, err := client.GetDefaultBranch(ctx, owner, repo)
+	p, err := factory.For(spec)
 	if err != nil {
-		return errors.GitHubFetchFailed(owner+"/"+repo, err)
+		return err
 	}
 
-	fmt.Printf("Fetching %s/%s...\n", owner, repo)
+	// Determine ref (pinned ref, or the repo's default branch) and its commit
+	rc, err := newRepoContext(ctx, p, spec, lock)
+	if err != nil {
+		return err
+	}
+	if err := rc.loadChecksums(ctx, cfg); err != nil {
+		return err
+	}
+	rc.loadTree(ctx, c)
+	rc.concurrency = cfg.SyncConcurrency()
+	rc.pruner = newPruner(!opts.noPrune)
+	notePreviousCommits(c, paths, []*repoContext{rc})
+	branch := rc.branch
+
+	fmt.Printf("Fetching %s...\n", spec.String())
 
 	// Sync config
 	if opts.shouldSyncConfig() {
-		result, err := client.FetchFile(ctx, owner, repo, config.DefaultPath, branch)
+		result, err := rc.fetchConfigFile(ctx, c)
 		if err != nil {
-			return errors.GitHubFetchFailed(owner+"/"+repo, err)
+			return errors.GitHubFetchFailed(spec.FullName(), err)
 		}
 
 		// Save to cache
+		rc.noteConfigChanges(c, result.Content)
 		meta := &cache.Metadata{
 			Owner:       owner,
 			Repo:        repo,
+			ETag:        result.ETag,
 			SHA:         result.SHA,
+			Ref:         branch,
+			Commit:      rc.commit,
 			LastFetched: time.Now(),
 		}
 
@@ -225,13 +805,18 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 		}
 
 		printSuccess("Synced config")
-		printInfo("File", config.DefaultPath)
-		printInfo("SHA", result.SHA[:8])
+		printInfo("File", spec.SourcePath(config.DefaultPath))
+		if branch != "" {
+			printInfo("Ref", branch)
+		}
+		if result.SHA != "" {
+			printInfo("SHA", lockfile.ShortSHA(result.SHA))
+		}
 	}
 
 	// Sync commands
 	if opts.shouldSyncCommands() {
-		commandCount, err := syncCommands(ctx, client, owner, repo, branch, paths)
+		commandCount, err := syncCommands(ctx, rc, paths)
 		if err != nil {
 			printWarning("Failed to sync commands: %v", err)
 		} else if commandCount > 0 {
@@ -241,7 +826,7 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 		}
 
 		// Also sync templates
-		templateCount, err := syncTemplates(ctx, client, owner, repo, branch, paths)
+		templateCount, err := syncTemplates(ctx, rc, paths)
 		if err != nil {
 			printWarning("Failed to sync templates: %v", err)
 		} else if templateCount > 0 {
@@ -251,7 +836,7 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 
 	// Sync languages
 	if opts.shouldSyncLanguages() {
-		languageCount, err := syncLanguages(ctx, client, owner, repo, branch, paths)
+		languageCount, err := syncLanguages(ctx, rc, paths)
 		if err != nil {
 			printWarning("Failed to sync languages: %v", err)
 		} else if languageCount > 0 {
@@ -263,7 +848,7 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 
 	// Sync evals
 	if opts.shouldSyncEvals() {
-		evalCount, err := syncEvals(ctx, client, owner, repo, branch, paths)
+		evalCount, err := syncEvals(ctx, rc, paths)
 		if err != nil {
 			printWarning("Failed to sync evals: %v", err)
 		} else if evalCount > 0 {
@@ -273,7 +858,7 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 
 	// Sync rules
 	if opts.shouldSyncRules() {
-		ruleCount, err := syncRules(ctx, client, owner, repo, branch, paths)
+		ruleCount, err := syncRules(ctx, rc, paths)
 		if err != nil {
 			printWarning("Failed to sync rules: %v", err)
 		} else if ruleCount > 0 {
@@ -285,7 +870,7 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 
 	// Sync skills
 	if opts.shouldSyncSkills() {
-		skillCount, err := syncSkills(ctx, client, owner, repo, branch, paths)
+		skillCount, err := syncSkills(ctx, rc, paths)
 		if err != nil {
 			printWarning("Failed to sync skills: %v", err)
 		} else if skillCount > 0 {
@@ -295,6 +880,16 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 		}
 	}
 
+	// Refuse content that doesn't match the signed checksums
+	if err := checkSignedContent([]*repoContext{rc}); err != nil {
+		return err
+	}
+
+	// Record exactly what was fetched, or confirm it matches the lockfile
+	if err := finishLockfile(paths, opts, []*repoContext{rc}); err != nil {
+		return err
+	}
+
 	// Apply to ~/.claude/CLAUDE.md
 	if opts.shouldApplyConfig() {
 		fmt.Println()
@@ -305,7 +900,7 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 
 	// Sync commands to Claude Code
 	if opts.shouldSyncClaudeCommands() {
-		claudeCount, err := syncClaudeCommands(paths, owner, repo)
+		claudeCount, err := syncClaudeCommands(paths, owner, repo, rc.pruner)
 		if err != nil {
 			printWarning("Failed to sync Claude commands: %v", err)
 		} else if claudeCount > 0 {
@@ -316,7 +911,7 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 
 	// Sync rules to Claude Code
 	if opts.shouldSyncClaudeRules() {
-		claudeRuleCount, err := syncClaudeRules(paths, owner, repo)
+		claudeRuleCount, err := syncClaudeRules(cfg, paths, rc.pruner)
 		if err != nil {
 			printWarning("Failed to sync Claude rules: %v", err)
 		} else if claudeRuleCount > 0 {
@@ -326,7 +921,7 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 
 	// Sync skills to Claude Code
 	if opts.shouldSyncClaudeSkills() {
-		claudeSkillCount, err := syncClaudeSkills(paths, owner, repo)
+		claudeSkillCount, err := syncClaudeSkills(cfg, paths, rc.pruner)
 		if err != nil {
 			printWarning("Failed to sync Claude skills: %v", err)
 		} else if claudeSkillCount > 0 {
@@ -335,6 +930,17 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 		}
 	}
 
+	// Summarize files removed because they were deleted upstream
+	rc.pruner.report()
+
+	// Show what changed upstream since the last sync
+	reportUpstreamChanges(ctx, []*repoContext{rc})
+
+	// Snapshot what was applied so it can be rolled back
+	if !opts.fetchOnly {
+		recordGeneration(paths, []*repoContext{rc})
+	}
+
 	// Check merged config size and suggest optimization if large
 	if !opts.fetchOnly {
 		checkConfigSizeAndSuggestOptimize(cfg, paths, owner, repo)
@@ -343,6 +949,164 @@ func runSync(ctx context.Context, opts *syncOptions) error {
 	return nil
 }
 
+// newProviderFactory returns a factory that creates source providers on demand.
+// GitHub clients are only created (and authenticated) if a GitHub source is used.
+func newProviderFactory(cfg *config.Config, paths *config.Paths) *provider.Factory {
+	return &provider.Factory{
+		GitHub:     newGitHubClient,
+		GitHubHost: cfg.SourceGitHubHost(),
+		GitDir:     paths.GitCacheDir(),
+	}
+}
+
+// newGitHubClient creates an authenticated client for host, falling back to a
+// token from the environment.
+func newGitHubClient(host string) (*github.Client, error) {
+	client, err := github.NewClientForHost(host)
+	if err == nil {
+		return client, nil
+	}
+
+	token := github.GetTokenFromEnvForHost(host)
+	if token == "" {
+		return nil, errors.GitHubAuthFailed(err)
+	}
+	client, err = github.NewClientWithTokenForHost(host, token)
+	if err != nil {
+		return nil, errors.GitHubAuthFailed(err)
+	}
+	return client, nil
+}
+
+// newRepoContext resolves the ref and commit to sync for a source.
+// When lock is non-nil (frozen mode), the source is pinned to its locked commit instead.
+func newRepoContext(ctx context.Context, p provider.SourceProvider, spec *config.RepoSpec, lock *lockfile.Lockfile) (*repoContext, error) {
+	owner, repo := spec.CacheKey()
+	rc := &repoContext{
+		owner:    owner,
+		repo:     repo,
+		spec:     spec,
+		provider: p,
+		fetched:  lockfile.NewRecorder(),
+	}
+
+	if lock != nil {
+		locked := lock.FindSource(spec.FullName())
+		if locked == nil {
+			return nil, errors.LockfileMismatch(fmt.Sprintf("%s is not in the lockfile", spec.FullName()))
+		}
+		if spec.IsPinned() && locked.Ref != spec.Ref {
+			return nil, errors.LockfileMismatch(fmt.Sprintf("%s is pinned to %s but locked at %s", spec.FullName(), spec.Ref, locked.Ref))
+		}
+		rc.branch = locked.Ref
+		rc.commit = locked.Commit
+		rc.locked = locked
+		return rc, nil
+	}
+
+	branch, err := resolveRef(ctx, p, spec)
+	if err != nil {
+		return nil, errors.GitHubFetchFailed(spec.String(), err)
+	}
+
+	// Providers without commit resolution fetch by branch instead
+	var commit string
+	if resolver, ok := p.(provider.CommitResolver); ok {
+		commit, err = resolver.ResolveCommit(ctx, spec.Owner, spec.Repo, branch)
+		if err != nil {
+			return nil, errors.GitHubFetchFailed(spec.String(), err)
+		}
+	}
+
+	rc.branch = branch
+	rc.commit = commit
+	return rc, nil
+}
+
+// withSpec returns a context for another config entry naming the same repo,
+// sharing the ref and commit rc resolved.
+func (rc *repoContext) withSpec(p provider.SourceProvider, spec *config.RepoSpec) *repoContext {
+	owner, repo := spec.CacheKey()
+	return &repoContext{
+		owner:    owner,
+		repo:     repo,
+		branch:   rc.branch,
+		commit:   rc.commit,
+		locked:   rc.locked,
+		fetched:  lockfile.NewRecorder(),
+		spec:     spec,
+		provider: p,
+	}
+}
+
+// loadLockfileForFrozen loads staghorn.lock for a frozen sync.
+func loadLockfileForFrozen(paths *config.Paths) (*lockfile.Lockfile, error) {
+	lock, err := lockfile.Load(paths.LockFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.LockfileMismatch(fmt.Sprintf("no lockfile at %s", paths.LockFile))
+		}
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	return lock, nil
+}
+
+// finishLockfile writes staghorn.lock after a full sync. In frozen mode it instead
+// verifies that every fetched file matched the lockfile.
+// Partial syncs leave the lockfile untouched since they only fetch a subset of files.
+func finishLockfile(paths *config.Paths, opts *syncOptions, contexts []*repoContext) error {
+	if opts.frozen {
+		for _, rc := range contexts {
+			if rc.locked == nil {
+				continue
+			}
+			if diffs := rc.locked.Diff(rc.fetched.Files()); len(diffs) > 0 {
+				return errors.LockfileMismatch(fmt.Sprintf("%s: %s", rc.fullName(), strings.Join(diffs, ", ")))
+			}
+		}
+		printSuccess("Verified against %s", filepath.Base(paths.LockFile))
+		return nil
+	}
+
+	if opts.isPartial() {
+		return nil
+	}
+
+	lock := &lockfile.Lockfile{}
+	for _, rc := range contexts {
+		files := rc.fetched.Files()
+		if len(files) == 0 {
+			continue
+		}
+		// Several config entries can point at the same repo; buildRepoContexts
+		// resolves it once, so they share a commit
+		if existing := lock.FindSource(rc.fullName()); existing != nil {
+			existing.Files = append(existing.Files, files...)
+			continue
+		}
+		lock.SetSource(&lockfile.Source{
+			Repo:   rc.fullName(),
+			Ref:    rc.branch,
+			Commit: rc.commit,
+			Files:  files,
+		})
+	}
+
+	if err := lock.Save(paths.LockFile); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// resolveRef returns the ref to fetch for a source: the pinned ref if one is
+// configured, otherwise the repository's default branch.
+func resolveRef(ctx context.Context, p provider.SourceProvider, spec *config.RepoSpec) (string, error) {
+	if spec.IsPinned() {
+		return spec.Ref, nil
+	}
+	return p.GetDefaultBranch(ctx, spec.Owner, spec.Repo)
+}
+
 // syncDirectoryOpts configures the syncDirectoryContents helper.
 type syncDirectoryOpts struct {
 	remoteDir  string   // Remote directory name (e.g., "commands")
@@ -353,21 +1117,22 @@ type syncDirectoryOpts struct {
 
 // syncDirectoryContents fetches files from a remote directory and saves them locally.
 // This is a generic helper used by syncCommands, syncTemplates, syncLanguages, etc.
-func syncDirectoryContents(ctx context.Context, client *github.Client, owner, repo, branch string, opts syncDirectoryOpts) (int, error) {
-	entries, err := client.ListDirectory(ctx, owner, repo, opts.remoteDir, branch)
+func syncDirectoryContents(ctx context.Context, rc *repoContext, opts syncDirectoryOpts) (int, error) {
+	entries, err := rc.listDirectory(ctx, opts.remoteDir)
 	if err != nil {
 		return 0, err
 	}
 
 	if entries == nil {
-		return 0, nil
+		// Directory was removed upstream (or never existed)
+		return 0, rc.pruner.track(opts.localDir, nil, nil)
 	}
 
 	if err := os.MkdirAll(opts.localDir, 0755); err != nil {
 		return 0, fmt.Errorf("failed to create %s directory: %w", opts.itemType, err)
 	}
 
-	count := 0
+	var jobs []fileJob
 	for _, entry := range entries {
 		if entry.Type != "file" {
 			continue
@@ -385,159 +1150,108 @@ func syncDirectoryContents(ctx context.Context, client *github.Client, owner, re
 			continue
 		}
 
-		result, err := client.FetchFile(ctx, owner, repo, entry.Path, branch)
-		if err != nil {
-			printWarning("Failed to fetch %s %s: %v", opts.itemType, entry.Name, err)
-			continue
-		}
-
-		localPath := filepath.Join(opts.localDir, entry.Name)
-		if err := os.WriteFile(localPath, []byte(result.Content), 0644); err != nil {
-			printWarning("Failed to write %s %s: %v", opts.itemType, entry.Name, err)
-			continue
-		}
-
-		count++
+		jobs = append(jobs, fileJob{
+			rc:        rc,
+			path:      entry.Path,
+			localPath: filepath.Join(opts.localDir, entry.Name),
+			itemType:  opts.itemType,
+			name:      entry.Name,
+		})
 	}
 
-	return count, nil
+	written := fetchAndWrite(ctx, jobs, rc.concurrency)
+	if err := rc.pruner.track(opts.localDir, relPaths(opts.localDir, jobs), nil); err != nil {
+		return len(written), err
+	}
+	return len(written), nil
 }
 
 // syncCommands fetches commands from the team repo's commands/ directory.
-func syncCommands(ctx context.Context, client *github.Client, owner, repo, branch string, paths *config.Paths) (int, error) {
-	return syncDirectoryContents(ctx, client, owner, repo, branch, syncDirectoryOpts{
+func syncCommands(ctx context.Context, rc *repoContext, paths *config.Paths) (int, error) {
+	return syncDirectoryContents(ctx, rc, syncDirectoryOpts{
 		remoteDir:  "commands",
-		localDir:   paths.TeamCommandsDir(owner, repo),
+		localDir:   paths.TeamCommandsDir(rc.owner, rc.repo),
 		itemType:   "command",
 		extensions: []string{".md"},
 	})
 }
 
 // syncTemplates fetches project templates from the team repo's templates/ directory.
-func syncTemplates(ctx context.Context, client *github.Client, owner, repo, branch string, paths *config.Paths) (int, error) {
-	return syncDirectoryContents(ctx, client, owner, repo, branch, syncDirectoryOpts{
+func syncTemplates(ctx context.Context, rc *repoContext, paths *config.Paths) (int, error) {
+	return syncDirectoryContents(ctx, rc, syncDirectoryOpts{
 		remoteDir:  "templates",
-		localDir:   paths.TeamTemplatesDir(owner, repo),
+		localDir:   paths.TeamTemplatesDir(rc.owner, rc.repo),
 		itemType:   "template",
 		extensions: []string{".md"},
 	})
 }
 
 // syncLanguages fetches language configs from the team repo's languages/ directory.
-func syncLanguages(ctx context.Context, client *github.Client, owner, repo, branch string, paths *config.Paths) (int, error) {
-	return syncDirectoryContents(ctx, client, owner, repo, branch, syncDirectoryOpts{
+func syncLanguages(ctx context.Context, rc *repoContext, paths *config.Paths) (int, error) {
+	return syncDirectoryContents(ctx, rc, syncDirectoryOpts{
 		remoteDir:  "languages",
-		localDir:   paths.TeamLanguagesDir(owner, repo),
+		localDir:   paths.TeamLanguagesDir(rc.owner, rc.repo),
 		itemType:   "language config",
 		extensions: []string{".md"},
 	})
 }
 
 // syncEvals fetches evals from the team repo's evals/ directory.
-func syncEvals(ctx context.Context, client *github.Client, owner, repo, branch string, paths *config.Paths) (int, error) {
-	return syncDirectoryContents(ctx, client, owner, repo, branch, syncDirectoryOpts{
+func syncEvals(ctx context.Context, rc *repoContext, paths *config.Paths) (int, error) {
+	return syncDirectoryContents(ctx, rc, syncDirectoryOpts{
 		remoteDir:  "evals",
-		localDir:   paths.TeamEvalsDir(owner, repo),
+		localDir:   paths.TeamEvalsDir(rc.owner, rc.repo),
 		itemType:   "eval",
 		extensions: []string{".yaml", ".yml"},
 	})
 }
 
 // syncRules fetches rules from the team repo's rules/ directory (recursive).
-func syncRules(ctx context.Context, client *github.Client, owner, repo, branch string, paths *config.Paths) (int, error) {
-	rulesDir := paths.TeamRulesDir(owner, repo)
-
-	// Clear existing cache to handle deletions
-	if err := os.RemoveAll(rulesDir); err != nil {
-		return 0, fmt.Errorf("failed to clear rules cache: %w", err)
-	}
-
-	count, err := syncRulesRecursive(ctx, client, owner, repo, branch, "rules", rulesDir, "")
-	if err != nil {
-		return 0, err
-	}
-
-	return count, nil
-}
+func syncRules(ctx context.Context, rc *repoContext, paths *config.Paths) (int, error) {
+	rulesDir := paths.TeamRulesDir(rc.owner, rc.repo)
 
-// syncRulesRecursive handles recursive directory fetching for rules.
-func syncRulesRecursive(ctx context.Context, client *github.Client, owner, repo, branch, remotePath, localBase, relPath string) (int, error) {
-	entries, err := client.ListDirectory(ctx, owner, repo, remotePath, branch)
+	jobs, err := listFilesRecursive(ctx, rc, "rules", rulesDir, "rule", isMarkdown)
 	if err != nil {
 		return 0, err
 	}
 
-	if entries == nil {
-		return 0, nil
+	written := fetchAndWrite(ctx, jobs, rc.concurrency)
+	if err := rc.pruner.track(rulesDir, relPaths(rulesDir, jobs), nil); err != nil {
+		return len(written), err
 	}
-
-	count := 0
-	for _, entry := range entries {
-		entryRelPath := entry.Name
-		if relPath != "" {
-			entryRelPath = filepath.Join(relPath, entry.Name)
-		}
-
-		if entry.Type == "dir" {
-			// Recurse into subdirectory
-			subCount, err := syncRulesRecursive(ctx, client, owner, repo, branch,
-				entry.Path, localBase, entryRelPath)
-			if err != nil {
-				printWarning("Failed to sync rules subdirectory %s: %v", entry.Name, err)
-				continue
-			}
-			count += subCount
-		} else if entry.Type == "file" && strings.HasSuffix(entry.Name, ".md") {
-			// Fetch and cache rule file
-			result, err := client.FetchFile(ctx, owner, repo, entry.Path, branch)
-			if err != nil {
-				printWarning("Failed to fetch rule %s: %v", entry.Name, err)
-				continue
-			}
-
-			localPath := filepath.Join(localBase, entryRelPath)
-			if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
-				printWarning("Failed to create directory for rule %s: %v", entry.Name, err)
-				continue
-			}
-
-			if err := os.WriteFile(localPath, []byte(result.Content), 0644); err != nil {
-				printWarning("Failed to write rule %s: %v", entry.Name, err)
-				continue
-			}
-
-			count++
-		}
-	}
-
-	return count, nil
+	return len(written), nil
 }
 
 // syncClaudeRules syncs staghorn rules to Claude Code rules directory.
-func syncClaudeRules(paths *config.Paths, owner, repo string) (int, error) {
+func syncClaudeRules(cfg *config.Config, paths *config.Paths, pr *pruner) (int, error) {
 	// Load rules from all sources using the registry
-	registry, err := rules.LoadRegistry(
-		paths.TeamRulesDir(owner, repo),
-		paths.PersonalRules,
-		"", // No project dir for global sync
-	)
+	registry, err := loadTeamRuleRegistry(cfg, paths, "") // No project dir for global sync
 	if err != nil {
 		return 0, fmt.Errorf("failed to load rules: %w", err)
 	}
 
+	count, written, err := installClaudeRules(registry, paths.ClaudeRulesDir(), pr)
+	recordWrites(paths, written)
+	return count, err
+}
+
+// installClaudeRules converts every rule in registry into claudeDir, skipping
+// files staghorn doesn't manage. Returns the content written to each file.
+func installClaudeRules(registry *rules.Registry, claudeDir string, pr *pruner) (int, map[string][]byte, error) {
 	allRules := registry.All()
 	if len(allRules) == 0 {
-		return 0, nil
+		return 0, nil, pr.track(claudeDir, nil, isManagedFileIn(claudeDir))
 	}
 
 	// Create Claude rules directory
-	claudeDir := paths.ClaudeRulesDir()
 	if err := os.MkdirAll(claudeDir, 0755); err != nil {
-		return 0, fmt.Errorf("failed to create Claude rules directory: %w", err)
+		return 0, nil, fmt.Errorf("failed to create Claude rules directory: %w", err)
 	}
 
 	// Write each rule
 	count := 0
+	var installed []string
+	written := make(map[string][]byte)
 	for _, rule := range allRules {
 		outputPath := filepath.Join(claudeDir, rule.RelPath)
 
@@ -549,6 +1263,7 @@ func syncClaudeRules(paths *config.Paths, owner, repo string) (int, error) {
 				continue
 			}
 		}
+		installed = append(installed, filepath.ToSlash(rule.RelPath))
 
 		// Ensure parent directory exists (for subdirectories)
 		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
@@ -561,18 +1276,28 @@ func syncClaudeRules(paths *config.Paths, owner, repo string) (int, error) {
 			printWarning("Failed to convert rule %s: %v", rule.RelPath, err)
 			continue
 		}
-		if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		if err := fsutil.WriteFile(outputPath, []byte(content), 0644); err != nil {
 			printWarning("Failed to write Claude rule %s: %v", rule.RelPath, err)
 			continue
 		}
+		written[outputPath] = []byte(content)
 		count++
 	}
 
-	return count, nil
+	return count, written, pr.track(claudeDir, installed, isManagedFileIn(claudeDir))
+}
+
+// isManagedFileIn returns a guard reporting whether a file under dir still
+// carries the staghorn managed header.
+func isManagedFileIn(dir string) func(rel string) bool {
+	return func(rel string) bool {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		return err == nil && strings.Contains(string(content), merge.HeaderManagedPrefix)
+	}
 }
 
 // syncClaudeCommands syncs staghorn commands to Claude Code custom commands directory.
-func syncClaudeCommands(paths *config.Paths, owner, repo string) (int, error) {
+func syncClaudeCommands(paths *config.Paths, owner, repo string, pr *pruner) (int, error) {
 	// Load commands from all sources using the registry
 	registry, err := commands.LoadRegistry(
 		paths.TeamCommandsDir(owner, repo),
@@ -583,19 +1308,28 @@ func syncClaudeCommands(paths *config.Paths, owner, repo string) (int, error) {
 		return 0, fmt.Errorf("failed to load commands: %w", err)
 	}
 
+	count, written, err := installClaudeCommands(registry, paths.ClaudeCommandsDir(), pr)
+	recordWrites(paths, written)
+	return count, err
+}
+
+// installClaudeCommands converts every command in registry into claudeDir,
+// skipping files staghorn doesn't manage. Returns the content written to each file.
+func installClaudeCommands(registry *commands.Registry, claudeDir string, pr *pruner) (int, map[string][]byte, error) {
 	allCommands := registry.All()
 	if len(allCommands) == 0 {
-		return 0, nil
+		return 0, nil, pr.track(claudeDir, nil, isManagedFileIn(claudeDir))
 	}
 
 	// Create Claude commands directory
-	claudeDir := paths.ClaudeCommandsDir()
 	if err := os.MkdirAll(claudeDir, 0755); err != nil {
-		return 0, fmt.Errorf("failed to create Claude commands directory: %w", err)
+		return 0, nil, fmt.Errorf("failed to create Claude commands directory: %w", err)
 	}
 
 	// Write each command as a Claude command
 	count := 0
+	var installed []string
+	written := make(map[string][]byte)
 	for _, cmd := range allCommands {
 		filename := cmd.Name + ".md"
 		outputPath := filepath.Join(claudeDir, filename)
@@ -608,16 +1342,62 @@ func syncClaudeCommands(paths *config.Paths, owner, repo string) (int, error) {
 				continue
 			}
 		}
+		installed = append(installed, filename)
 
 		content := commands.ConvertToClaude(cmd)
-		if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		if err := fsutil.WriteFile(outputPath, []byte(content), 0644); err != nil {
 			printWarning("Failed to write Claude command %s: %v", cmd.Name, err)
 			continue
 		}
+		written[outputPath] = []byte(content)
 		count++
 	}
 
-	return count, nil
+	return count, written, pr.track(claudeDir, installed, isManagedFileIn(claudeDir))
+}
+
+// loadTeamLayers reads the cached CLAUDE.md of each base repo, broadest first.
+// Stacked layers are tagged with their repo so provenance markers name each one;
+// a single base repo stays a plain "team" layer.
+func loadTeamLayers(cfg *config.Config, paths *config.Paths) ([]merge.Layer, error) {
+	repos := cfg.Source.BaseRepos()
+	layers := make([]merge.Layer, 0, len(repos))
+	for _, repoStr := range repos {
+		spec, err := config.ParseRepoSpec(repoStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repo %s: %w", repoStr, err)
+		}
+
+		owner, repo := spec.CacheKey()
+		content, err := os.ReadFile(paths.CacheFile(owner, repo))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("no cached team config found for %s", spec.FullName())
+			}
+			return nil, fmt.Errorf("failed to read cached config: %w", err)
+		}
+
+		layer := merge.Layer{Content: string(content), Source: "team"}
+		if len(repos) > 1 {
+			layer.Repo = spec.FullName()
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+// teamSourceLabel returns the source shown in the merged config header.
+func teamSourceLabel(cfg *config.Config, teamLayers []merge.Layer) string {
+	var repos []string
+	for _, layer := range teamLayers {
+		if layer.Repo != "" {
+			repos = append(repos, layer.Repo)
+		}
+	}
+	if len(repos) == 0 {
+		return cfg.SourceRepo()
+	}
+	return strings.Join(repos, " + ")
 }
 
 // readPersonalConfig reads and processes the personal config file.
@@ -678,28 +1458,8 @@ func handleExistingConfigMigration(cfg *config.Config, paths *config.Paths, outp
 	}
 
 	existingStr := string(existingContent)
-	needsPrompt := false
-	promptReason := ""
-
-	if !strings.Contains(existingStr, merge.HeaderManagedPrefix) {
-		needsPrompt = true
-		promptReason = "Found existing ~/.claude/CLAUDE.md not managed by staghorn"
-	} else {
-		// Check if switching sources
-		currentSource := cfg.SourceRepo()
-		if idx := strings.Index(existingStr, "Source: "); idx != -1 {
-			end := strings.Index(existingStr[idx:], " |")
-			if end != -1 {
-				previousSource := existingStr[idx+8 : idx+end]
-				if previousSource != currentSource {
-					needsPrompt = true
-					promptReason = fmt.Sprintf("Switching source from %s to %s", previousSource, currentSource)
-				}
-			}
-		}
-	}
-
-	if !needsPrompt {
+	promptReason := migrationReason(cfg, existingStr)
+	if promptReason == "" {
 		return true, personalConfig, nil
 	}
 
@@ -732,7 +1492,7 @@ func handleExistingConfigMigration(cfg *config.Config, paths *config.Paths, outp
 		if err := os.MkdirAll(paths.ConfigDir, 0755); err != nil {
 			return false, nil, fmt.Errorf("failed to create config directory: %w", err)
 		}
-		if err := os.WriteFile(paths.PersonalMD, []byte(newPersonal), 0644); err != nil {
+		if err := fsutil.WriteFile(paths.PersonalMD, []byte(newPersonal), 0644); err != nil {
 			return false, nil, fmt.Errorf("failed to write personal config: %w", err)
 		}
 		printSuccess("Migrated content to %s", paths.PersonalMD)
@@ -745,7 +1505,7 @@ func handleExistingConfigMigration(cfg *config.Config, paths *config.Paths, outp
 
 	case "2":
 		backupPath := outputPath + ".backup"
-		if err := os.WriteFile(backupPath, existingContent, 0644); err != nil {
+		if err := fsutil.WriteFile(backupPath, existingContent, 0644); err != nil {
 			return false, nil, fmt.Errorf("failed to backup existing file: %w", err)
 		}
 		printSuccess("Backed up to %s", backupPath)
@@ -761,20 +1521,57 @@ func handleExistingConfigMigration(cfg *config.Config, paths *config.Paths, outp
 	}
 }
 
+// migrationReason explains why an existing ~/.claude/CLAUDE.md needs the user's
+// attention before it is overwritten. Returns "" if it can be replaced as is.
+func migrationReason(cfg *config.Config, existing string) string {
+	if !strings.Contains(existing, merge.HeaderManagedPrefix) {
+		return "Found existing ~/.claude/CLAUDE.md not managed by staghorn"
+	}
+
+	// Check if switching sources (changing only the pinned ref is not a switch)
+	currentSource := cfg.SourceRepo()
+	if idx := strings.Index(existing, "Source: "); idx != -1 {
+		end := strings.Index(existing[idx:], " |")
+		if end != -1 {
+			previousSource := existing[idx+8 : idx+end]
+			if !sameRepo(previousSource, currentSource) {
+				return fmt.Sprintf("Switching source from %s to %s", previousSource, currentSource)
+			}
+		}
+	}
+	return ""
+}
+
+// sameRepo reports whether two repo strings refer to the same owner/repo, ignoring refs.
+func sameRepo(a, b string) bool {
+	aOwner, aRepo, errA := config.ParseRepo(a)
+	bOwner, bRepo, errB := config.ParseRepo(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return strings.EqualFold(aOwner, bOwner) && strings.EqualFold(aRepo, bRepo)
+}
+
 // writeConfigOutput writes the merged config to the output file and prints status.
-func writeConfigOutput(outputPath string, output string, hasPersonal bool) error {
+func writeConfigOutput(outputPath string, output string, teamLayers int, hasPersonal bool) error {
 	claudeDir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(claudeDir, 0755); err != nil {
 		return fmt.Errorf("failed to create ~/.claude directory: %w", err)
 	}
 
-	if err := os.WriteFile(outputPath, []byte(output), 0644); err != nil {
+	if err := fsutil.WriteFile(outputPath, []byte(output), 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
 	printSuccess("Applied to %s", outputPath)
 
-	if hasPersonal {
+	if teamLayers > 1 {
+		if hasPersonal {
+			fmt.Printf("  %s %d team layers + personal additions\n", dim("Merged:"), teamLayers)
+		} else {
+			fmt.Printf("  %s %d team layers (no personal additions)\n", dim("Merged:"), teamLayers)
+		}
+	} else if hasPersonal {
 		fmt.Printf("  %s Team config + personal additions\n", dim("Merged:"))
 	} else {
 		fmt.Printf("  %s Team config only (no personal additions)\n", dim("Merged:"))
@@ -785,44 +1582,71 @@ func writeConfigOutput(outputPath string, output string, hasPersonal bool) error
 }
 
 // mergeAndWriteConfig handles migration, merging, and writing for both single and multi-source configs.
-func mergeAndWriteConfig(cfg *config.Config, paths *config.Paths, teamConfig, personalConfig []byte, activeLanguages []string, languageFiles map[string][]*language.LanguageFile) error {
-	layers := []merge.Layer{
-		{Content: string(teamConfig), Source: "team"},
-		{Content: string(personalConfig), Source: "personal"},
-	}
+// Team layers are merged in order, with personal config on top.
+func mergeAndWriteConfig(cfg *config.Config, paths *config.Paths, teamLayers []merge.Layer, personalConfig []byte, activeLanguages []string, languageFiles map[string][]*language.LanguageFile) error {
 	mergeOpts := merge.MergeOptions{
 		AnnotateSources: true,
-		SourceRepo:      cfg.SourceRepo(),
+		SourceRepo:      teamSourceLabel(cfg, teamLayers),
 		Languages:       activeLanguages,
 		LanguageFiles:   languageFiles,
 	}
 
-	outputPath := filepath.Join(os.Getenv("HOME"), ".claude", "CLAUDE.md")
-	shouldContinue, updatedPersonal, err := handleExistingConfigMigration(cfg, paths, outputPath, personalConfig)
-	if err != nil {
-		return err
+	outputPath := paths.ClaudeMD()
+	if err := checkFileEdits(paths, outputPath); err != nil {
+		return err
+	}
+	shouldContinue, updatedPersonal, err := handleExistingConfigMigration(cfg, paths, outputPath, personalConfig)
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+	if updatedPersonal != nil {
+		personalConfig = updatedPersonal
+	}
+
+	layers := append(teamLayers, merge.Layer{Content: string(personalConfig), Source: "personal"})
+	output := merge.MergeWithLanguages(layers, mergeOpts)
+	if err := writeConfigOutput(outputPath, output, len(teamLayers), len(personalConfig) > 0); err != nil {
+		return err
+	}
+	recordWrites(paths, map[string][]byte{outputPath: []byte(output)})
+
+	reportOverrides(merge.ParseOverrides(output))
+	return nil
+}
+
+// reportOverrides summarizes team sections replaced or removed by personal
+// directives, and warns about directives ignored because a section is locked.
+func reportOverrides(overrides []merge.Override) {
+	counts := make(map[string]int)
+	for _, o := range overrides {
+		if o.Action == merge.OverrideBlocked {
+			printWarning("Section %q is locked upstream; %s directive ignored", o.Section, o.Source)
+			continue
+		}
+		counts[o.Action]++
+	}
+
+	var parts []string
+	if n := counts[merge.OverrideReplaced]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d replaced", n))
 	}
-	if !shouldContinue {
-		return nil
+	if n := counts[merge.OverrideRemoved]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", n))
 	}
-	if updatedPersonal != nil {
-		personalConfig = updatedPersonal
-		layers[1] = merge.Layer{Content: string(personalConfig), Source: "personal"}
+	if len(parts) > 0 {
+		fmt.Printf("  %s %s (see 'staghorn info --content --sources')\n", dim("Overrides:"), strings.Join(parts, ", "))
 	}
-
-	output := merge.MergeWithLanguages(layers, mergeOpts)
-	return writeConfigOutput(outputPath, output, len(personalConfig) > 0)
 }
 
 // applyConfig merges team config with personal additions and writes to ~/.claude/CLAUDE.md.
 func applyConfig(cfg *config.Config, paths *config.Paths, owner, repo string) error {
 	// Get team config from cache
-	teamConfig, err := os.ReadFile(paths.CacheFile(owner, repo))
+	teamLayers, err := loadTeamLayers(cfg, paths)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("no cached team config found")
-		}
-		return fmt.Errorf("failed to read cached config: %w", err)
+		return err
 	}
 
 	// Get personal config (optional)
@@ -845,7 +1669,7 @@ func applyConfig(cfg *config.Config, paths *config.Paths, owner, repo string) er
 		)
 	}
 
-	return mergeAndWriteConfig(cfg, paths, teamConfig, personalConfig, activeLanguages, languageFiles)
+	return mergeAndWriteConfig(cfg, paths, teamLayers, personalConfig, activeLanguages, languageFiles)
 }
 
 // stripInstructionalComments removes HTML comments marked with [staghorn] prefix
@@ -891,10 +1715,9 @@ func checkConfigSizeAndSuggestOptimize(cfg *config.Config, paths *config.Paths,
 	// Build merged content to calculate size
 	var layers []merge.Layer
 
-	// Team layer
-	c := cache.New(paths)
-	if teamContent, _, err := c.Read(owner, repo); err == nil && teamContent != "" {
-		layers = append(layers, merge.Layer{Content: teamContent, Source: "team"})
+	// Team layers
+	if teamLayers, err := loadTeamLayers(cfg, paths); err == nil {
+		layers = append(layers, teamLayers...)
 	}
 
 	// Personal layer
@@ -954,45 +1777,118 @@ func checkConfigSizeAndSuggestOptimize(cfg *config.Config, paths *config.Paths,
 }
 
 // buildRepoContexts creates repo contexts for all repos in a multi-source config.
-func buildRepoContexts(ctx context.Context, client *github.Client, cfg *config.Config) (map[string]*repoContext, error) {
+// Repos are resolved in parallel, bounded by the configured sync concurrency.
+// In frozen mode (lock non-nil), each repo is pinned to its locked commit.
+func buildRepoContexts(ctx context.Context, factory *provider.Factory, cfg *config.Config, c *cache.Cache, lock *lockfile.Lockfile) (map[string]*repoContext, error) {
 	allRepos := cfg.Source.AllRepos()
-	contexts := make(map[string]*repoContext, len(allRepos))
-
-	for _, repoStr := range allRepos {
-		owner, repo, err := config.ParseRepo(repoStr)
+	specs := make([]*config.RepoSpec, len(allRepos))
+	for i, repoStr := range allRepos {
+		spec, err := config.ParseRepoSpec(repoStr)
 		if err != nil {
 			return nil, fmt.Errorf("invalid repo %s: %w", repoStr, err)
 		}
+		specs[i] = spec
+	}
+
+	// Entries spelled differently can name the same repo (e.g. "acme/standards"
+	// and "github:acme/standards"). Resolve each repo once and share its commit,
+	// so they all fetch from, and lock, the same one.
+	groups := make(map[string][]int)
+	var names []string
+	for i, spec := range specs {
+		name := strings.ToLower(spec.FullName())
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], i)
+	}
 
-		branch, err := client.GetDefaultBranch(ctx, owner, repo)
+	results := make([]*repoContext, len(allRepos))
+	errs := make([]error, len(allRepos))
+	runConcurrently(len(names), cfg.SyncConcurrency(), func(n int) {
+		first := groups[names[n]][0]
+		p, err := factory.For(specs[first])
+		if err != nil {
+			errs[first] = err
+			return
+		}
+		results[first], errs[first] = newRepoContext(ctx, p, specs[first], lock)
+	})
+	for _, err := range errs {
 		if err != nil {
-			return nil, errors.GitHubFetchFailed(repoStr, err)
+			return nil, err
+		}
+	}
+
+	runConcurrently(len(allRepos), cfg.SyncConcurrency(), func(i int) {
+		rc := results[i]
+		if rc == nil {
+			p, err := factory.For(specs[i])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			rc = results[groups[strings.ToLower(specs[i].FullName())][0]].withSpec(p, specs[i])
+		}
+		if err := rc.loadChecksums(ctx, cfg); err != nil {
+			errs[i] = err
+			return
 		}
+		rc.loadTree(ctx, c)
+		rc.concurrency = cfg.SyncConcurrency()
+		results[i] = rc
+	})
 
-		contexts[repoStr] = &repoContext{
-			owner:  owner,
-			repo:   repo,
-			branch: branch,
+	contexts := make(map[string]*repoContext, len(allRepos))
+	for i, repoStr := range allRepos {
+		if errs[i] != nil {
+			return nil, errs[i]
 		}
+		contexts[repoStr] = results[i]
 	}
 
 	return contexts, nil
 }
 
+// sortedRepoContexts returns repo contexts ordered by their config key for deterministic output.
+func sortedRepoContexts(repoContexts map[string]*repoContext) []*repoContext {
+	keys := make([]string, 0, len(repoContexts))
+	for k := range repoContexts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	contexts := make([]*repoContext, 0, len(keys))
+	for _, k := range keys {
+		contexts = append(contexts, repoContexts[k])
+	}
+	return contexts
+}
+
 // runMultiSourceSync handles sync when multiple source repos are configured.
-func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.Paths, opts *syncOptions, client *github.Client, c *cache.Cache) error {
+func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.Paths, opts *syncOptions, factory *provider.Factory, c *cache.Cache, lock *lockfile.Lockfile) error {
 	// Build contexts for all repos
-	repoContexts, err := buildRepoContexts(ctx, client, cfg)
+	repoContexts, err := buildRepoContexts(ctx, factory, cfg, c, lock)
 	if err != nil {
 		return err
 	}
 
+	// All sources share one pruner so removals are reported together
+	pr := newPruner(!opts.noPrune)
+	for _, rc := range repoContexts {
+		rc.pruner = pr
+	}
+	notePreviousCommits(c, paths, sortedRepoContexts(repoContexts))
+
 	// Get the base and default repo contexts - these should always exist after buildRepoContexts
-	baseRepoStr := cfg.Source.RepoForBase()
-	baseCtx := repoContexts[baseRepoStr]
-	if baseCtx == nil {
-		// This indicates a bug in buildRepoContexts - it should have created this context
-		return fmt.Errorf("internal error: no context for base repo %s after building contexts", baseRepoStr)
+	baseRepoStrs := cfg.Source.BaseRepos()
+	baseCtxs := make([]*repoContext, len(baseRepoStrs))
+	for i, baseRepoStr := range baseRepoStrs {
+		baseCtxs[i] = repoContexts[baseRepoStr]
+		if baseCtxs[i] == nil {
+			// This indicates a bug in buildRepoContexts - it should have created this context
+			return fmt.Errorf("internal error: no context for base repo %s after building contexts", baseRepoStr)
+		}
 	}
 
 	defaultRepoStr := cfg.Source.DefaultRepo()
@@ -1004,39 +1900,49 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 
 	fmt.Printf("Fetching from %d source(s)...\n", len(repoContexts))
 
-	// Sync base config
+	// Sync base config, one file per layer
 	if opts.shouldSyncConfig() {
-		fmt.Printf("  Base config from %s/%s\n", baseCtx.owner, baseCtx.repo)
-		result, err := client.FetchFile(ctx, baseCtx.owner, baseCtx.repo, config.DefaultPath, baseCtx.branch)
-		if err != nil {
-			return errors.GitHubFetchFailed(baseRepoStr, err)
-		}
+		for i, baseCtx := range baseCtxs {
+			if len(baseCtxs) > 1 {
+				fmt.Printf("  Base config layer %d from %s\n", i+1, baseCtx.fullName())
+			} else {
+				fmt.Printf("  Base config from %s\n", baseCtx.fullName())
+			}
+			result, err := baseCtx.fetchConfigFile(ctx, c)
+			if err != nil {
+				return errors.GitHubFetchFailed(baseRepoStrs[i], err)
+			}
 
-		meta := &cache.Metadata{
-			Owner:       baseCtx.owner,
-			Repo:        baseCtx.repo,
-			SHA:         result.SHA,
-			LastFetched: time.Now(),
-		}
+			baseCtx.noteConfigChanges(c, result.Content)
+			meta := &cache.Metadata{
+				Owner:       baseCtx.owner,
+				Repo:        baseCtx.repo,
+				ETag:        result.ETag,
+				SHA:         result.SHA,
+				Ref:         baseCtx.branch,
+				Commit:      baseCtx.commit,
+				LastFetched: time.Now(),
+			}
 
-		if err := c.Write(baseCtx.owner, baseCtx.repo, result.Content, meta); err != nil {
-			return fmt.Errorf("failed to write cache: %w", err)
-		}
+			if err := c.Write(baseCtx.owner, baseCtx.repo, result.Content, meta); err != nil {
+				return fmt.Errorf("failed to write cache: %w", err)
+			}
 
-		printSuccess("Synced config from %s/%s", baseCtx.owner, baseCtx.repo)
+			printSuccess("Synced config from %s", baseCtx.fullName())
+		}
 	}
 
 	// Sync commands with multi-source support
 	if opts.shouldSyncCommands() {
-		commandCount, err := syncCommandsMultiSource(ctx, client, cfg, repoContexts, paths)
+		commandCount, err := syncCommandsMultiSource(ctx, cfg, repoContexts, paths)
 		if err != nil {
 			printWarning("Failed to sync commands: %v", err)
 		} else if commandCount > 0 {
 			printSuccess("Synced %d commands", commandCount)
 		}
 
-		// Sync templates from default repo
-		templateCount, err := syncTemplates(ctx, client, defaultCtx.owner, defaultCtx.repo, defaultCtx.branch, paths)
+		// Sync templates with multi-source support
+		templateCount, err := syncTemplatesMultiSource(ctx, cfg, repoContexts, paths)
 		if err != nil {
 			printWarning("Failed to sync templates: %v", err)
 		} else if templateCount > 0 {
@@ -1046,7 +1952,7 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 
 	// Sync languages with multi-source support
 	if opts.shouldSyncLanguages() {
-		languageCount, err := syncLanguagesMultiSource(ctx, client, cfg, repoContexts, paths)
+		languageCount, err := syncLanguagesMultiSource(ctx, cfg, repoContexts, paths)
 		if err != nil {
 			printWarning("Failed to sync languages: %v", err)
 		} else if languageCount > 0 {
@@ -1054,9 +1960,9 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 		}
 	}
 
-	// Sync evals from default repo
+	// Sync evals with multi-source support
 	if opts.shouldSyncEvals() {
-		evalCount, err := syncEvals(ctx, client, defaultCtx.owner, defaultCtx.repo, defaultCtx.branch, paths)
+		evalCount, err := syncEvalsMultiSource(ctx, cfg, repoContexts, paths)
 		if err != nil {
 			printWarning("Failed to sync evals: %v", err)
 		} else if evalCount > 0 {
@@ -1064,9 +1970,9 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 		}
 	}
 
-	// Sync rules from default repo (no per-rule multi-source support)
+	// Sync rules with multi-source support
 	if opts.shouldSyncRules() {
-		ruleCount, err := syncRules(ctx, client, defaultCtx.owner, defaultCtx.repo, defaultCtx.branch, paths)
+		ruleCount, err := syncRulesMultiSource(ctx, cfg, repoContexts, paths)
 		if err != nil {
 			printWarning("Failed to sync rules: %v", err)
 		} else if ruleCount > 0 {
@@ -1076,7 +1982,7 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 
 	// Sync skills with multi-source support
 	if opts.shouldSyncSkills() {
-		skillCount, err := syncSkillsMultiSource(ctx, client, cfg, repoContexts, paths)
+		skillCount, err := syncSkillsMultiSource(ctx, cfg, repoContexts, paths)
 		if err != nil {
 			printWarning("Failed to sync skills: %v", err)
 		} else if skillCount > 0 {
@@ -1084,6 +1990,16 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 		}
 	}
 
+	// Refuse content that doesn't match the signed checksums
+	if err := checkSignedContent(sortedRepoContexts(repoContexts)); err != nil {
+		return err
+	}
+
+	// Record exactly what was fetched, or confirm it matches the lockfile
+	if err := finishLockfile(paths, opts, sortedRepoContexts(repoContexts)); err != nil {
+		return err
+	}
+
 	// Apply config
 	if opts.shouldApplyConfig() {
 		fmt.Println()
@@ -1094,7 +2010,7 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 
 	// Sync commands to Claude Code
 	if opts.shouldSyncClaudeCommands() {
-		claudeCount, err := syncClaudeCommands(paths, defaultCtx.owner, defaultCtx.repo)
+		claudeCount, err := syncClaudeCommands(paths, defaultCtx.owner, defaultCtx.repo, defaultCtx.pruner)
 		if err != nil {
 			printWarning("Failed to sync Claude commands: %v", err)
 		} else if claudeCount > 0 {
@@ -1104,7 +2020,7 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 
 	// Sync rules to Claude Code
 	if opts.shouldSyncClaudeRules() {
-		claudeRuleCount, err := syncClaudeRules(paths, defaultCtx.owner, defaultCtx.repo)
+		claudeRuleCount, err := syncClaudeRules(cfg, paths, defaultCtx.pruner)
 		if err != nil {
 			printWarning("Failed to sync Claude rules: %v", err)
 		} else if claudeRuleCount > 0 {
@@ -1114,7 +2030,7 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 
 	// Sync skills to Claude Code
 	if opts.shouldSyncClaudeSkills() {
-		claudeSkillCount, err := syncClaudeSkills(paths, defaultCtx.owner, defaultCtx.repo)
+		claudeSkillCount, err := syncClaudeSkills(cfg, paths, defaultCtx.pruner)
 		if err != nil {
 			printWarning("Failed to sync Claude skills: %v", err)
 		} else if claudeSkillCount > 0 {
@@ -1123,6 +2039,17 @@ func runMultiSourceSync(ctx context.Context, cfg *config.Config, paths *config.P
 		}
 	}
 
+	// Summarize files removed because they were deleted upstream
+	pr.report()
+
+	// Show what changed upstream since the last sync
+	reportUpstreamChanges(ctx, sortedRepoContexts(repoContexts))
+
+	// Snapshot what was applied so it can be rolled back
+	if !opts.fetchOnly {
+		recordGeneration(paths, sortedRepoContexts(repoContexts))
+	}
+
 	// Check config size
 	if !opts.fetchOnly {
 		checkConfigSizeAndSuggestOptimize(cfg, paths, defaultCtx.owner, defaultCtx.repo)
@@ -1153,7 +2080,7 @@ func isExplicitlyConfiguredCommand(cfg *config.Config, cmd string) bool {
 // For explicitly configured items, always warn. For items using default repo,
 // only warn on non-404 errors (silently skip if not found).
 func handleMultiSourceFetchError(itemType, name, sourceRepo string, err error, isExplicit bool) {
-	if github.IsNotFoundError(err) {
+	if provider.IsNotFound(err) {
 		if isExplicit {
 			printWarning("%s %s not found in explicitly configured source %s", itemType, name, sourceRepo)
 		}
@@ -1165,7 +2092,7 @@ func handleMultiSourceFetchError(itemType, name, sourceRepo string, err error, i
 }
 
 // syncLanguagesMultiSource fetches languages from their configured source repos.
-func syncLanguagesMultiSource(ctx context.Context, client *github.Client, cfg *config.Config, repoContexts map[string]*repoContext, paths *config.Paths) (int, error) {
+func syncLanguagesMultiSource(ctx context.Context, cfg *config.Config, repoContexts map[string]*repoContext, paths *config.Paths) (int, error) {
 	// First, discover all languages from the default repo
 	defaultRepoStr := cfg.Source.DefaultRepo()
 	defaultCtx := repoContexts[defaultRepoStr]
@@ -1175,7 +2102,8 @@ func syncLanguagesMultiSource(ctx context.Context, client *github.Client, cfg *c
 
 	// Get languages from default repo
 	allLanguages := make(map[string]bool)
-	entries, err := client.ListDirectory(ctx, defaultCtx.owner, defaultCtx.repo, "languages", defaultCtx.branch)
+	entries, err := defaultCtx.listDirectory(ctx, "languages")
+	listed := err == nil
 	if err == nil && entries != nil {
 		for _, entry := range entries {
 			if entry.Type == "file" && strings.HasSuffix(entry.Name, ".md") {
@@ -1193,8 +2121,8 @@ func syncLanguagesMultiSource(ctx context.Context, client *github.Client, cfg *c
 	}
 
 	// Sync each language from its configured source
-	count := 0
-	for lang := range allLanguages {
+	var jobs []fileJob
+	for _, lang := range sortedKeys(allLanguages) {
 		sourceRepoStr := cfg.Source.RepoForLanguage(lang)
 		repoCtx := repoContexts[sourceRepoStr]
 		if repoCtx == nil {
@@ -1202,35 +2130,42 @@ func syncLanguagesMultiSource(ctx context.Context, client *github.Client, cfg *c
 			continue
 		}
 
-		// Fetch this language from its source
-		langPath := fmt.Sprintf("languages/%s.md", lang)
-		result, err := client.FetchFile(ctx, repoCtx.owner, repoCtx.repo, langPath, repoCtx.branch)
-		if err != nil {
-			handleMultiSourceFetchError("language", lang, sourceRepoStr, err, isExplicitlyConfiguredLanguage(cfg, lang))
-			continue
-		}
-
 		// Store in the repo-specific cache directory
-		langDir := paths.TeamLanguagesDir(repoCtx.owner, repoCtx.repo)
-		if err := os.MkdirAll(langDir, 0755); err != nil {
-			printWarning("Failed to create language directory for %s: %v", lang, err)
-			continue
-		}
+		jobs = append(jobs, fileJob{
+			rc:        repoCtx,
+			path:      fmt.Sprintf("languages/%s.md", lang),
+			localPath: filepath.Join(paths.TeamLanguagesDir(repoCtx.owner, repoCtx.repo), lang+".md"),
+			itemType:  "language",
+			name:      lang,
+		})
+	}
 
-		localPath := filepath.Join(langDir, lang+".md")
-		if err := os.WriteFile(localPath, []byte(result.Content), 0644); err != nil {
-			printWarning("Failed to write language %s: %v", lang, err)
+	outcomes := fetchConcurrently(ctx, jobs, cfg.SyncConcurrency())
+
+	count := 0
+	for i, job := range jobs {
+		if err := outcomes[i].err; err != nil {
+			handleMultiSourceFetchError("language", job.name, job.rc.fullName(), err, isExplicitlyConfiguredLanguage(cfg, job.name))
 			continue
 		}
+		if writeFetchedFile(job, outcomes[i].result) {
+			count++
+		}
+	}
 
-		count++
+	// Without the default repo's listing we can't tell what was deleted upstream
+	if listed {
+		dirFor := func(rc *repoContext) string { return paths.TeamLanguagesDir(rc.owner, rc.repo) }
+		if err := trackMultiSource(defaultCtx.pruner, repoContexts, dirFor, jobs, nil); err != nil {
+			return count, err
+		}
 	}
 
 	return count, nil
 }
 
 // syncCommandsMultiSource fetches commands from their configured source repos.
-func syncCommandsMultiSource(ctx context.Context, client *github.Client, cfg *config.Config, repoContexts map[string]*repoContext, paths *config.Paths) (int, error) {
+func syncCommandsMultiSource(ctx context.Context, cfg *config.Config, repoContexts map[string]*repoContext, paths *config.Paths) (int, error) {
 	// First, discover all commands from the default repo
 	defaultRepoStr := cfg.Source.DefaultRepo()
 	defaultCtx := repoContexts[defaultRepoStr]
@@ -1240,7 +2175,8 @@ func syncCommandsMultiSource(ctx context.Context, client *github.Client, cfg *co
 
 	// Get commands from default repo
 	allCommands := make(map[string]bool)
-	entries, err := client.ListDirectory(ctx, defaultCtx.owner, defaultCtx.repo, "commands", defaultCtx.branch)
+	entries, err := defaultCtx.listDirectory(ctx, "commands")
+	listed := err == nil
 	if err == nil && entries != nil {
 		for _, entry := range entries {
 			if entry.Type == "file" && strings.HasSuffix(entry.Name, ".md") {
@@ -1258,8 +2194,8 @@ func syncCommandsMultiSource(ctx context.Context, client *github.Client, cfg *co
 	}
 
 	// Sync each command from its configured source
-	count := 0
-	for cmd := range allCommands {
+	var jobs []fileJob
+	for _, cmd := range sortedKeys(allCommands) {
 		sourceRepoStr := cfg.Source.RepoForCommand(cmd)
 		repoCtx := repoContexts[sourceRepoStr]
 		if repoCtx == nil {
@@ -1267,49 +2203,302 @@ func syncCommandsMultiSource(ctx context.Context, client *github.Client, cfg *co
 			continue
 		}
 
-		// Fetch this command from its source
-		cmdPath := fmt.Sprintf("commands/%s.md", cmd)
-		result, err := client.FetchFile(ctx, repoCtx.owner, repoCtx.repo, cmdPath, repoCtx.branch)
-		if err != nil {
-			handleMultiSourceFetchError("command", cmd, sourceRepoStr, err, isExplicitlyConfiguredCommand(cfg, cmd))
+		// Store in the repo-specific cache directory
+		jobs = append(jobs, fileJob{
+			rc:        repoCtx,
+			path:      fmt.Sprintf("commands/%s.md", cmd),
+			localPath: filepath.Join(paths.TeamCommandsDir(repoCtx.owner, repoCtx.repo), cmd+".md"),
+			itemType:  "command",
+			name:      cmd,
+		})
+	}
+
+	outcomes := fetchConcurrently(ctx, jobs, cfg.SyncConcurrency())
+
+	count := 0
+	for i, job := range jobs {
+		if err := outcomes[i].err; err != nil {
+			handleMultiSourceFetchError("command", job.name, job.rc.fullName(), err, isExplicitlyConfiguredCommand(cfg, job.name))
 			continue
 		}
+		if writeFetchedFile(job, outcomes[i].result) {
+			count++
+		}
+	}
 
-		// Store in the repo-specific cache directory
-		cmdDir := paths.TeamCommandsDir(repoCtx.owner, repoCtx.repo)
-		if err := os.MkdirAll(cmdDir, 0755); err != nil {
-			printWarning("Failed to create commands directory for %s: %v", cmd, err)
+	// Without the default repo's listing we can't tell what was deleted upstream
+	if listed {
+		dirFor := func(rc *repoContext) string { return paths.TeamCommandsDir(rc.owner, rc.repo) }
+		if err := trackMultiSource(defaultCtx.pruner, repoContexts, dirFor, jobs, nil); err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+// multiSourceDir describes a directory of team items whose entries in the
+// source config can pull individual items from repos other than the default.
+type multiSourceDir struct {
+	remoteDir string                       // Directory in each repo (e.g., "rules")
+	itemType  string                       // Human-readable name for warnings (e.g., "rule")
+	recursive bool                         // Include subdirectories
+	keep      func(name string) bool       // Files to sync
+	key       func(rel string) string      // Item key for a file path under remoteDir
+	entries   map[string]string            // Explicitly configured sources, by key
+	repoFor   func(key string) string      // Source repo for an item key
+	localDir  func(rc *repoContext) string // Cache directory for a repo's items
+}
+
+// syncDirMultiSource lists the directory in the default repo and in every
+// repo named by an entry, and fetches each file from the repo that owns it.
+// Unlike commands, items are discovered by listing rather than by name, so an
+// entry can cover files the default repo doesn't have (or a whole directory).
+func syncDirMultiSource(ctx context.Context, cfg *config.Config, repoContexts map[string]*repoContext, d multiSourceDir) (int, error) {
+	defaultRepoStr := cfg.Source.DefaultRepo()
+	defaultCtx := repoContexts[defaultRepoStr]
+	if defaultCtx == nil {
+		return 0, fmt.Errorf("no context for default repo %s", defaultRepoStr)
+	}
+
+	var jobs []fileJob
+	synced := make(map[string]string) // Item key -> repo it was synced from
+	incomplete := make(map[string]bool)
+	for _, repoStr := range itemRepos(defaultRepoStr, d.entries) {
+		rc := repoContexts[repoStr]
+		if rc == nil {
+			printWarning("No context for %s source %s", d.itemType, repoStr)
 			continue
 		}
 
-		localPath := filepath.Join(cmdDir, cmd+".md")
-		if err := os.WriteFile(localPath, []byte(result.Content), 0644); err != nil {
-			printWarning("Failed to write command %s: %v", cmd, err)
+		localDir := d.localDir(rc)
+		repoJobs, err := listDirFiles(ctx, rc, d, localDir)
+		if err != nil {
+			if repoStr == defaultRepoStr {
+				return 0, err
+			}
+			incomplete[localDir] = true
+			printWarning("Failed to list %ss in %s: %v", d.itemType, repoStr, err)
 			continue
 		}
 
-		count++
+		for _, job := range repoJobs {
+			rel, err := filepath.Rel(localDir, job.localPath)
+			if err != nil {
+				continue
+			}
+			key := d.key(filepath.ToSlash(rel))
+			if d.repoFor(key) != repoStr {
+				continue
+			}
+			synced[key] = repoStr
+			jobs = append(jobs, job)
+		}
 	}
 
-	return count, nil
+	// Warn about entries that matched nothing in their source
+	for key, repoStr := range d.entries {
+		rc := repoContexts[repoStr]
+		if rc == nil || incomplete[d.localDir(rc)] {
+			continue
+		}
+		found := false
+		for synced, from := range synced {
+			if from == repoStr && (synced == key || strings.HasPrefix(synced, strings.TrimSuffix(key, "/")+"/")) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			printWarning("%s %s not found in explicitly configured source %s", d.itemType, key, repoStr)
+		}
+	}
+
+	written := fetchAndWrite(ctx, jobs, cfg.SyncConcurrency())
+
+	if err := trackMultiSource(defaultCtx.pruner, repoContexts, d.localDir, jobs, incomplete); err != nil {
+		return len(written), err
+	}
+	return len(written), nil
 }
 
-// applyConfigFromMultiSource merges configs from multiple source repos.
-func applyConfigFromMultiSource(cfg *config.Config, paths *config.Paths, repoContexts map[string]*repoContext) error {
-	// Get base config from the base source repo
-	baseRepoStr := cfg.Source.RepoForBase()
-	baseCtx := repoContexts[baseRepoStr]
-	if baseCtx == nil {
-		// This should not happen if buildRepoContexts was called correctly
-		return fmt.Errorf("internal error: no context for base repo %s", baseRepoStr)
+// listDirFiles returns a job for every file d keeps in rc's copy of the directory.
+func listDirFiles(ctx context.Context, rc *repoContext, d multiSourceDir, localDir string) ([]fileJob, error) {
+	if d.recursive {
+		return listFilesRecursive(ctx, rc, d.remoteDir, localDir, d.itemType, d.keep)
 	}
 
-	teamConfig, err := os.ReadFile(paths.CacheFile(baseCtx.owner, baseCtx.repo))
+	entries, err := rc.listDirectory(ctx, d.remoteDir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("no cached team config found for %s/%s", baseCtx.owner, baseCtx.repo)
+		return nil, err
+	}
+
+	var jobs []fileJob
+	for _, entry := range entries {
+		if entry.Type != "file" || !d.keep(entry.Name) {
+			continue
+		}
+		jobs = append(jobs, fileJob{
+			rc:        rc,
+			path:      entry.Path,
+			localPath: filepath.Join(localDir, entry.Name),
+			itemType:  d.itemType,
+			name:      entry.Name,
+		})
+	}
+	return jobs, nil
+}
+
+// syncRulesMultiSource fetches rules from their configured source repos.
+func syncRulesMultiSource(ctx context.Context, cfg *config.Config, repoContexts map[string]*repoContext, paths *config.Paths) (int, error) {
+	var entries map[string]string
+	if cfg.Source.Multi != nil {
+		entries = cfg.Source.Multi.Rules
+	}
+	return syncDirMultiSource(ctx, cfg, repoContexts, multiSourceDir{
+		remoteDir: "rules",
+		itemType:  "rule",
+		recursive: true,
+		keep:      isMarkdown,
+		key:       func(rel string) string { return rel },
+		entries:   entries,
+		repoFor:   cfg.Source.RepoForRule,
+		localDir:  func(rc *repoContext) string { return paths.TeamRulesDir(rc.owner, rc.repo) },
+	})
+}
+
+// syncEvalsMultiSource fetches evals from their configured source repos.
+func syncEvalsMultiSource(ctx context.Context, cfg *config.Config, repoContexts map[string]*repoContext, paths *config.Paths) (int, error) {
+	var entries map[string]string
+	if cfg.Source.Multi != nil {
+		entries = cfg.Source.Multi.Evals
+	}
+	return syncDirMultiSource(ctx, cfg, repoContexts, multiSourceDir{
+		remoteDir: "evals",
+		itemType:  "eval",
+		keep:      isEvalFile,
+		key:       func(rel string) string { return strings.TrimSuffix(rel, filepath.Ext(rel)) },
+		entries:   entries,
+		repoFor:   cfg.Source.RepoForEval,
+		localDir:  func(rc *repoContext) string { return paths.TeamEvalsDir(rc.owner, rc.repo) },
+	})
+}
+
+// syncTemplatesMultiSource fetches project templates from their configured source repos.
+func syncTemplatesMultiSource(ctx context.Context, cfg *config.Config, repoContexts map[string]*repoContext, paths *config.Paths) (int, error) {
+	var entries map[string]string
+	if cfg.Source.Multi != nil {
+		entries = cfg.Source.Multi.Templates
+	}
+	return syncDirMultiSource(ctx, cfg, repoContexts, multiSourceDir{
+		remoteDir: "templates",
+		itemType:  "template",
+		keep:      isMarkdown,
+		key:       func(rel string) string { return strings.TrimSuffix(rel, ".md") },
+		entries:   entries,
+		repoFor:   cfg.Source.RepoForTemplate,
+		localDir:  func(rc *repoContext) string { return paths.TeamTemplatesDir(rc.owner, rc.repo) },
+	})
+}
+
+// isEvalFile reports whether a file name has an eval extension.
+func isEvalFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
+
+// itemRepos returns defaultRepo followed by the other repos named in entries, sorted.
+func itemRepos(defaultRepo string, entries map[string]string) []string {
+	others := make(map[string]bool)
+	for _, repo := range entries {
+		if repo != defaultRepo {
+			others[repo] = true
+		}
+	}
+	return append([]string{defaultRepo}, sortedKeys(others)...)
+}
+
+// teamItemSource is one source repo's cache directory for a kind of team item.
+type teamItemSource struct {
+	repo string // Source repo as written in the config
+	dir  string
+}
+
+// teamItemSources returns the cache directories team items of one kind are
+// loaded from: the default repo's, then one for each other repo in entries.
+// Callers keep only the items whose configured source is the directory's repo,
+// so a stale copy left in the wrong cache is never picked up.
+func teamItemSources(cfg *config.Config, entries map[string]string, dirFor func(owner, repo string) string) []teamItemSource {
+	if cfg.Source.IsEmpty() {
+		return nil
+	}
+
+	var sources []teamItemSource
+	for _, repoStr := range itemRepos(cfg.Source.DefaultRepo(), entries) {
+		owner, repo, err := config.ParseRepo(repoStr)
+		if err != nil {
+			continue
+		}
+		sources = append(sources, teamItemSource{repo: repoStr, dir: dirFor(owner, repo)})
+	}
+	return sources
+}
+
+// trackMultiSource records the files synced into each repo's cache directory
+// (as returned by dirFor) and prunes the rest. Directories in skip are left alone.
+func trackMultiSource(pr *pruner, repoContexts map[string]*repoContext, dirFor func(rc *repoContext) string, jobs []fileJob, skip map[string]bool) error {
+	seen := make(map[string]bool)
+	for _, rc := range sortedRepoContexts(repoContexts) {
+		dir := dirFor(rc)
+		if seen[dir] || skip[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		var dirJobs []fileJob
+		for _, job := range jobs {
+			if rel, err := filepath.Rel(dir, job.localPath); err == nil && !strings.HasPrefix(rel, "..") {
+				dirJobs = append(dirJobs, job)
+			}
 		}
-		return fmt.Errorf("failed to read cached config: %w", err)
+
+		if err := pr.track(dir, relPaths(dir, dirJobs), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFetchedFile writes a fetched file to its local path, warning on failure.
+func writeFetchedFile(job fileJob, result *provider.FetchResult) bool {
+	if err := os.MkdirAll(filepath.Dir(job.localPath), 0755); err != nil {
+		printWarning("Failed to create %s directory for %s: %v", job.itemType, job.name, err)
+		return false
+	}
+
+	if err := fsutil.WriteFile(job.localPath, []byte(result.Content), 0644); err != nil {
+		printWarning("Failed to write %s %s: %v", job.itemType, job.name, err)
+		return false
+	}
+
+	return true
+}
+
+// sortedKeys returns the keys of a set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// applyConfigFromMultiSource merges configs from multiple source repos.
+func applyConfigFromMultiSource(cfg *config.Config, paths *config.Paths, repoContexts map[string]*repoContext) error {
+	// Get base config from the base source repos, broadest layer first
+	teamLayers, err := loadTeamLayers(cfg, paths)
+	if err != nil {
+		return err
 	}
 
 	// Get personal config (optional)
@@ -1319,14 +2508,8 @@ func applyConfigFromMultiSource(cfg *config.Config, paths *config.Paths, repoCon
 	}
 
 	// Collect all team language directories from all repos (sorted for deterministic output)
-	var repoKeys []string
-	for k := range repoContexts {
-		repoKeys = append(repoKeys, k)
-	}
-	sort.Strings(repoKeys)
 	var teamLangDirs []string
-	for _, k := range repoKeys {
-		ctx := repoContexts[k]
+	for _, ctx := range sortedRepoContexts(repoContexts) {
 		teamLangDirs = append(teamLangDirs, paths.TeamLanguagesDir(ctx.owner, ctx.repo))
 	}
 
@@ -1336,7 +2519,7 @@ func applyConfigFromMultiSource(cfg *config.Config, paths *config.Paths, repoCon
 	// Load language files from their respective source repos
 	languageFiles := loadMultiSourceLanguageFiles(cfg, paths, repoContexts, activeLanguages)
 
-	return mergeAndWriteConfig(cfg, paths, teamConfig, personalConfig, activeLanguages, languageFiles)
+	return mergeAndWriteConfig(cfg, paths, teamLayers, personalConfig, activeLanguages, languageFiles)
 }
 
 // loadMultiSourceLanguageFiles loads language files from their respective source repos.
@@ -1374,137 +2557,174 @@ func loadMultiSourceLanguageFiles(cfg *config.Config, paths *config.Paths, repoC
 
 // syncSkills fetches skills from the team repo's skills/ directory.
 // Skills are directories containing SKILL.md plus optional supporting files.
-func syncSkills(ctx context.Context, client *github.Client, owner, repo, branch string, paths *config.Paths) (int, error) {
+func syncSkills(ctx context.Context, rc *repoContext, paths *config.Paths) (int, error) {
 	// List skills directory (top-level entries are skill directories)
-	entries, err := client.ListDirectory(ctx, owner, repo, "skills", branch)
+	entries, err := rc.listDirectory(ctx, "skills")
 	if err != nil {
 		return 0, err
 	}
 
-	if entries == nil {
-		return 0, nil
-	}
-
-	// Create local skills cache directory
-	skillsDir := paths.TeamSkillsDir(owner, repo)
+	skillsDir := paths.TeamSkillsDir(rc.owner, rc.repo)
 
-	// Clear existing cache to handle deletions
-	if err := os.RemoveAll(skillsDir); err != nil {
-		return 0, fmt.Errorf("failed to clear skills cache: %w", err)
+	if entries == nil {
+		// Skills were removed upstream (or never existed)
+		return 0, rc.pruner.track(skillsDir, nil, nil)
 	}
 
-	// Sync each skill directory
-	count := 0
+	// Collect files from every skill directory, then fetch them together
+	var jobs []fileJob
+	complete := true
 	for _, entry := range entries {
 		if entry.Type != "dir" {
 			continue
 		}
 
-		// Sync this skill directory recursively
-		skillLocalDir := filepath.Join(skillsDir, entry.Name)
-		fileCount, err := syncSkillDir(ctx, client, owner, repo, branch, entry.Path, skillLocalDir)
+		skillJobs, err := listSkillFiles(ctx, rc, entry.Name, entry.Path, filepath.Join(skillsDir, entry.Name))
 		if err != nil {
 			printWarning("Failed to sync skill %s: %v", entry.Name, err)
+			complete = false
 			continue
 		}
+		jobs = append(jobs, skillJobs...)
+	}
 
-		if fileCount > 0 {
-			count++
+	count := countGroups(fetchAndWrite(ctx, jobs, rc.concurrency))
+
+	// Only prune with a complete listing, so a failed skill isn't mistaken for a deleted one
+	if complete {
+		if err := rc.pruner.track(skillsDir, relPaths(skillsDir, jobs), nil); err != nil {
+			return count, err
 		}
 	}
-
 	return count, nil
 }
 
-// syncSkillDir syncs a single skill directory recursively.
-func syncSkillDir(ctx context.Context, client *github.Client, owner, repo, branch, remotePath, localDir string) (int, error) {
-	entries, err := client.ListDirectory(ctx, owner, repo, remotePath, branch)
+// listSkillFiles returns jobs for every file in a skill directory, grouped by skill name.
+func listSkillFiles(ctx context.Context, rc *repoContext, skill, remotePath, localDir string) ([]fileJob, error) {
+	jobs, err := listFilesRecursive(ctx, rc, remotePath, localDir, "skill file", nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		jobs[i].group = skill
+	}
+	return jobs, nil
+}
+
+// syncClaudeSkills syncs staghorn skills to Claude Code skills directory.
+// Team skill changes that add hooks, tools, or scripts are held for review
+// unless the source's skills policy says otherwise.
+func syncClaudeSkills(cfg *config.Config, paths *config.Paths, pr *pruner) (int, error) {
+	registry, err := loadClaudeSkills(cfg, paths)
 	if err != nil {
 		return 0, err
 	}
 
-	if entries == nil {
-		return 0, nil
+	review, err := newSkillReview(cfg, paths)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load held skills: %w", err)
 	}
 
-	// Ensure local directory exists
-	if err := os.MkdirAll(localDir, 0755); err != nil {
-		return 0, fmt.Errorf("failed to create skill directory: %w", err)
+	count, err := installClaudeSkills(registry, paths.ClaudeSkillsDir(), pr, review)
+	if finishErr := review.finish(); finishErr != nil {
+		return count, finishErr
 	}
+	return count, err
+}
 
-	count := 0
-	for _, entry := range entries {
-		if entry.Type == "dir" {
-			// Recurse into subdirectory
-			subLocalDir := filepath.Join(localDir, entry.Name)
-			subCount, err := syncSkillDir(ctx, client, owner, repo, branch, entry.Path, subLocalDir)
-			if err != nil {
-				printWarning("Failed to sync skill subdirectory %s: %v", entry.Name, err)
-				continue
-			}
-			count += subCount
-		} else if entry.Type == "file" {
-			// Fetch and cache file
-			result, err := client.FetchFile(ctx, owner, repo, entry.Path, branch)
-			if err != nil {
-				printWarning("Failed to fetch skill file %s: %v", entry.Name, err)
-				continue
-			}
+// loadClaudeSkills loads the skills a global sync installs: each team skill
+// from its configured source's cache, then personal skills over them.
+func loadClaudeSkills(cfg *config.Config, paths *config.Paths) (*skills.Registry, error) {
+	registry := skills.NewRegistry()
 
-			localPath := filepath.Join(localDir, entry.Name)
-			if err := os.WriteFile(localPath, []byte(result.Content), 0644); err != nil {
-				printWarning("Failed to write skill file %s: %v", entry.Name, err)
-				continue
+	var entries map[string]string
+	if cfg.Source.Multi != nil {
+		entries = cfg.Source.Multi.Skills
+	}
+	for _, src := range teamItemSources(cfg, entries, paths.TeamSkillsDir) {
+		teamSkills, err := skills.LoadFromDirectory(src.dir, skills.SourceTeam)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load team skills from %s: %w", src.dir, err)
+		}
+		for _, skill := range teamSkills {
+			if cfg.Source.RepoForSkill(skill.Name) == src.repo {
+				registry.Add(skill)
 			}
-
-			count++
 		}
 	}
 
-	return count, nil
-}
-
-// syncClaudeSkills syncs staghorn skills to Claude Code skills directory.
-func syncClaudeSkills(paths *config.Paths, owner, repo string) (int, error) {
-	// Load skills from all sources using the registry
-	registry, err := skills.LoadRegistry(
-		paths.TeamSkillsDir(owner, repo),
-		paths.PersonalSkills,
-		"", // No project dir for global sync
-	)
+	personal, err := skills.LoadFromDirectory(paths.PersonalSkills, skills.SourcePersonal)
 	if err != nil {
-		return 0, fmt.Errorf("failed to load skills: %w", err)
+		return nil, fmt.Errorf("failed to load personal skills: %w", err)
 	}
+	registry.AddAll(personal)
+	return registry, nil
+}
 
+// installClaudeSkills syncs every skill in registry into claudeDir, skipping
+// skills staghorn doesn't manage. Skills the review holds back keep their
+// installed version; a nil review admits every skill.
+func installClaudeSkills(registry *skills.Registry, claudeDir string, pr *pruner, review *skillReview) (int, error) {
 	allSkills := registry.All()
 	if len(allSkills) == 0 {
-		return 0, nil
+		return 0, pr.track(claudeDir, nil, isManagedSkillFileIn(claudeDir))
 	}
 
 	// Create Claude skills directory
-	claudeDir := paths.ClaudeSkillsDir()
 	if err := os.MkdirAll(claudeDir, 0755); err != nil {
 		return 0, fmt.Errorf("failed to create Claude skills directory: %w", err)
 	}
 
 	// Sync each skill
 	count := 0
+	var installed []string
 	for _, skill := range allSkills {
+		if review != nil {
+			ok, err := review.admit(skill, claudeDir)
+			if err != nil {
+				printWarning("Failed to review skill %s: %v", skill.Name, err)
+			}
+			if !ok {
+				// Keep whatever version was installed before, so pruning leaves it alone
+				installed = append(installed, installedSkillFiles(claudeDir, skill.Name)...)
+				continue
+			}
+		}
+
 		filesWritten, err := skills.SyncToClaude(skill, claudeDir)
 		if err != nil {
 			if strings.Contains(err.Error(), "not managed by staghorn") {
 				printWarning("Skipping skill %s: existing skill not managed by staghorn", skill.Name)
-			} else {
-				printWarning("Failed to sync skill %s: %v", skill.Name, err)
+				continue
 			}
-			continue
+			printWarning("Failed to sync skill %s: %v", skill.Name, err)
 		}
-		if filesWritten > 0 {
+		installed = append(installed, skillFiles(skill)...)
+		if err == nil && filesWritten > 0 {
 			count++
 		}
 	}
 
-	return count, nil
+	return count, pr.track(claudeDir, installed, isManagedSkillFileIn(claudeDir))
+}
+
+// skillFiles returns the files SyncToClaude installs for a skill, relative to the skills directory.
+func skillFiles(skill *skills.Skill) []string {
+	files := []string{skill.Name + "/SKILL.md"}
+	for relPath := range skill.SupportingFiles {
+		files = append(files, skill.Name+"/"+filepath.ToSlash(relPath))
+	}
+	return files
+}
+
+// isManagedSkillFileIn returns a guard reporting whether a file under the Claude
+// skills directory belongs to a skill whose SKILL.md carries the managed header.
+func isManagedSkillFileIn(dir string) func(rel string) bool {
+	return func(rel string) bool {
+		skillName, _, _ := strings.Cut(rel, "/")
+		content, err := os.ReadFile(filepath.Join(dir, skillName, "SKILL.md"))
+		return err == nil && strings.Contains(string(content), skills.HeaderManagedPrefix)
+	}
 }
 
 // isExplicitlyConfiguredSkill returns true if the skill has an explicit source configured.
@@ -1517,7 +2737,7 @@ func isExplicitlyConfiguredSkill(cfg *config.Config, skill string) bool {
 }
 
 // syncSkillsMultiSource fetches skills from their configured source repos.
-func syncSkillsMultiSource(ctx context.Context, client *github.Client, cfg *config.Config, repoContexts map[string]*repoContext, paths *config.Paths) (int, error) {
+func syncSkillsMultiSource(ctx context.Context, cfg *config.Config, repoContexts map[string]*repoContext, paths *config.Paths) (int, error) {
 	// First, discover all skills from the default repo
 	defaultRepoStr := cfg.Source.DefaultRepo()
 	defaultCtx := repoContexts[defaultRepoStr]
@@ -1527,7 +2747,8 @@ func syncSkillsMultiSource(ctx context.Context, client *github.Client, cfg *conf
 
 	// Get skills from default repo
 	allSkills := make(map[string]bool)
-	entries, err := client.ListDirectory(ctx, defaultCtx.owner, defaultCtx.repo, "skills", defaultCtx.branch)
+	entries, err := defaultCtx.listDirectory(ctx, "skills")
+	listed := err == nil
 	if err == nil && entries != nil {
 		for _, entry := range entries {
 			if entry.Type == "dir" {
@@ -1543,9 +2764,10 @@ func syncSkillsMultiSource(ctx context.Context, client *github.Client, cfg *conf
 		}
 	}
 
-	// Sync each skill from its configured source
-	count := 0
-	for skill := range allSkills {
+	// Collect files for each skill from its configured source, then fetch them together
+	var jobs []fileJob
+	incomplete := make(map[string]bool) // Skill dirs with a failed listing, never pruned
+	for _, skill := range sortedKeys(allSkills) {
 		sourceRepoStr := cfg.Source.RepoForSkill(skill)
 		repoCtx := repoContexts[sourceRepoStr]
 		if repoCtx == nil {
@@ -1553,32 +2775,32 @@ func syncSkillsMultiSource(ctx context.Context, client *github.Client, cfg *conf
 			continue
 		}
 
-		// Sync this skill from its source
 		skillPath := fmt.Sprintf("skills/%s", skill)
 		skillLocalDir := filepath.Join(paths.TeamSkillsDir(repoCtx.owner, repoCtx.repo), skill)
 
 		// Check if skill exists in remote
-		entries, err := client.ListDirectory(ctx, repoCtx.owner, repoCtx.repo, skillPath, repoCtx.branch)
+		skillJobs, err := listSkillFiles(ctx, repoCtx, skill, skillPath, skillLocalDir)
 		if err != nil {
+			incomplete[paths.TeamSkillsDir(repoCtx.owner, repoCtx.repo)] = true
 			handleMultiSourceFetchError("skill", skill, sourceRepoStr, err, isExplicitlyConfiguredSkill(cfg, skill))
 			continue
 		}
-		if entries == nil {
+		if skillJobs == nil {
 			if isExplicitlyConfiguredSkill(cfg, skill) {
 				printWarning("Skill %s not found in explicitly configured source %s", skill, sourceRepoStr)
 			}
 			continue
 		}
 
-		// Sync the skill directory
-		fileCount, err := syncSkillDir(ctx, client, repoCtx.owner, repoCtx.repo, repoCtx.branch, skillPath, skillLocalDir)
-		if err != nil {
-			printWarning("Failed to sync skill %s from %s: %v", skill, sourceRepoStr, err)
-			continue
-		}
+		jobs = append(jobs, skillJobs...)
+	}
 
-		if fileCount > 0 {
-			count++
+	count := countGroups(fetchAndWrite(ctx, jobs, cfg.SyncConcurrency()))
+
+	if listed {
+		dirFor := func(rc *repoContext) string { return paths.TeamSkillsDir(rc.owner, rc.repo) }
+		if err := trackMultiSource(defaultCtx.pruner, repoContexts, dirFor, jobs, incomplete); err != nil {
+			return count, err
 		}
 	}
 
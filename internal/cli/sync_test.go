@@ -1,14 +1,26 @@
 package cli
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/HartBrook/staghorn/internal/cache"
 	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/github"
+	"github.com/HartBrook/staghorn/internal/lockfile"
 	"github.com/HartBrook/staghorn/internal/merge"
+	"github.com/HartBrook/staghorn/internal/provider"
+	"github.com/HartBrook/staghorn/internal/skills"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -501,3 +513,574 @@ Use gofmt.`
 	// Verify header
 	assert.Contains(t, outputStr, "Managed by staghorn", "output should contain staghorn header")
 }
+
+func TestLoadTeamLayers(t *testing.T) {
+	tempDir := t.TempDir()
+	paths := config.NewPathsWithOverrides(filepath.Join(tempDir, "config"), tempDir)
+	require.NoError(t, os.WriteFile(paths.CacheFile("acme", "standards"), []byte("## Org\n\nOrg rules."), 0644))
+	require.NoError(t, os.WriteFile(paths.CacheFile("acme", "platform-standards"), []byte("## Platform\n\nPlatform rules."), 0644))
+
+	t.Run("stacked layers name their repo", func(t *testing.T) {
+		cfg := &config.Config{Source: config.Source{Multi: &config.SourceConfig{
+			Default: "acme/standards",
+			Layers:  []string{"acme/standards", "acme/platform-standards"},
+		}}}
+
+		layers, err := loadTeamLayers(cfg, paths)
+		require.NoError(t, err)
+		require.Len(t, layers, 2)
+		assert.Equal(t, merge.Layer{Content: "## Org\n\nOrg rules.", Source: "team", Repo: "acme/standards"}, layers[0])
+		assert.Equal(t, "acme/platform-standards", layers[1].Repo)
+		assert.Equal(t, "acme/standards + acme/platform-standards", teamSourceLabel(cfg, layers))
+	})
+
+	t.Run("single base stays a plain team layer", func(t *testing.T) {
+		cfg := &config.Config{Source: config.Source{Simple: "acme/standards"}}
+
+		layers, err := loadTeamLayers(cfg, paths)
+		require.NoError(t, err)
+		require.Len(t, layers, 1)
+		assert.Empty(t, layers[0].Repo)
+		assert.Equal(t, "acme/standards", teamSourceLabel(cfg, layers))
+	})
+
+	t.Run("missing layer", func(t *testing.T) {
+		cfg := &config.Config{Source: config.Source{Multi: &config.SourceConfig{
+			Default: "acme/standards",
+			Layers:  []string{"acme/standards", "acme/missing"},
+		}}}
+
+		_, err := loadTeamLayers(cfg, paths)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "acme/missing")
+	})
+}
+
+func TestSameRepo(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"acme/standards", "acme/standards", true},
+		{"acme/standards", "acme/standards@v1.4.0", true},
+		{"acme/standards@v1", "ACME/Standards@v2", true},
+		{"acme/standards", "acme/other", false},
+		{"acme/standards@v1", "other/standards@v1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, sameRepo(tt.a, tt.b))
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestBuildRepoContexts_ResolvesEachRepoOnce(t *testing.T) {
+	// Every commit lookup answers with a new SHA, as if the branch kept moving
+	var resolutions atomic.Int32
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, status := `{"message": "Not Found"}`, http.StatusNotFound
+		switch req.URL.Path {
+		case "/repos/acme/standards":
+			body, status = `{"default_branch": "main"}`, http.StatusOK
+		case "/repos/acme/standards/commits/main":
+			body, status = fmt.Sprintf(`{"sha": "%040d"}`, resolutions.Add(1)), http.StatusOK
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+	t.Cleanup(func() { http.DefaultTransport = transport })
+
+	factory := &provider.Factory{GitHub: func(host string) (*github.Client, error) {
+		return github.NewClientWithTokenForHost(host, "test-token")
+	}}
+	cfg := &config.Config{Source: config.Source{Multi: &config.SourceConfig{
+		Default: "acme/standards",
+		Skills:  map[string]string{"deploy": "github:acme/standards"},
+	}}}
+
+	contexts, err := buildRepoContexts(context.Background(), factory, cfg, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, contexts, 2)
+	assert.Equal(t, int32(1), resolutions.Load())
+	assert.Equal(t, contexts["acme/standards"].commit, contexts["github:acme/standards"].commit)
+}
+
+func TestFinishLockfile(t *testing.T) {
+	tempDir := t.TempDir()
+	paths := config.NewPathsWithOverrides(filepath.Join(tempDir, "config"), filepath.Join(tempDir, "cache"))
+
+	rc := &repoContext{owner: "acme", repo: "standards", branch: "main", commit: "abc123", fetched: lockfile.NewRecorder()}
+	rc.fetched.Record("CLAUDE.md", "sha-claude")
+	rc.fetched.Record("commands/review.md", "sha-review")
+
+	t.Run("partial sync leaves lockfile untouched", func(t *testing.T) {
+		err := finishLockfile(paths, &syncOptions{commandsOnly: true}, []*repoContext{rc})
+		require.NoError(t, err)
+		_, err = os.Stat(paths.LockFile)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("full sync writes lockfile", func(t *testing.T) {
+		err := finishLockfile(paths, &syncOptions{}, []*repoContext{rc})
+		require.NoError(t, err)
+
+		lock, err := lockfile.Load(paths.LockFile)
+		require.NoError(t, err)
+		src := lock.FindSource("acme/standards")
+		require.NotNil(t, src)
+		assert.Equal(t, "main", src.Ref)
+		assert.Equal(t, "abc123", src.Commit)
+		assert.Len(t, src.Files, 2)
+	})
+
+	t.Run("frozen sync verifies against lockfile", func(t *testing.T) {
+		lock, err := lockfile.Load(paths.LockFile)
+		require.NoError(t, err)
+
+		frozen := &repoContext{owner: "acme", repo: "standards", locked: lock.FindSource("acme/standards"), fetched: lockfile.NewRecorder()}
+		frozen.fetched.Record("CLAUDE.md", "sha-claude")
+		require.NoError(t, finishLockfile(paths, &syncOptions{frozen: true}, []*repoContext{frozen}))
+
+		frozen.fetched.Record("commands/review.md", "sha-changed")
+		err = finishLockfile(paths, &syncOptions{frozen: true}, []*repoContext{frozen})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "commands/review.md changed")
+	})
+}
+
+func TestNewRepoContext_Frozen(t *testing.T) {
+	lock := &lockfile.Lockfile{Sources: []*lockfile.Source{
+		{Repo: "acme/standards", Ref: "v1.0.0", Commit: "abc123"},
+	}}
+
+	t.Run("pins to locked commit", func(t *testing.T) {
+		spec, err := config.ParseRepoSpec("acme/standards@v1.0.0")
+		require.NoError(t, err)
+		rc, err := newRepoContext(context.Background(), nil, spec, lock)
+		require.NoError(t, err)
+		assert.Equal(t, "v1.0.0", rc.branch)
+		assert.Equal(t, "abc123", rc.fetchRef())
+	})
+
+	t.Run("fails when pinned ref differs", func(t *testing.T) {
+		spec, err := config.ParseRepoSpec("acme/standards@v2.0.0")
+		require.NoError(t, err)
+		_, err = newRepoContext(context.Background(), nil, spec, lock)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "locked at v1.0.0")
+	})
+
+	t.Run("fails when repo not locked", func(t *testing.T) {
+		spec, err := config.ParseRepoSpec("other/repo")
+		require.NoError(t, err)
+		_, err = newRepoContext(context.Background(), nil, spec, lock)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not in the lockfile")
+	})
+}
+
+func TestRepoContext_IncrementalFetch(t *testing.T) {
+	tempDir := t.TempDir()
+	c := cache.New(config.NewPathsWithOverrides(tempDir, tempDir))
+
+	unchanged := "# Review"
+	require.NoError(t, c.WriteBlob(cache.BlobSHA(unchanged), unchanged))
+
+	rc := &repoContext{
+		owner:   "acme",
+		repo:    "standards",
+		fetched: lockfile.NewRecorder(),
+		blobs:   c,
+		tree: &github.Tree{Entries: []github.TreeEntry{
+			{Path: "commands", Type: "tree"},
+			{Path: "commands/review.md", Type: "blob", SHA: cache.BlobSHA(unchanged)},
+		}},
+	}
+
+	// A nil client would panic if sync tried to hit the API for cached blobs
+	result, err := rc.fetchFile(context.Background(), "commands/review.md")
+	require.NoError(t, err)
+	assert.Equal(t, unchanged, result.Content)
+	assert.Equal(t, []lockfile.File{{Path: "commands/review.md", SHA: cache.BlobSHA(unchanged)}}, rc.fetched.Files())
+
+	entries, err := rc.listDirectory(context.Background(), "commands")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "review.md", entries[0].Name)
+
+	_, ok := rc.cachedFile("commands/missing.md")
+	assert.False(t, ok)
+}
+
+func TestRunConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	seen := make([]bool, 20)
+
+	runConcurrently(len(seen), 3, func(i int) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		seen[i] = true
+		mu.Unlock()
+	})
+
+	assert.LessOrEqual(t, maxInFlight, 3)
+	for i, ok := range seen {
+		assert.True(t, ok, "index %d not run", i)
+	}
+}
+
+func TestFetchAndWrite(t *testing.T) {
+	tempDir := t.TempDir()
+	c := cache.New(config.NewPathsWithOverrides(tempDir, tempDir))
+
+	var entries []github.TreeEntry
+	var jobs []fileJob
+	rc := &repoContext{owner: "acme", repo: "standards", fetched: lockfile.NewRecorder(), blobs: c}
+	for _, name := range []string{"b.md", "a.md", "nested/c.md"} {
+		content := "# " + name
+		sha := cache.BlobSHA(content)
+		require.NoError(t, c.WriteBlob(sha, content))
+		entries = append(entries, github.TreeEntry{Path: "rules/" + name, Type: "blob", SHA: sha})
+		jobs = append(jobs, fileJob{
+			rc:        rc,
+			path:      "rules/" + name,
+			localPath: filepath.Join(tempDir, "out", name),
+			itemType:  "rule",
+			name:      name,
+			group:     strings.Split(name, "/")[0],
+		})
+	}
+	rc.tree = &github.Tree{Entries: entries}
+
+	written := fetchAndWrite(context.Background(), jobs, 2)
+	require.Len(t, written, 3)
+
+	// Written jobs keep input order regardless of completion order
+	assert.Equal(t, "b.md", written[0].name)
+	assert.Equal(t, "a.md", written[1].name)
+	assert.Equal(t, "nested/c.md", written[2].name)
+	assert.Equal(t, 3, countGroups(written))
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "out", "nested", "c.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# nested/c.md", string(content))
+}
+
+func TestPrunerTrack(t *testing.T) {
+	write := func(t *testing.T, dir, rel, content string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	t.Run("removes files dropped since the last sync", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "review.md", "x")
+		write(t, dir, "old.md", "x")
+
+		pr := newPruner(true)
+		require.NoError(t, pr.track(dir, []string{"review.md", "old.md"}, nil))
+		require.NoError(t, pr.track(dir, []string{"review.md"}, nil))
+
+		assert.NoFileExists(t, filepath.Join(dir, "old.md"))
+		assert.FileExists(t, filepath.Join(dir, "review.md"))
+		assert.Equal(t, []string{filepath.Join(dir, "old.md")}, pr.removed)
+	})
+
+	t.Run("seeds from directory contents without a manifest", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "stale/rule.md", "x")
+		write(t, dir, "current.md", "x")
+
+		pr := newPruner(true)
+		require.NoError(t, pr.track(dir, []string{"current.md"}, nil))
+
+		assert.NoDirExists(t, filepath.Join(dir, "stale"))
+		assert.FileExists(t, filepath.Join(dir, "current.md"))
+	})
+
+	t.Run("never removes files the user took over", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "managed.md", merge.HeaderManagedPrefix+" -->\nold")
+		write(t, dir, "mine.md", merge.HeaderManagedPrefix+" -->\nold")
+
+		pr := newPruner(true)
+		require.NoError(t, pr.track(dir, []string{"managed.md", "mine.md"}, isManagedFileIn(dir)))
+
+		// The user rewrites one, dropping the header
+		write(t, dir, "mine.md", "my own command")
+		require.NoError(t, pr.track(dir, nil, isManagedFileIn(dir)))
+
+		assert.NoFileExists(t, filepath.Join(dir, "managed.md"))
+		assert.FileExists(t, filepath.Join(dir, "mine.md"))
+	})
+
+	t.Run("doesn't seed directories shared with the user", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "react/SKILL.md", skills.HeaderManagedPrefix+" -->\nUse hooks.")
+		write(t, dir, "react/my-notes.md", "added by hand")
+
+		pr := newPruner(true)
+		require.NoError(t, pr.track(dir, []string{"react/SKILL.md"}, isManagedSkillFileIn(dir)))
+		assert.FileExists(t, filepath.Join(dir, "react", "my-notes.md"))
+
+		// The manifest records only what sync installed, so later syncs leave the file alone too
+		require.NoError(t, pr.track(dir, []string{"react/SKILL.md"}, isManagedSkillFileIn(dir)))
+		assert.FileExists(t, filepath.Join(dir, "react", "my-notes.md"))
+		assert.Empty(t, pr.removed)
+	})
+
+	t.Run("no-prune keeps and keeps tracking stale files", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "old.md", "x")
+
+		require.NoError(t, newPruner(false).track(dir, nil, nil))
+		assert.FileExists(t, filepath.Join(dir, "old.md"))
+
+		// A later sync without --no-prune still knows to remove it
+		require.NoError(t, newPruner(true).track(dir, nil, nil))
+		assert.NoFileExists(t, filepath.Join(dir, "old.md"))
+	})
+
+	t.Run("nil pruner is a no-op", func(t *testing.T) {
+		var pr *pruner
+		assert.NoError(t, pr.track(t.TempDir(), nil, nil))
+	})
+}
+
+func TestSyncClaudeCommandsPrunesRemoved(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	paths := config.NewPathsWithOverrides(filepath.Join(tempDir, "config"), filepath.Join(tempDir, "cache"))
+
+	teamDir := paths.TeamCommandsDir("acme", "standards")
+	require.NoError(t, os.MkdirAll(teamDir, 0755))
+	for _, name := range []string{"review", "debug"} {
+		require.NoError(t, os.WriteFile(filepath.Join(teamDir, name+".md"), []byte("---\nname: "+name+"\ndescription: "+name+"\n---\nDo "+name), 0644))
+	}
+
+	pr := newPruner(true)
+	count, err := syncClaudeCommands(paths, "acme", "standards", pr)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// Command removed upstream
+	require.NoError(t, os.Remove(filepath.Join(teamDir, "debug.md")))
+	count, err = syncClaudeCommands(paths, "acme", "standards", pr)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	claudeDir := paths.ClaudeCommandsDir()
+	assert.FileExists(t, filepath.Join(claudeDir, "review.md"))
+	assert.NoFileExists(t, filepath.Join(claudeDir, "debug.md"))
+}
+
+func TestRunSync_LocalSource(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// A source repo laid out on disk exactly like a GitHub source
+	sourceDir := filepath.Join(t.TempDir(), "standards")
+	files := map[string]string{
+		"CLAUDE.md":                "## Team\n\nUse tabs.",
+		"commands/review.md":       "---\nname: review\ndescription: Review code\n---\nReview it",
+		"templates/service.md":     "# Service",
+		"languages/go.md":          "## Go\n\nRun gofmt.",
+		"evals/security.yaml":      "name: security",
+		"rules/security.md":        "Never log secrets.",
+		"rules/api/rest.md":        "Use nouns.",
+		"skills/react/SKILL.md":    "---\nname: react\ndescription: React help\n---\nUse hooks.",
+		"skills/react/examples.md": "Example",
+	}
+	for path, content := range files {
+		full := filepath.Join(sourceDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	paths := config.NewPaths()
+	require.NoError(t, config.SaveTo(config.NewSimpleConfig("file://"+sourceDir), paths.ConfigFile))
+
+	require.NoError(t, runSync(context.Background(), &syncOptions{}))
+
+	spec, err := config.ParseRepoSpec("file://" + sourceDir)
+	require.NoError(t, err)
+	owner, repo := spec.CacheKey()
+
+	cached, err := os.ReadFile(paths.CacheFile(owner, repo))
+	require.NoError(t, err)
+	assert.Equal(t, files["CLAUDE.md"], string(cached))
+
+	assert.FileExists(t, filepath.Join(paths.TeamCommandsDir(owner, repo), "review.md"))
+	assert.FileExists(t, filepath.Join(paths.TeamTemplatesDir(owner, repo), "service.md"))
+	assert.FileExists(t, filepath.Join(paths.TeamLanguagesDir(owner, repo), "go.md"))
+	assert.FileExists(t, filepath.Join(paths.TeamEvalsDir(owner, repo), "security.yaml"))
+	assert.FileExists(t, filepath.Join(paths.TeamRulesDir(owner, repo), "api", "rest.md"))
+	assert.FileExists(t, filepath.Join(paths.TeamSkillsDir(owner, repo), "react", "examples.md"))
+
+	output, err := os.ReadFile(filepath.Join(home, ".claude", "CLAUDE.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "Use tabs.")
+
+	// Edits on disk are picked up by the next sync without --force
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "CLAUDE.md"), []byte("## Team\n\nUse spaces."), 0644))
+	require.NoError(t, runSync(context.Background(), &syncOptions{}))
+
+	cached, err = os.ReadFile(paths.CacheFile(owner, repo))
+	require.NoError(t, err)
+	assert.Contains(t, string(cached), "Use spaces.")
+}
+
+func TestRunSync_MultiSourceRulesEvalsTemplates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeRepo := func(name string, files map[string]string) string {
+		dir := filepath.Join(t.TempDir(), name)
+		for path, content := range files {
+			full := filepath.Join(dir, filepath.FromSlash(path))
+			require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+			require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+		}
+		return "file://" + dir
+	}
+
+	standards := writeRepo("standards", map[string]string{
+		"CLAUDE.md":                 "## Team\n\nUse tabs.",
+		"rules/api.md":              "Use nouns.",
+		"rules/security/old.md":     "Outdated advice.",
+		"evals/style.yaml":          "name: style",
+		"evals/security-base.yaml":  "name: stale",
+		"templates/service.md":      "# Service",
+		"templates/library.md":      "# Old library",
+		"templates/unrelated.md":    "# Unrelated",
+		"rules/security/secrets.md": "Old secrets rule.",
+	})
+	security := writeRepo("security", map[string]string{
+		"CLAUDE.md":                 "## Security",
+		"rules/security/secrets.md": "Never log secrets.",
+		"rules/security/tls.md":     "Require TLS 1.2.",
+		"rules/unowned.md":          "Not configured here.",
+		"evals/security-base.yaml":  "name: security-base",
+		"templates/library.md":      "# Library",
+	})
+
+	cfg := &config.Config{
+		Version: config.DefaultVersion,
+		Source: config.Source{Multi: &config.SourceConfig{
+			Default:   standards,
+			Rules:     map[string]string{"security/": security},
+			Evals:     map[string]string{"security-base": security},
+			Templates: map[string]string{"library": security, "missing": security},
+		}},
+		Cache: config.CacheConfig{TTL: config.DefaultCacheTTL},
+	}
+	paths := config.NewPaths()
+	require.NoError(t, config.SaveTo(cfg, paths.ConfigFile))
+
+	output, err := captureStdout(func() error {
+		return runSync(context.Background(), &syncOptions{})
+	})
+	require.NoError(t, err)
+	assert.Contains(t, output, "template missing not found in explicitly configured source")
+
+	stdOwner, stdRepo, err := config.ParseRepo(standards)
+	require.NoError(t, err)
+	secOwner, secRepo, err := config.ParseRepo(security)
+	require.NoError(t, err)
+
+	// Each item is cached under the repo that owns it
+	assert.FileExists(t, filepath.Join(paths.TeamRulesDir(stdOwner, stdRepo), "api.md"))
+	assert.NoFileExists(t, filepath.Join(paths.TeamRulesDir(stdOwner, stdRepo), "security", "old.md"))
+	assert.FileExists(t, filepath.Join(paths.TeamRulesDir(secOwner, secRepo), "security", "tls.md"))
+	assert.NoFileExists(t, filepath.Join(paths.TeamRulesDir(secOwner, secRepo), "unowned.md"))
+	assert.FileExists(t, filepath.Join(paths.TeamEvalsDir(secOwner, secRepo), "security-base.yaml"))
+	assert.NoFileExists(t, filepath.Join(paths.TeamEvalsDir(stdOwner, stdRepo), "security-base.yaml"))
+	assert.FileExists(t, filepath.Join(paths.TeamTemplatesDir(secOwner, secRepo), "library.md"))
+
+	// Claude Code rules combine both sources
+	secrets, err := os.ReadFile(filepath.Join(paths.ClaudeRulesDir(), "security", "secrets.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(secrets), "Never log secrets.")
+	assert.FileExists(t, filepath.Join(paths.ClaudeRulesDir(), "api.md"))
+
+	registry, err := loadTeamRuleRegistry(cfg, paths, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, registry.Count())
+
+	names, templatePaths := listTeamTemplates(cfg, paths)
+	assert.Equal(t, []string{"library", "service", "unrelated"}, names)
+	assert.Equal(t, filepath.Join(paths.TeamTemplatesDir(secOwner, secRepo), "library.md"), templatePaths["library"])
+}
+
+func TestRunSync_SubdirSource(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// Standards live in a subdirectory of a larger repo
+	repoDir := filepath.Join(t.TempDir(), "monorepo")
+	files := map[string]string{
+		"CLAUDE.md":                              "## Monorepo\n\nNot the standards.",
+		"platform/ai-standards/CLAUDE.md":        "## Team\n\nUse tabs.",
+		"platform/ai-standards/rules/api.md":     "Use nouns.",
+		"platform/ai-standards/commands/ship.md": "---\nname: ship\ndescription: Ship it\n---\nShip it",
+		"rules/root.md":                          "Not a standards rule.",
+	}
+	for path, content := range files {
+		full := filepath.Join(repoDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	source := "file://" + repoDir + "//platform/ai-standards"
+	paths := config.NewPaths()
+	require.NoError(t, config.SaveTo(config.NewSimpleConfig(source), paths.ConfigFile))
+
+	require.NoError(t, runSync(context.Background(), &syncOptions{}))
+
+	owner, repo, err := config.ParseRepo(source)
+	require.NoError(t, err)
+
+	cached, err := os.ReadFile(paths.CacheFile(owner, repo))
+	require.NoError(t, err)
+	assert.Equal(t, files["platform/ai-standards/CLAUDE.md"], string(cached))
+	assert.FileExists(t, filepath.Join(paths.TeamRulesDir(owner, repo), "api.md"))
+	assert.NoFileExists(t, filepath.Join(paths.TeamRulesDir(owner, repo), "root.md"))
+	assert.FileExists(t, filepath.Join(paths.TeamCommandsDir(owner, repo), "ship.md"))
+
+	output, err := os.ReadFile(filepath.Join(home, ".claude", "CLAUDE.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "Source: "+source)
+
+	// The lockfile records paths relative to the subdirectory
+	lock, err := lockfile.Load(paths.LockFile)
+	require.NoError(t, err)
+	spec, err := config.ParseRepoSpec(source)
+	require.NoError(t, err)
+	locked := lock.FindSource(spec.FullName())
+	require.NotNil(t, locked)
+	assert.NotNil(t, locked.FindFile("rules/api.md"))
+}
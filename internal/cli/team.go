@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/HartBrook/staghorn/internal/commands"
 	"github.com/HartBrook/staghorn/internal/config"
 	"github.com/HartBrook/staghorn/internal/eval"
+	"github.com/HartBrook/staghorn/internal/optimize"
+	"github.com/HartBrook/staghorn/internal/rules"
+	"github.com/HartBrook/staghorn/internal/signing"
 	"github.com/HartBrook/staghorn/internal/skills"
 	"github.com/HartBrook/staghorn/internal/starter"
 	"github.com/spf13/cobra"
@@ -29,6 +33,7 @@ commands, language configs, and templates that team members sync from.`,
 
 	cmd.AddCommand(NewTeamInitCmd())
 	cmd.AddCommand(NewTeamValidateCmd())
+	cmd.AddCommand(NewTeamChecksumsCmd())
 
 	return cmd
 }
@@ -62,9 +67,21 @@ team's shared standards repo.`,
 	return cmd
 }
 
+// defaultRuleTokenBudget is the rule body size team validate warns above.
+// Matching rules are loaded into context alongside CLAUDE.md.
+const defaultRuleTokenBudget = 1000
+
+// teamValidateOptions holds the flags of team validate.
+type teamValidateOptions struct {
+	sampleTree  string // Directory to check rule path patterns against
+	tokenBudget int    // Rule body size to warn above, or 0 to skip the check
+}
+
 // NewTeamValidateCmd creates the team validate command.
 func NewTeamValidateCmd() *cobra.Command {
-	return &cobra.Command{
+	opts := teamValidateOptions{}
+
+	cmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate team repository structure",
 		Long: `Validate that the current directory is a valid team repository.
@@ -73,11 +90,56 @@ Checks:
 - CLAUDE.md exists and is non-empty
 - Commands in commands/ have valid YAML frontmatter
 - Languages in languages/ are valid markdown
-- Templates in templates/ are valid markdown (optional)`,
+- Templates in templates/ are valid markdown (optional)
+- Rules in rules/ have valid frontmatter and path patterns (optional)
+
+Rules are also linted for identical scopes and bodies over the token budget.
+With --sample-tree, each rule path pattern is matched against the files in a
+checkout of a project the rules are meant for, and patterns that match
+nothing are reported.`,
+		Example: `  staghorn team validate
+  staghorn team validate --sample-tree ../my-service
+  staghorn team validate --token-budget 500`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runTeamValidate()
+			return runTeamValidate(opts)
 		},
 	}
+
+	cmd.Flags().StringVar(&opts.sampleTree, "sample-tree", "", "Project directory to check rule path patterns against")
+	cmd.Flags().IntVar(&opts.tokenBudget, "token-budget", defaultRuleTokenBudget, "Warn about rules whose body exceeds this many tokens (0 to disable)")
+
+	return cmd
+}
+
+// NewTeamChecksumsCmd creates the team checksums command.
+func NewTeamChecksumsCmd() *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "checksums",
+		Short: "Write the checksums manifest for signing",
+		Long: `Writes .staghorn/checksums.json listing the sha256 of every file in the
+current directory (except .git). Sign it so members who pin your key can verify
+what they sync:
+
+  ssh-keygen -Y sign -n staghorn -f ~/.ssh/id_ed25519 .staghorn/checksums.json
+  # or: minisign -Sm .staghorn/checksums.json
+
+Commit the manifest and its signature (.sig or .minisig) with every change.`,
+		Example: `  staghorn team checksums          # Regenerate the manifest
+  staghorn team checksums --check  # Fail if the manifest is out of date (for CI)`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get working directory: %w", err)
+			}
+			return runTeamChecksums(cwd, check)
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Verify the manifest matches the files instead of writing it")
+
+	return cmd
 }
 
 func runTeamInit(nonInteractive, noTemplates, noReadme bool) error {
@@ -271,7 +333,17 @@ func runTeamInit(nonInteractive, noTemplates, noReadme bool) error {
 	return nil
 }
 
-func runTeamValidate() error {
+func runTeamValidate(opts teamValidateOptions) error {
+	// Read the sample tree first, so a bad path fails before any output
+	var sampleFiles []string
+	if opts.sampleTree != "" {
+		files, err := listSampleTree(opts.sampleTree)
+		if err != nil {
+			return err
+		}
+		sampleFiles = files
+	}
+
 	fmt.Println()
 	fmt.Println("Validating team repository...")
 	fmt.Println()
@@ -390,6 +462,30 @@ func runTeamValidate() error {
 		fmt.Printf("%s skills/ - directory not found (optional)\n", warningIcon)
 	}
 
+	// Check rules/ (optional)
+	if _, err := os.Stat("rules"); err == nil {
+		ruleList, ruleErrs := validateRules("rules")
+		if len(ruleList) == 0 && len(ruleErrs) == 0 {
+			fmt.Printf("%s rules/ - directory empty\n", warningIcon)
+			warnings++
+		} else if len(ruleErrs) > 0 {
+			for _, e := range ruleErrs {
+				printError("%s", e)
+			}
+			errors += len(ruleErrs)
+		} else {
+			printSuccess("rules/ - %d valid rules", len(ruleList))
+		}
+
+		lints := lintRules(ruleList, sampleFiles, opts.tokenBudget)
+		for _, w := range lints {
+			fmt.Printf("%s %s\n", warningIcon, w)
+		}
+		warnings += len(lints)
+	} else {
+		fmt.Printf("%s rules/ - directory not found (optional)\n", warningIcon)
+	}
+
 	// Summary
 	fmt.Println()
 	if errors > 0 {
@@ -741,3 +837,151 @@ func validateSkills(dir string) (valid, total int, errs []string) {
 
 	return valid, total, errs
 }
+
+// validateRules parses every rule in dir, returning the rules that parsed
+// and an error for each that didn't.
+func validateRules(dir string) ([]*rules.Rule, []string) {
+	ruleList, err := rules.LoadFromDirectory(dir, rules.SourceTeam)
+	if err == nil {
+		return ruleList, nil
+	}
+
+	var errs []string
+	if parseErrs, ok := err.(*rules.ParseErrors); ok {
+		for _, e := range parseErrs.Errors {
+			errs = append(errs, e.Error())
+		}
+	} else {
+		errs = append(errs, err.Error())
+	}
+	return ruleList, errs
+}
+
+// lintRules returns warnings for rules that parse but are likely mistakes:
+// invalid paths patterns (sync skips those rules), patterns that match no
+// file in sampleFiles (when given), path-scoped rules with identical scopes,
+// and bodies over tokenBudget (when non-zero).
+func lintRules(ruleList []*rules.Rule, sampleFiles []string, tokenBudget int) []string {
+	var warnings []string
+	scopes := make(map[string][]string) // Sorted patterns -> rule files
+
+	for _, rule := range ruleList {
+		name := filepath.ToSlash(filepath.Join("rules", rule.RelPath))
+
+		if rule.HasPathScope() {
+			for _, pattern := range rule.Paths {
+				if err := rules.ValidateGlob(pattern); err != nil {
+					warnings = append(warnings, fmt.Sprintf("%s - invalid paths pattern %v; sync skips this rule", name, err))
+				} else if sampleFiles != nil && !matchesAny(pattern, sampleFiles) {
+					warnings = append(warnings, fmt.Sprintf("%s - pattern %q matches no files in the sample tree", name, pattern))
+				}
+			}
+
+			patterns := append([]string(nil), rule.Paths...)
+			sort.Strings(patterns)
+			key := strings.Join(patterns, "\n")
+			scopes[key] = append(scopes[key], name)
+		}
+
+		if tokens := optimize.CountTokens(rule.Body); tokenBudget > 0 && tokens > tokenBudget {
+			warnings = append(warnings, fmt.Sprintf("%s - body is ~%d tokens, over the budget of %d", name, tokens, tokenBudget))
+		}
+	}
+
+	keys := make([]string, 0, len(scopes))
+	for key := range scopes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if names := scopes[key]; len(names) > 1 {
+			warnings = append(warnings, fmt.Sprintf("%s have identical paths (%s); merge them or narrow their scopes",
+				strings.Join(names, ", "), strings.ReplaceAll(key, "\n", ", ")))
+		}
+	}
+
+	return warnings
+}
+
+// matchesAny reports whether pattern matches any of files.
+func matchesAny(pattern string, files []string) bool {
+	for _, file := range files {
+		if rules.MatchGlob(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// listSampleTree returns the files under dir as slash-separated paths
+// relative to it, skipping .git.
+func listSampleTree(dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sample tree: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("sample tree %s is not a directory", dir)
+	}
+
+	files := []string{}
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sample tree: %w", err)
+	}
+	return files, nil
+}
+
+func runTeamChecksums(dir string, check bool) error {
+	checksums, err := signing.Generate(dir)
+	if err != nil {
+		return fmt.Errorf("failed to hash files: %w", err)
+	}
+	data, err := checksums.Marshal()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, filepath.FromSlash(signing.ChecksumsFile))
+
+	if check {
+		existing, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("no %s; run 'staghorn team checksums' to create it", signing.ChecksumsFile)
+		}
+		if string(existing) != string(data) {
+			return fmt.Errorf("%s is out of date; run 'staghorn team checksums' and sign it again", signing.ChecksumsFile)
+		}
+		printSuccess("%s matches %d files", signing.ChecksumsFile, len(checksums.Files))
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", signing.ChecksumsFile, err)
+	}
+
+	printSuccess("Wrote %s (%d files)", signing.ChecksumsFile, len(checksums.Files))
+	fmt.Println()
+	fmt.Println("Sign it with one of:")
+	fmt.Printf("  ssh-keygen -Y sign -n %s -f ~/.ssh/id_ed25519 %s\n", signing.Namespace, signing.ChecksumsFile)
+	fmt.Printf("  minisign -Sm %s\n", signing.ChecksumsFile)
+	return nil
+}
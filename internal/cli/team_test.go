@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/rules"
 )
 
 func TestTeamInitNonInteractive(t *testing.T) {
@@ -148,7 +149,7 @@ func TestTeamValidate_ValidRepo(t *testing.T) {
 	}
 
 	// Validate should succeed
-	err = runTeamValidate()
+	err = runTeamValidate(teamValidateOptions{})
 	if err != nil {
 		t.Errorf("runTeamValidate failed on valid repo: %v", err)
 	}
@@ -168,7 +169,7 @@ func TestTeamValidate_MissingClaudeMD(t *testing.T) {
 	}
 
 	// Create empty directory - no CLAUDE.md
-	err = runTeamValidate()
+	err = runTeamValidate(teamValidateOptions{})
 	if err == nil {
 		t.Error("expected validation to fail without CLAUDE.md")
 	}
@@ -201,7 +202,7 @@ func TestTeamValidate_InvalidCommand(t *testing.T) {
 	}
 
 	// Validate should fail
-	err = runTeamValidate()
+	err = runTeamValidate(teamValidateOptions{})
 	if err == nil {
 		t.Error("expected validation to fail with invalid command")
 	}
@@ -348,7 +349,7 @@ func TestTeamValidate_EmptyClaudeMD(t *testing.T) {
 	}
 
 	// Validate should fail
-	err = runTeamValidate()
+	err = runTeamValidate(teamValidateOptions{})
 	if err == nil {
 		t.Error("expected validation to fail with empty CLAUDE.md")
 	}
@@ -381,7 +382,7 @@ func TestTeamValidate_EmptyLanguageFile(t *testing.T) {
 	}
 
 	// Validate should fail
-	err = runTeamValidate()
+	err = runTeamValidate(teamValidateOptions{})
 	if err == nil {
 		t.Error("expected validation to fail with empty language file")
 	}
@@ -414,7 +415,7 @@ func TestTeamValidate_EmptyTemplateFile(t *testing.T) {
 	}
 
 	// Validate should fail
-	err = runTeamValidate()
+	err = runTeamValidate(teamValidateOptions{})
 	if err == nil {
 		t.Error("expected validation to fail with empty template file")
 	}
@@ -534,7 +535,7 @@ func TestTeamValidate_ChecksSourceYaml(t *testing.T) {
 
 	// Validate should pass (source.yaml is optional but warned about)
 	// This test just verifies we don't crash when source.yaml is missing
-	_ = runTeamValidate() // May return error due to missing CLAUDE.md content checks
+	_ = runTeamValidate(teamValidateOptions{}) // May return error due to missing CLAUDE.md content checks
 
 	// Now create source.yaml and validate again
 	if err := config.WriteSourceRepoConfig(tmpDir); err != nil {
@@ -542,5 +543,106 @@ func TestTeamValidate_ChecksSourceYaml(t *testing.T) {
 	}
 
 	// Validate should still work
-	_ = runTeamValidate()
+	_ = runTeamValidate(teamValidateOptions{})
+}
+
+func TestTeamValidate_InvalidRuleGlob(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(origDir) }()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+
+	if err := os.WriteFile("CLAUDE.md", []byte("# Team Standards"), 0644); err != nil {
+		t.Fatalf("failed to write CLAUDE.md: %v", err)
+	}
+	if err := os.MkdirAll("rules", 0755); err != nil {
+		t.Fatalf("failed to create rules dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("rules", "api.md"), []byte("---\npaths:\n  - \"src/api/**.ts\"\n---\n\n# API"), 0644); err != nil {
+		t.Fatalf("failed to write api.md: %v", err)
+	}
+
+	output, err := captureStdout(func() error {
+		return runTeamValidate(teamValidateOptions{})
+	})
+	if err != nil {
+		t.Errorf("an invalid pattern should be a lint warning, got error: %v", err)
+	}
+	if !strings.Contains(output, `rules/api.md - invalid paths pattern "src/api/**.ts": ** must be a whole path segment`) {
+		t.Errorf("expected an invalid pattern warning, got:\n%s", output)
+	}
+}
+
+func TestLintRules(t *testing.T) {
+	ruleList := []*rules.Rule{
+		{RelPath: "api.md", Frontmatter: rules.Frontmatter{Paths: []string{"src/api/**/*.go", "cmd/**"}}},
+		{RelPath: "handlers.md", Frontmatter: rules.Frontmatter{Paths: []string{"cmd/**", "src/api/**/*.go"}}},
+		{RelPath: "web.md", Frontmatter: rules.Frontmatter{Paths: []string{"web/**/*.tsx"}}},
+		{RelPath: "security.md", Body: strings.Repeat("word ", 100)},
+	}
+	sampleFiles := []string{"src/api/users.go", "cmd/server/main.go", "README.md"}
+
+	warnings := lintRules(ruleList, sampleFiles, 50)
+	joined := strings.Join(warnings, "\n")
+
+	for _, want := range []string{
+		`rules/web.md - pattern "web/**/*.tsx" matches no files`,
+		"rules/api.md, rules/handlers.md have identical paths",
+		"rules/security.md - body is ~125 tokens, over the budget of 50",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected warning %q, got:\n%s", want, joined)
+		}
+	}
+	if len(warnings) != 3 {
+		t.Errorf("got %d warnings, want 3:\n%s", len(warnings), joined)
+	}
+
+	// Without a sample tree or budget, only the identical scopes remain
+	if warnings := lintRules(ruleList, nil, 0); len(warnings) != 1 {
+		t.Errorf("got %v, want only the identical scopes warning", warnings)
+	}
+}
+
+func TestTeamValidate_SampleTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(origDir) }()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+
+	if err := os.WriteFile("CLAUDE.md", []byte("# Team Standards"), 0644); err != nil {
+		t.Fatalf("failed to write CLAUDE.md: %v", err)
+	}
+	if err := os.MkdirAll("rules", 0755); err != nil {
+		t.Fatalf("failed to create rules dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("rules", "api.md"), []byte("---\npaths:\n  - \"internal/api/**\"\n---\n\n# API"), 0644); err != nil {
+		t.Fatalf("failed to write api.md: %v", err)
+	}
+
+	sample := filepath.Join(tmpDir, "sample")
+	if err := os.MkdirAll(filepath.Join(sample, "internal", "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sample, "internal", "web", "server.go"), []byte("package web"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := captureStdout(func() error { return runTeamValidate(teamValidateOptions{sampleTree: sample}) })
+	if err != nil {
+		t.Fatalf("unmatched patterns should only warn: %v", err)
+	}
+	if !strings.Contains(out, `pattern "internal/api/**" matches no files`) {
+		t.Errorf("expected unmatched pattern warning:\n%s", out)
+	}
+
+	if _, err := captureStdout(func() error {
+		return runTeamValidate(teamValidateOptions{sampleTree: filepath.Join(tmpDir, "missing")})
+	}); err == nil {
+		t.Error("expected error for a missing sample tree")
+	}
 }
@@ -2,11 +2,13 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/fsutil"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,6 +24,16 @@ type CacheConfig struct {
 	TTL string `yaml:"ttl"` // e.g., "24h"
 }
 
+// SyncConfig contains sync settings.
+type SyncConfig struct {
+	Concurrency int `yaml:"concurrency,omitempty"` // Max parallel fetches (default: 4)
+}
+
+// GitHubConfig contains GitHub settings.
+type GitHubConfig struct {
+	Host string `yaml:"host,omitempty"` // GitHub Enterprise Server hostname (default: github.com)
+}
+
 // OptimizeConfig contains optimization settings.
 type OptimizeConfig struct {
 	WarnThreshold     int    `yaml:"warn_threshold,omitempty"`     // Token threshold for warning (default: 3000)
@@ -35,14 +47,18 @@ type Config struct {
 	Version int `yaml:"version"`
 
 	// Source defines where to fetch configs from.
-	// Can be a simple string ("owner/repo") or a structured object for multi-source.
+	// Can be a simple string ("owner/repo", optionally pinned with "@ref")
+	// or a structured object for multi-source.
 	Source Source `yaml:"source"`
 
 	// Trusted is a list of repos/orgs that don't require confirmation.
-	// Examples: "acme-corp" (trusts all repos from org), "user/repo" (specific repo)
-	Trusted []string `yaml:"trusted,omitempty"`
+	// Examples: "acme-corp" (trusts all repos from org), "user/repo" (specific repo).
+	// Entries can also set a skills policy: {repo: acme-corp, skills: allow}.
+	Trusted []TrustedSource `yaml:"trusted,omitempty"`
 
 	Cache     CacheConfig    `yaml:"cache"`
+	Sync      SyncConfig     `yaml:"sync,omitempty"`
+	GitHub    GitHubConfig   `yaml:"github,omitempty"`
 	Languages LanguageConfig `yaml:"languages,omitempty"`
 	Optimize  OptimizeConfig `yaml:"optimize,omitempty"`
 }
@@ -52,6 +68,10 @@ const (
 	DefaultVersion  = 1
 	DefaultPath     = "CLAUDE.md"
 	DefaultCacheTTL = "24h"
+
+	DefaultSyncConcurrency = 4
+	// MaxSyncConcurrency caps parallel fetches to stay clear of GitHub's secondary rate limits.
+	MaxSyncConcurrency = 16
 )
 
 // Load reads and validates config from the default location.
@@ -77,10 +97,18 @@ func LoadFrom(path string) (*Config, error) {
 
 	cfg.applyDefaults()
 
+	// URLs on the configured GitHub Enterprise host are GitHub sources
+	cfg.Source.resolveGitHubURLs(cfg.SourceGitHubHost(), cfg.GitHubHost())
+
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
 
+	// Relative local sources are relative to the config file, not the working directory
+	if dir, err := filepath.Abs(filepath.Dir(path)); err == nil {
+		cfg.resolveLocalPaths(dir)
+	}
+
 	return &cfg, nil
 }
 
@@ -105,7 +133,19 @@ func SaveTo(cfg *Config, path string) error {
 		return errors.Wrap(errors.ErrConfigInvalid, "failed to create config directory", "", err)
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return fsutil.WriteFile(path, data, 0644)
+}
+
+// resolveLocalPaths makes relative local sources and trusted entries absolute
+// against dir. Saving writes them back as they were.
+func (c *Config) resolveLocalPaths(dir string) {
+	c.Source.resolveLocalPaths(dir)
+	for i, t := range c.Trusted {
+		if resolved := ResolveLocalPath(t.Repo, dir); resolved != t.Repo {
+			c.Trusted[i].written = t.Repo
+			c.Trusted[i].Repo = resolved
+		}
+	}
 }
 
 // Validate checks config for required fields and valid values.
@@ -120,6 +160,23 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Sync.Concurrency < 0 || c.Sync.Concurrency > MaxSyncConcurrency {
+		return errors.ConfigInvalid(fmt.Sprintf("sync.concurrency must be between 0 (default) and %d", MaxSyncConcurrency))
+	}
+
+	for _, t := range c.Trusted {
+		if err := t.Validate(); err != nil {
+			return errors.ConfigInvalid(err.Error())
+		}
+	}
+
+	if c.GitHub.Host != "" && !hostPattern.MatchString(c.GitHub.Host) {
+		return errors.ConfigInvalid("github.host must be a hostname like ghe.example.com")
+	}
+	if c.Source.Multi != nil && c.Source.Multi.Host != "" && !hostPattern.MatchString(c.Source.Multi.Host) {
+		return errors.ConfigInvalid("source.host must be a hostname like ghe.example.com")
+	}
+
 	return nil
 }
 
@@ -146,6 +203,29 @@ func (c *CacheConfig) TTLDuration() time.Duration {
 	return d
 }
 
+// SyncConcurrency returns the max number of parallel fetches during sync.
+func (c *Config) SyncConcurrency() int {
+	if c.Sync.Concurrency <= 0 {
+		return DefaultSyncConcurrency
+	}
+	return c.Sync.Concurrency
+}
+
+// GitHubHost returns the configured GitHub Enterprise host, or "" for github.com.
+// Search, init, and trust links use this host.
+func (c *Config) GitHubHost() string {
+	return normalizeGitHubHost(c.GitHub.Host)
+}
+
+// SourceGitHubHost returns the host for GitHub sources that don't name one:
+// source.host if set, otherwise github.host. Returns "" for github.com.
+func (c *Config) SourceGitHubHost() string {
+	if c.Source.Multi != nil && c.Source.Multi.Host != "" {
+		return normalizeGitHubHost(c.Source.Multi.Host)
+	}
+	return c.GitHubHost()
+}
+
 // Exists checks if a config file exists at the default location.
 func Exists() bool {
 	paths := NewPaths()
@@ -157,13 +237,23 @@ func Exists() bool {
 // It checks both the user's trusted list and the default trusted sources.
 func (c *Config) IsTrustedSource(repo string) bool {
 	// Check user's trusted list
-	if IsTrusted(repo, c.Trusted) {
+	if IsTrusted(repo, TrustedRepos(c.Trusted)) {
 		return true
 	}
 	// Check default trusted sources
 	return IsTrusted(repo, DefaultTrustedSources)
 }
 
+// SkillPolicy returns how risky skill changes from repo are handled.
+func (c *Config) SkillPolicy(repo string) SkillPolicy {
+	return SkillPolicyFor(repo, c.Trusted)
+}
+
+// SigningKeys returns the public keys that must sign repo's content, if any.
+func (c *Config) SigningKeys(repo string) []string {
+	return KeysFor(repo, c.Trusted)
+}
+
 // NewSimpleConfig creates a config with a single source.
 func NewSimpleConfig(repo string) *Config {
 	return &Config{
@@ -183,6 +273,11 @@ func (c *Config) DefaultOwnerRepo() (owner, repo string, err error) {
 	return ParseRepo(c.Source.DefaultRepo())
 }
 
+// DefaultRepoSpec returns the parsed default source, including any pinned ref.
+func (c *Config) DefaultRepoSpec() (*RepoSpec, error) {
+	return ParseRepoSpec(c.Source.DefaultRepo())
+}
+
 // SourceRepo returns the full repo string for display purposes.
 func (c *Config) SourceRepo() string {
 	return c.Source.DefaultRepo()
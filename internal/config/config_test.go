@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -96,6 +97,17 @@ func TestParseRepo(t *testing.T) {
 			wantOwner: "acme",
 			wantRepo:  "standards",
 		},
+		{
+			name:      "pinned ref is ignored",
+			repo:      "acme/standards@v1.4.0",
+			wantOwner: "acme",
+			wantRepo:  "standards",
+		},
+		{
+			name:    "empty ref",
+			repo:    "acme/standards@",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -124,6 +136,496 @@ func TestParseRepo(t *testing.T) {
 	}
 }
 
+func TestParseRepoSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     string
+		wantRepo string
+		wantRef  string
+		wantStr  string
+		wantErr  bool
+	}{
+		{
+			name:     "no ref",
+			repo:     "acme/standards",
+			wantRepo: "acme/standards",
+			wantStr:  "acme/standards",
+		},
+		{
+			name:     "tag",
+			repo:     "acme/standards@v1.4.0",
+			wantRepo: "acme/standards",
+			wantRef:  "v1.4.0",
+			wantStr:  "acme/standards@v1.4.0",
+		},
+		{
+			name:     "branch with slash",
+			repo:     "acme/standards@release/2024",
+			wantRepo: "acme/standards",
+			wantRef:  "release/2024",
+			wantStr:  "acme/standards@release/2024",
+		},
+		{
+			name:     "commit sha",
+			repo:     "acme/standards@3f2a9c1d",
+			wantRepo: "acme/standards",
+			wantRef:  "3f2a9c1d",
+			wantStr:  "acme/standards@3f2a9c1d",
+		},
+		{
+			name:     "URL with ref",
+			repo:     "https://github.com/acme/standards@v2",
+			wantRepo: "acme/standards",
+			wantRef:  "v2",
+			wantStr:  "acme/standards@v2",
+		},
+		{
+			name:     "skill path with ref",
+			repo:     "vercel-labs/agent-skills/skills/react@v1",
+			wantRepo: "vercel-labs/agent-skills",
+			wantRef:  "v1",
+			wantStr:  "vercel-labs/agent-skills@v1",
+		},
+		{
+			name:    "empty ref",
+			repo:    "acme/standards@",
+			wantErr: true,
+		},
+		{
+			name:    "ref with spaces",
+			repo:    "acme/standards@bad ref",
+			wantErr: true,
+		},
+		{
+			name:    "ref with parent traversal",
+			repo:    "acme/standards@v1..v2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseRepoSpec(tt.repo)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRepoSpec() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRepoSpec() unexpected error: %v", err)
+			}
+			if spec.FullName() != tt.wantRepo {
+				t.Errorf("FullName() = %q, want %q", spec.FullName(), tt.wantRepo)
+			}
+			if spec.Ref != tt.wantRef {
+				t.Errorf("Ref = %q, want %q", spec.Ref, tt.wantRef)
+			}
+			if spec.IsPinned() != (tt.wantRef != "") {
+				t.Errorf("IsPinned() = %v, want %v", spec.IsPinned(), tt.wantRef != "")
+			}
+			if spec.String() != tt.wantStr {
+				t.Errorf("String() = %q, want %q", spec.String(), tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestParseRepoSpec_Providers(t *testing.T) {
+	tests := []struct {
+		name         string
+		repo         string
+		wantProvider string
+		wantHost     string
+		wantURL      string
+		wantOwner    string
+		wantRepo     string
+		wantRef      string
+		wantFullName string
+		wantCacheKey string
+		wantErr      bool
+	}{
+		{
+			name:         "github prefix",
+			repo:         "github:acme/standards@v1",
+			wantProvider: ProviderGitHub,
+			wantOwner:    "acme",
+			wantRepo:     "standards",
+			wantRef:      "v1",
+			wantFullName: "acme/standards",
+			wantCacheKey: "acme/standards",
+		},
+		{
+			name:         "gitlab.com",
+			repo:         "gitlab:acme/standards",
+			wantProvider: ProviderGitLab,
+			wantOwner:    "acme",
+			wantRepo:     "standards",
+			wantFullName: "gitlab:acme/standards",
+			wantCacheKey: "gitlab_acme/standards",
+		},
+		{
+			name:         "gitlab nested groups on self-hosted",
+			repo:         "gitlab:gitlab.example.com/platform/ai/standards@v2",
+			wantProvider: ProviderGitLab,
+			wantHost:     "gitlab.example.com",
+			wantOwner:    "platform/ai",
+			wantRepo:     "standards",
+			wantRef:      "v2",
+			wantFullName: "gitlab:gitlab.example.com/platform/ai/standards",
+			wantCacheKey: "gitlab_gitlab.example.com_platform-ai/standards",
+		},
+		{
+			name:         "gitlab.com URL",
+			repo:         "https://gitlab.com/acme/sub/standards",
+			wantProvider: ProviderGitLab,
+			wantOwner:    "acme/sub",
+			wantRepo:     "standards",
+			wantFullName: "gitlab:acme/sub/standards",
+			wantCacheKey: "gitlab_acme-sub/standards",
+		},
+		{
+			name:         "gitea self-hosted",
+			repo:         "gitea:git.example.com/acme/standards",
+			wantProvider: ProviderGitea,
+			wantHost:     "git.example.com",
+			wantOwner:    "acme",
+			wantRepo:     "standards",
+			wantFullName: "gitea:git.example.com/acme/standards",
+			wantCacheKey: "gitea_git.example.com_acme/standards",
+		},
+		{
+			name:         "gitea default host",
+			repo:         "gitea:acme/standards",
+			wantProvider: ProviderGitea,
+			wantHost:     DefaultGiteaHost,
+			wantOwner:    "acme",
+			wantRepo:     "standards",
+			wantFullName: "gitea:gitea.com/acme/standards",
+			wantCacheKey: "gitea_gitea.com_acme/standards",
+		},
+		{
+			name:         "bitbucket https remote with ref",
+			repo:         "https://bitbucket.org/acme/standards.git@v1.2",
+			wantProvider: ProviderGit,
+			wantHost:     "bitbucket.org",
+			wantURL:      "https://bitbucket.org/acme/standards.git",
+			wantOwner:    "acme",
+			wantRepo:     "standards",
+			wantRef:      "v1.2",
+			wantFullName: "https://bitbucket.org/acme/standards.git",
+			wantCacheKey: "git_bitbucket.org_acme/standards",
+		},
+		{
+			name:         "scp-style ssh remote",
+			repo:         "git@git.example.com:team/standards.git",
+			wantProvider: ProviderGit,
+			wantHost:     "git.example.com",
+			wantURL:      "git@git.example.com:team/standards.git",
+			wantOwner:    "team",
+			wantRepo:     "standards",
+			wantFullName: "git@git.example.com:team/standards.git",
+			wantCacheKey: "git_git.example.com_team/standards",
+		},
+		{
+			name:         "github enterprise prefix",
+			repo:         "github:ghe.example.com/acme/standards@v1",
+			wantProvider: ProviderGitHub,
+			wantHost:     "ghe.example.com",
+			wantOwner:    "acme",
+			wantRepo:     "standards",
+			wantRef:      "v1",
+			wantFullName: "github:ghe.example.com/acme/standards",
+			wantCacheKey: "github_ghe.example.com_acme/standards",
+		},
+		{
+			name:    "URL on an unknown host",
+			repo:    "https://ghe.example.com/acme/standards",
+			wantErr: true,
+		},
+		{
+			name:    "bitbucket URL without .git",
+			repo:    "https://bitbucket.org/acme/standards",
+			wantErr: true,
+		},
+		{
+			name:         "explicit github.com host",
+			repo:         "github:github.com/acme/standards",
+			wantProvider: ProviderGitHub,
+			wantOwner:    "acme",
+			wantRepo:     "standards",
+			wantFullName: "acme/standards",
+			wantCacheKey: "acme/standards",
+		},
+		{
+			name:    "gitlab missing repo",
+			repo:    "gitlab:acme",
+			wantErr: true,
+		},
+		{
+			name:    "git remote with empty ref",
+			repo:    "https://git.example.com/acme/standards.git@",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseRepoSpec(tt.repo)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRepoSpec() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRepoSpec() unexpected error: %v", err)
+			}
+			if spec.Provider != tt.wantProvider {
+				t.Errorf("Provider = %q, want %q", spec.Provider, tt.wantProvider)
+			}
+			if spec.Host != tt.wantHost {
+				t.Errorf("Host = %q, want %q", spec.Host, tt.wantHost)
+			}
+			if spec.URL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", spec.URL, tt.wantURL)
+			}
+			if spec.Owner != tt.wantOwner || spec.Repo != tt.wantRepo {
+				t.Errorf("Owner/Repo = %q/%q, want %q/%q", spec.Owner, spec.Repo, tt.wantOwner, tt.wantRepo)
+			}
+			if spec.Ref != tt.wantRef {
+				t.Errorf("Ref = %q, want %q", spec.Ref, tt.wantRef)
+			}
+			if spec.FullName() != tt.wantFullName {
+				t.Errorf("FullName() = %q, want %q", spec.FullName(), tt.wantFullName)
+			}
+			owner, repo := spec.CacheKey()
+			if owner+"/"+repo != tt.wantCacheKey {
+				t.Errorf("CacheKey() = %q, want %q", owner+"/"+repo, tt.wantCacheKey)
+			}
+		})
+	}
+}
+
+func TestParseRepoSpec_Local(t *testing.T) {
+	tests := []struct {
+		repo     string
+		wantPath string
+		wantRepo string
+	}{
+		{"./standards", "./standards", "standards"},
+		{"../vendor/standards/", "../vendor/standards", "standards"},
+		{"/opt/standards", "/opt/standards", "standards"},
+		{"~/standards", "~/standards", "standards"},
+		{"file:///opt/standards", "/opt/standards", "standards"},
+		{"/opt/standards@v1", "/opt/standards@v1", "standards@v1"}, // No refs for local paths
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			spec, err := ParseRepoSpec(tt.repo)
+			if err != nil {
+				t.Fatalf("ParseRepoSpec() unexpected error: %v", err)
+			}
+			if spec.Provider != ProviderLocal {
+				t.Errorf("Provider = %q, want %q", spec.Provider, ProviderLocal)
+			}
+			if spec.Path != tt.wantPath || spec.FullName() != tt.wantPath {
+				t.Errorf("Path = %q, FullName() = %q, want %q", spec.Path, spec.FullName(), tt.wantPath)
+			}
+			if spec.Repo != tt.wantRepo {
+				t.Errorf("Repo = %q, want %q", spec.Repo, tt.wantRepo)
+			}
+			if spec.IsPinned() {
+				t.Errorf("IsPinned() = true, want false")
+			}
+		})
+	}
+
+	t.Run("cache keys differ for same-named directories", func(t *testing.T) {
+		a, _ := ParseRepoSpec("/a/standards")
+		b, _ := ParseRepoSpec("/b/standards")
+		aOwner, aRepo := a.CacheKey()
+		bOwner, bRepo := b.CacheKey()
+		if !strings.HasPrefix(aOwner, ProviderLocal+"_") || aRepo != "standards" {
+			t.Errorf("CacheKey() = %q/%q, want local_<hash>/standards", aOwner, aRepo)
+		}
+		if aOwner+aRepo == bOwner+bRepo {
+			t.Errorf("CacheKey() collided: %q/%q", aOwner, aRepo)
+		}
+	})
+}
+
+func TestCacheKey_ProvidersDontCollideWithGitHub(t *testing.T) {
+	pairs := [][2]string{
+		{"gitlab:acme/standards", "gitlab-acme/standards"},
+		{"github:ghe.example.com/acme/standards", "github-ghe.example.com-acme/standards"},
+		{"/opt/standards", "local/standards"},
+	}
+	for _, pair := range pairs {
+		a, err := ParseRepoSpec(pair[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseRepoSpec(pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		aOwner, aRepo := a.CacheKey()
+		bOwner, bRepo := b.CacheKey()
+		if strings.EqualFold(aOwner, bOwner) && strings.EqualFold(aRepo, bRepo) {
+			t.Errorf("%s and %s share cache key %s/%s", pair[0], pair[1], aOwner, aRepo)
+		}
+	}
+}
+
+func TestParseRepoSpec_Subdir(t *testing.T) {
+	tests := []struct {
+		repo       string
+		wantSubdir string
+		wantRef    string
+		wantName   string
+		wantKey    string // owner/repo from CacheKey
+	}{
+		{"acme/monorepo//platform/ai-standards", "platform/ai-standards", "", "acme/monorepo//platform/ai-standards", "acme/monorepo--platform-ai-standards"},
+		{"acme/monorepo//platform/ai-standards@v2", "platform/ai-standards", "v2", "acme/monorepo//platform/ai-standards", "acme/monorepo--platform-ai-standards"},
+		{"acme/monorepo@v2//standards/", "standards", "v2", "acme/monorepo//standards", "acme/monorepo--standards"},
+		{"https://github.com/acme/monorepo//standards", "standards", "", "acme/monorepo//standards", "acme/monorepo--standards"},
+		{"gitlab:platform/mono//ai", "ai", "", "gitlab:platform/mono//ai", "gitlab_platform/mono--ai"},
+		{"https://git.example.com/team/mono.git//ai@v1", "ai", "v1", "https://git.example.com/team/mono.git//ai", "git_git.example.com_team/mono--ai"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			spec, err := ParseRepoSpec(tt.repo)
+			if err != nil {
+				t.Fatalf("ParseRepoSpec() unexpected error: %v", err)
+			}
+			if spec.Subdir != tt.wantSubdir || spec.Ref != tt.wantRef {
+				t.Errorf("Subdir, Ref = %q, %q, want %q, %q", spec.Subdir, spec.Ref, tt.wantSubdir, tt.wantRef)
+			}
+			if spec.FullName() != tt.wantName {
+				t.Errorf("FullName() = %q, want %q", spec.FullName(), tt.wantName)
+			}
+			if owner, repo := spec.CacheKey(); owner+"/"+repo != tt.wantKey {
+				t.Errorf("CacheKey() = %q/%q, want %q", owner, repo, tt.wantKey)
+			}
+
+			// The canonical form parses back to the same source
+			again, err := ParseRepoSpec(spec.String())
+			if err != nil || again.FullName() != spec.FullName() || again.Ref != spec.Ref {
+				t.Errorf("ParseRepoSpec(%q) = %+v, %v; want a round trip", spec.String(), again, err)
+			}
+		})
+	}
+
+	t.Run("source paths", func(t *testing.T) {
+		spec, _ := ParseRepoSpec("acme/monorepo//platform/ai-standards")
+		if got := spec.SourcePath("rules/api.md"); got != "platform/ai-standards/rules/api.md" {
+			t.Errorf("SourcePath() = %q", got)
+		}
+		root, _ := ParseRepoSpec("acme/standards")
+		if got := root.SourcePath("CLAUDE.md"); got != "CLAUDE.md" {
+			t.Errorf("SourcePath() = %q, want CLAUDE.md", got)
+		}
+	})
+
+	t.Run("cache paths differ per subdirectory", func(t *testing.T) {
+		paths := NewPathsWithOverrides(t.TempDir(), t.TempDir())
+		aOwner, aRepo, _ := ParseRepo("acme/monorepo//team-a")
+		bOwner, bRepo, _ := ParseRepo("acme/monorepo//team-b")
+		if paths.TeamRulesDir(aOwner, aRepo) == paths.TeamRulesDir(bOwner, bRepo) {
+			t.Errorf("TeamRulesDir() collided: %s", paths.TeamRulesDir(aOwner, aRepo))
+		}
+	})
+
+	for _, repo := range []string{"acme/monorepo//", "acme/monorepo//../secrets", "acme/monorepo//a/./b", "acme/monorepo//a b"} {
+		t.Run("invalid "+repo, func(t *testing.T) {
+			if _, err := ParseRepoSpec(repo); err == nil {
+				t.Errorf("ParseRepoSpec(%q) expected error", repo)
+			}
+		})
+	}
+}
+
+func TestGitHubHost(t *testing.T) {
+	t.Run("defaults to github.com", func(t *testing.T) {
+		cfg := NewSimpleConfig("acme/standards")
+		if got := cfg.GitHubHost(); got != "" {
+			t.Errorf("GitHubHost() = %q, want empty", got)
+		}
+		if got := cfg.SourceGitHubHost(); got != "" {
+			t.Errorf("SourceGitHubHost() = %q, want empty", got)
+		}
+	})
+
+	t.Run("github.host applies to sources", func(t *testing.T) {
+		cfg := NewSimpleConfig("acme/standards")
+		cfg.GitHub.Host = "GHE.example.com"
+		if got := cfg.SourceGitHubHost(); got != "ghe.example.com" {
+			t.Errorf("SourceGitHubHost() = %q, want ghe.example.com", got)
+		}
+	})
+
+	t.Run("source.host overrides github.host", func(t *testing.T) {
+		cfg := &Config{
+			Source: Source{Multi: &SourceConfig{Default: "acme/standards", Host: "ghe.internal"}},
+			GitHub: GitHubConfig{Host: "ghe.example.com"},
+		}
+		if got := cfg.GitHubHost(); got != "ghe.example.com" {
+			t.Errorf("GitHubHost() = %q, want ghe.example.com", got)
+		}
+		if got := cfg.SourceGitHubHost(); got != "ghe.internal" {
+			t.Errorf("SourceGitHubHost() = %q, want ghe.internal", got)
+		}
+	})
+
+	t.Run("invalid host", func(t *testing.T) {
+		cfg := NewSimpleConfig("acme/standards")
+		cfg.GitHub.Host = "https://ghe.example.com"
+		if err := cfg.Validate(); err == nil {
+			t.Error("Validate() expected error for host with scheme")
+		}
+	})
+}
+
+func TestRepoSpecWebURL(t *testing.T) {
+	tests := []struct {
+		repo        string
+		defaultHost string
+		want        string
+	}{
+		{"acme/standards", "", "https://github.com/acme/standards"},
+		{"acme/standards", "ghe.example.com", "https://ghe.example.com/acme/standards"},
+		{"github:ghe.other.com/acme/standards", "ghe.example.com", "https://ghe.other.com/acme/standards"},
+		{"github:ghe.example.com/acme/standards/tree/main", "", "https://ghe.example.com/acme/standards"},
+		{"gitlab:acme/sub/standards", "", "https://gitlab.com/acme/sub/standards"},
+		{"git@git.example.com:acme/standards.git", "", "git@git.example.com:acme/standards.git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			spec, err := ParseRepoSpec(tt.repo)
+			if err != nil {
+				t.Fatalf("ParseRepoSpec() unexpected error: %v", err)
+			}
+			if got := spec.WebURL(tt.defaultHost); got != tt.want {
+				t.Errorf("WebURL(%q) = %q, want %q", tt.defaultHost, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrustWarning(t *testing.T) {
+	warning := TrustWarning("acme/standards", "https://ghe.example.com/acme/standards")
+	if !strings.Contains(warning, "https://ghe.example.com/acme/standards") {
+		t.Errorf("TrustWarning() should link to the source URL, got:\n%s", warning)
+	}
+	if strings.Contains(warning, "github.com") {
+		t.Errorf("TrustWarning() should not hard-code github.com, got:\n%s", warning)
+	}
+}
+
 func TestConfigValidation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -172,6 +674,38 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid sync concurrency",
+			config: Config{
+				Source: Source{Simple: "acme/standards"},
+				Sync:   SyncConfig{Concurrency: 8},
+			},
+			wantErr: false,
+		},
+		{
+			name: "zero sync concurrency uses the default",
+			config: Config{
+				Source: Source{Simple: "acme/standards"},
+				Sync:   SyncConfig{Concurrency: 0},
+			},
+			wantErr: false,
+		},
+		{
+			name: "sync concurrency too high",
+			config: Config{
+				Source: Source{Simple: "acme/standards"},
+				Sync:   SyncConfig{Concurrency: MaxSyncConcurrency + 1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative sync concurrency",
+			config: Config{
+				Source: Source{Simple: "acme/standards"},
+				Sync:   SyncConfig{Concurrency: -1},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -243,6 +777,146 @@ func TestLoadAndSave(t *testing.T) {
 	}
 }
 
+func TestLoadFrom_RelativeLocalSources(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config", "config.yaml")
+	written := "version: 1\nsource:\n  default: ./standards\n  rules:\n    security/: ../shared//rules\ntrusted:\n  - ./standards\ncache:\n  ttl: 24h\n"
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(written), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Run from elsewhere: the working directory must not matter
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() error: %v", err)
+	}
+
+	standards := filepath.Join(tempDir, "config", "standards")
+	if got := cfg.Source.DefaultRepo(); got != standards {
+		t.Errorf("DefaultRepo() = %q, want %q", got, standards)
+	}
+	if got, want := cfg.Source.RepoForRule("security/auth.md"), filepath.Join(tempDir, "shared")+"//rules"; got != want {
+		t.Errorf("RepoForRule() = %q, want %q", got, want)
+	}
+	if !cfg.IsTrustedSource(standards) {
+		t.Error("IsTrustedSource() = false, want the relative trusted entry to match")
+	}
+
+	// The cache key doesn't depend on how the path was written
+	spec, _ := cfg.DefaultRepoSpec()
+	abs, _ := ParseRepoSpec(standards)
+	owner, repo := spec.CacheKey()
+	if absOwner, absRepo := abs.CacheKey(); owner != absOwner || repo != absRepo {
+		t.Errorf("CacheKey() = %s/%s, want %s/%s", owner, repo, absOwner, absRepo)
+	}
+
+	// Saving keeps the paths as written
+	if err := SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("SaveTo() error: %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	for _, want := range []string{"default: ./standards", "security/: ../shared//rules", "- ./standards"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("saved config missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestResolveLocalPath(t *testing.T) {
+	tests := []struct {
+		repo string
+		want string
+	}{
+		{"./standards", "/etc/staghorn/standards"},
+		{"../standards//rules", "/etc/standards//rules"},
+		{"file://./standards", "/etc/staghorn/standards"},
+		{".", "/etc/staghorn"},
+		{"/opt/standards", "/opt/standards"},
+		{"file:///opt/standards", "file:///opt/standards"},
+		{"~/standards", "~/standards"},
+		{"acme/standards", "acme/standards"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			if got := ResolveLocalPath(tt.repo, "/etc/staghorn"); got != tt.want {
+				t.Errorf("ResolveLocalPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveGitHubURL(t *testing.T) {
+	tests := []struct {
+		repo string
+		host string
+		want string
+	}{
+		{"https://ghe.example.com/acme/standards", "ghe.example.com", "github:ghe.example.com/acme/standards"},
+		{"https://GHE.example.com/acme/mono//ai@v1", "ghe.example.com", "github:ghe.example.com/acme/mono//ai@v1"},
+		{"https://bitbucket.org/acme/standards", "ghe.example.com", "https://bitbucket.org/acme/standards"},
+		{"https://ghe.example.com/acme/standards.git", "ghe.example.com", "https://ghe.example.com/acme/standards.git"},
+		{"https://ghe.example.com/acme/standards", "", "https://ghe.example.com/acme/standards"},
+		{"https://github.com/acme/standards", "github.com", "https://github.com/acme/standards"},
+		{"acme/standards", "ghe.example.com", "acme/standards"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo+"_"+tt.host, func(t *testing.T) {
+			if got := ResolveGitHubURL(tt.repo, tt.host); got != tt.want {
+				t.Errorf("ResolveGitHubURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFrom_GitHubEnterpriseURLs(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	written := "version: 1\ngithub:\n  host: ghe.example.com\nsource:\n  default: https://ghe.example.com/acme/standards\n  skills:\n    review: https://bitbucket.org/acme/skills.git\ncache:\n  ttl: 24h\n"
+	if err := os.WriteFile(configPath, []byte(written), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() error: %v", err)
+	}
+	spec, err := cfg.DefaultRepoSpec()
+	if err != nil {
+		t.Fatalf("DefaultRepoSpec() error: %v", err)
+	}
+	if spec.Provider != ProviderGitHub || spec.Host != "ghe.example.com" {
+		t.Errorf("DefaultRepoSpec() = %s on %q, want github on ghe.example.com", spec.Provider, spec.Host)
+	}
+
+	// Saving keeps the URL as written
+	if err := SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("SaveTo() error: %v", err)
+	}
+	saved, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(saved), "default: https://ghe.example.com/acme/standards") {
+		t.Errorf("saved config = %q, want the URL as written", saved)
+	}
+
+	// Without the host configured, the URL doesn't say what serves it
+	unhosted := strings.Replace(written, "github:\n  host: ghe.example.com\n", "", 1)
+	if err := os.WriteFile(configPath, []byte(unhosted), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFrom(configPath); err == nil || !strings.Contains(err.Error(), "github:ghe.example.com/owner/repo") {
+		t.Errorf("LoadFrom() = %v, want unknown host error", err)
+	}
+}
+
 func TestLoadNotFound(t *testing.T) {
 	_, err := LoadFrom("/nonexistent/path/config.yaml")
 	if err == nil {
@@ -335,6 +1009,113 @@ func TestSourceConfig(t *testing.T) {
 			t.Errorf("AllRepos() = %d repos, want 3", len(repos))
 		}
 	})
+
+	t.Run("layers", func(t *testing.T) {
+		s := Source{
+			Multi: &SourceConfig{
+				Default: "acme/standards",
+				Layers:  []string{"acme/standards", "acme/platform-standards"},
+			},
+		}
+
+		bases := s.BaseRepos()
+		if len(bases) != 2 || bases[0] != "acme/standards" || bases[1] != "acme/platform-standards" {
+			t.Errorf("BaseRepos() = %v, want [acme/standards acme/platform-standards]", bases)
+		}
+		if s.RepoForBase() != "acme/platform-standards" {
+			t.Errorf("RepoForBase() = %q, want most specific layer", s.RepoForBase())
+		}
+		if repos := s.AllRepos(); len(repos) != 2 {
+			t.Errorf("AllRepos() = %v, want 2 repos", repos)
+		}
+		if err := s.Validate(); err != nil {
+			t.Errorf("Validate() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rules, evals, and templates", func(t *testing.T) {
+		s := Source{
+			Multi: &SourceConfig{
+				Default: "acme/standards",
+				Rules: map[string]string{
+					"security/":          "acme/security",
+					"security/legacy":    "acme/legacy",
+					"security/crypto.md": "acme/crypto",
+				},
+				Evals:     map[string]string{"security-baseline": "acme/security"},
+				Templates: map[string]string{"service": "acme/platform"},
+			},
+		}
+
+		rules := map[string]string{
+			"security/secrets.md":     "acme/security",
+			"security/crypto.md":      "acme/crypto",
+			"security/legacy/auth.md": "acme/legacy",
+			"securityish.md":          "acme/standards",
+			"api/rest.md":             "acme/standards",
+		}
+		for relPath, want := range rules {
+			if got := s.RepoForRule(relPath); got != want {
+				t.Errorf("RepoForRule(%q) = %q, want %q", relPath, got, want)
+			}
+		}
+		if got := s.RepoForEval("security-baseline"); got != "acme/security" {
+			t.Errorf("RepoForEval(security-baseline) = %q, want %q", got, "acme/security")
+		}
+		if got := s.RepoForEval("style"); got != "acme/standards" {
+			t.Errorf("RepoForEval(style) = %q, want default", got)
+		}
+		if got := s.RepoForTemplate("service"); got != "acme/platform" {
+			t.Errorf("RepoForTemplate(service) = %q, want %q", got, "acme/platform")
+		}
+		if repos := s.AllRepos(); len(repos) != 5 {
+			t.Errorf("AllRepos() = %v, want 5 repos", repos)
+		}
+
+		s.Multi.Evals["broken"] = "not-a-repo"
+		if err := s.Validate(); err == nil || !strings.Contains(err.Error(), `eval "broken"`) {
+			t.Errorf("Validate() = %v, want invalid eval source", err)
+		}
+	})
+
+	t.Run("repo at one ref", func(t *testing.T) {
+		s := Source{Multi: &SourceConfig{
+			Default: "acme/standards@v1",
+			Skills:  map[string]string{"review": "acme/standards@v1"},
+			Rules:   map[string]string{"security/": "acme/monorepo//security@v2", "api/": "acme/monorepo//api@v3"},
+		}}
+		if err := s.Validate(); err != nil {
+			t.Errorf("Validate() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("base repos without layers", func(t *testing.T) {
+		s := Source{Simple: "acme/standards"}
+		if bases := s.BaseRepos(); len(bases) != 1 || bases[0] != "acme/standards" {
+			t.Errorf("BaseRepos() = %v, want [acme/standards]", bases)
+		}
+	})
+
+	t.Run("invalid layers", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			multi *SourceConfig
+		}{
+			{"base and layers", &SourceConfig{Default: "acme/standards", Base: "acme/base", Layers: []string{"acme/a", "acme/b"}}},
+			{"duplicate layer", &SourceConfig{Default: "acme/standards", Layers: []string{"acme/a", "acme/a"}}},
+			{"invalid layer", &SourceConfig{Default: "acme/standards", Layers: []string{"not-a-repo"}}},
+			{"repo at two refs", &SourceConfig{Default: "acme/standards@v1", Skills: map[string]string{"review": "acme/standards@v2"}}},
+			{"repo pinned and unpinned", &SourceConfig{Default: "acme/standards", Commands: map[string]string{"deploy": "ACME/standards@v1"}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				s := Source{Multi: tt.multi}
+				if err := s.Validate(); err == nil {
+					t.Error("Validate() expected error")
+				}
+			})
+		}
+	})
 }
 
 func TestTrust(t *testing.T) {
@@ -374,6 +1155,42 @@ func TestTrust(t *testing.T) {
 			trusted: []string{"acme/standards"},
 			want:    true,
 		},
+		{
+			name:    "repo trust covers subdirectories",
+			repo:    "acme/monorepo//platform/ai-standards",
+			trusted: []string{"acme/monorepo"},
+			want:    true,
+		},
+		{
+			name:    "provider entries don't match a github.com owner named like a cache key",
+			repo:    "gitlab-acme/standards",
+			trusted: []string{"gitlab:acme/standards"},
+			want:    false,
+		},
+		{
+			name:    "provider entries match the same repo",
+			repo:    "gitlab:ACME/standards//ai",
+			trusted: []string{"https://gitlab.com/acme/standards"},
+			want:    true,
+		},
+		{
+			name:    "org-level trust is for github.com owners",
+			repo:    "gitlab:acme/standards",
+			trusted: []string{"acme"},
+			want:    false,
+		},
+		{
+			name:    "git remotes match across URL forms",
+			repo:    "git@git.example.com:team/standards.git",
+			trusted: []string{"https://git.example.com/team/standards.git"},
+			want:    true,
+		},
+		{
+			name:    "subdirectory trust is exact",
+			repo:    "acme/monorepo//experiments",
+			trusted: []string{"acme/monorepo//platform/ai-standards"},
+			want:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -389,7 +1206,7 @@ func TestTrust(t *testing.T) {
 func TestConfig_IsTrustedSource_DefaultSources(t *testing.T) {
 	// Config with empty trusted list should still trust default sources
 	cfg := &Config{
-		Trusted: []string{},
+		Trusted: []TrustedSource{},
 	}
 
 	tests := []struct {
@@ -423,3 +1240,118 @@ func TestConfig_IsTrustedSource_DefaultSources(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncConcurrency(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.SyncConcurrency(); got != DefaultSyncConcurrency {
+		t.Errorf("SyncConcurrency() = %d, want default %d", got, DefaultSyncConcurrency)
+	}
+
+	cfg.Sync.Concurrency = 2
+	if got := cfg.SyncConcurrency(); got != 2 {
+		t.Errorf("SyncConcurrency() = %d, want 2", got)
+	}
+}
+
+func TestTrustedSources(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	content := `version: 1
+source: acme-corp/standards
+trusted:
+  - acme-corp
+  - repo: acme-corp/experimental
+    skills: review
+  - repo: widgets
+    skills: allow
+  - repo: community/skills
+    skills: deny
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() error: %v", err)
+	}
+	if len(cfg.Trusted) != 4 {
+		t.Fatalf("len(Trusted) = %d, want 4", len(cfg.Trusted))
+	}
+	if !cfg.IsTrustedSource("acme-corp/anything") {
+		t.Error("plain org entry should still trust its repos")
+	}
+
+	policies := map[string]SkillPolicy{
+		"acme-corp/standards":    SkillPolicyReview,
+		"acme-corp/experimental": SkillPolicyReview,
+		"widgets/tools":          SkillPolicyAllow,
+		"community/skills":       SkillPolicyDeny,
+		"random/repo":            SkillPolicyReview,
+	}
+	for repo, want := range policies {
+		if got := cfg.SkillPolicy(repo); got != want {
+			t.Errorf("SkillPolicy(%q) = %q, want %q", repo, got, want)
+		}
+	}
+
+	// Repo entries win over their org, whatever the order
+	trusted := []TrustedSource{{Repo: "acme", Skills: SkillPolicyAllow}, {Repo: "acme/app", Skills: SkillPolicyDeny}}
+	if got := SkillPolicyFor("acme/app", trusted); got != SkillPolicyDeny {
+		t.Errorf("SkillPolicyFor(acme/app) = %q, want deny", got)
+	}
+
+	// A github.com owner named like another provider's cache key gets nothing
+	trusted = []TrustedSource{{Repo: "gitlab:acme/standards", Skills: SkillPolicyAllow}}
+	if got := SkillPolicyFor("gitlab-acme/standards", trusted); got != SkillPolicyReview {
+		t.Errorf("SkillPolicyFor(gitlab-acme/standards) = %q, want review", got)
+	}
+
+	// Entries without a policy save back as plain strings
+	if err := SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("SaveTo() error: %v", err)
+	}
+	saved, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(saved), "- acme-corp\n") || !strings.Contains(string(saved), "skills: deny") {
+		t.Errorf("unexpected saved trusted list:\n%s", saved)
+	}
+}
+
+func TestTrustedSources_InvalidPolicy(t *testing.T) {
+	cfg := NewSimpleConfig("acme/standards")
+	cfg.Trusted = []TrustedSource{{Repo: "acme", Skills: "sometimes"}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid skills policy") {
+		t.Errorf("Validate() error = %v, want invalid skills policy", err)
+	}
+}
+
+func TestTrustedSources_Keys(t *testing.T) {
+	key := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIIp1bC51xyZ0uPsKxvCtZlyBpnhEnBfqii49oet/40sY release@acme"
+	cfg := NewSimpleConfig("acme/standards")
+	cfg.Trusted = []TrustedSource{
+		{Repo: "acme", Skills: SkillPolicyAllow},
+		{Repo: "acme/standards", Keys: []string{key}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+
+	if got := cfg.SigningKeys("acme/standards"); len(got) != 1 || got[0] != key {
+		t.Errorf("SigningKeys(acme/standards) = %v, want the pinned key", got)
+	}
+	if got := cfg.SigningKeys("acme/other"); got != nil {
+		t.Errorf("SigningKeys(acme/other) = %v, want none", got)
+	}
+	// A keys-only entry doesn't override the org's skills policy
+	if got := cfg.SkillPolicy("acme/standards"); got != SkillPolicyAllow {
+		t.Errorf("SkillPolicy(acme/standards) = %q, want allow", got)
+	}
+
+	cfg.Trusted = []TrustedSource{{Repo: "acme", Keys: []string{"not-a-key"}}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "unrecognized key") {
+		t.Errorf("Validate() error = %v, want unrecognized key", err)
+	}
+}
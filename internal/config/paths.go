@@ -18,6 +18,8 @@ type Paths struct {
 	PersonalEvals     string // ~/.config/staghorn/evals
 	PersonalRules     string // ~/.config/staghorn/rules
 	PersonalSkills    string // ~/.config/staghorn/skills
+	LockFile          string // ~/.config/staghorn/staghorn.lock
+	ClaudeDir         string // ~/.claude (empty means the current user's home)
 }
 
 // NewPaths creates Paths using ~/.config and ~/.cache directories.
@@ -38,6 +40,8 @@ func NewPaths() *Paths {
 		PersonalEvals:     filepath.Join(configDir, "evals"),
 		PersonalRules:     filepath.Join(configDir, "rules"),
 		PersonalSkills:    filepath.Join(configDir, "skills"),
+		LockFile:          filepath.Join(configDir, "staghorn.lock"),
+		ClaudeDir:         filepath.Join(home, ".claude"),
 	}
 }
 
@@ -53,6 +57,7 @@ func NewPathsWithOverrides(configDir, cacheDir string) *Paths {
 		PersonalEvals:     filepath.Join(configDir, "evals"),
 		PersonalRules:     filepath.Join(configDir, "rules"),
 		PersonalSkills:    filepath.Join(configDir, "skills"),
+		LockFile:          filepath.Join(configDir, "staghorn.lock"),
 	}
 }
 
@@ -66,6 +71,51 @@ func (p *Paths) CacheMetadataFile(owner, repo string) string {
 	return filepath.Join(p.CacheDir, fmt.Sprintf("%s-%s.meta.json", owner, repo))
 }
 
+// BlobDir returns the directory holding cached file blobs.
+func (p *Paths) BlobDir() string {
+	return filepath.Join(p.CacheDir, "blobs")
+}
+
+// BlobFile returns the path for a cached file blob, keyed by its git blob SHA.
+func (p *Paths) BlobFile(sha string) string {
+	return filepath.Join(p.BlobDir(), sha)
+}
+
+// GitCacheDir returns the directory holding bare clones of plain git sources.
+func (p *Paths) GitCacheDir() string {
+	return filepath.Join(p.CacheDir, "git")
+}
+
+// RunLockFile returns the advisory lock that keeps staghorn processes from
+// writing the config and cache directories at the same time.
+func (p *Paths) RunLockFile() string {
+	return filepath.Join(p.CacheDir, "run.lock")
+}
+
+// SyncMarkerFile returns the file present while a sync is in progress.
+// Finding it at startup means the previous sync was interrupted.
+func (p *Paths) SyncMarkerFile() string {
+	return filepath.Join(p.CacheDir, "sync-in-progress")
+}
+
+// PendingSkillsFile returns the list of skill changes held for 'staghorn approve'.
+func (p *Paths) PendingSkillsFile() string {
+	return filepath.Join(p.CacheDir, "pending-skills.json")
+}
+
+// GenerationsDir returns the directory holding snapshots of files applied to ~/.claude.
+// It lives beside the config because, unlike the cache, it can't be re-fetched.
+func (p *Paths) GenerationsDir() string {
+	return filepath.Join(p.ConfigDir, "generations")
+}
+
+// DriftDir returns the directory recording what staghorn last wrote to each
+// generated file, used to detect hand edits. Like generations, it lives beside
+// the config: clearing the cache must not make hand edits look like staghorn's.
+func (p *Paths) DriftDir() string {
+	return filepath.Join(p.ConfigDir, "drift")
+}
+
 // TeamCommandsDir returns the path for cached team commands.
 func (p *Paths) TeamCommandsDir(owner, repo string) string {
 	return filepath.Join(p.CacheDir, fmt.Sprintf("%s-%s-commands", owner, repo))
@@ -111,31 +161,36 @@ func (p *Paths) OptimizedMetaFile(owner, repo string) string {
 	return filepath.Join(p.OptimizedDir(), fmt.Sprintf("%s-%s.meta.json", owner, repo))
 }
 
-// ClaudeCommandsDir returns the path for Claude Code custom commands.
-func (p *Paths) ClaudeCommandsDir() string {
+// claudeDir returns the Claude Code user directory, defaulting to ~/.claude.
+func (p *Paths) claudeDir() string {
+	if p.ClaudeDir != "" {
+		return p.ClaudeDir
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		home = os.Getenv("HOME")
 	}
-	return filepath.Join(home, ".claude", "commands")
+	return filepath.Join(home, ".claude")
+}
+
+// ClaudeMD returns the path of the merged global config Claude Code reads.
+func (p *Paths) ClaudeMD() string {
+	return filepath.Join(p.claudeDir(), "CLAUDE.md")
+}
+
+// ClaudeCommandsDir returns the path for Claude Code custom commands.
+func (p *Paths) ClaudeCommandsDir() string {
+	return filepath.Join(p.claudeDir(), "commands")
 }
 
 // ClaudeRulesDir returns the path for Claude Code user-level rules.
 func (p *Paths) ClaudeRulesDir() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		home = os.Getenv("HOME")
-	}
-	return filepath.Join(home, ".claude", "rules")
+	return filepath.Join(p.claudeDir(), "rules")
 }
 
 // ClaudeSkillsDir returns the path for Claude Code user-level skills.
 func (p *Paths) ClaudeSkillsDir() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		home = os.Getenv("HOME")
-	}
-	return filepath.Join(home, ".claude", "skills")
+	return filepath.Join(p.claudeDir(), "skills")
 }
 
 // ProjectClaudeCommandsDir returns the path for project-level Claude Code commands.
@@ -154,6 +209,7 @@ type ProjectPaths struct {
 	Root         string // Project root directory
 	StaghornDir  string // .staghorn/
 	SourceMD     string // .staghorn/project.md (source of truth)
+	TemplateMD   string // .staghorn/template.md (team template the project was created from)
 	OutputMD     string // ./CLAUDE.md (generated output)
 	CommandsDir  string // .staghorn/commands/
 	LanguagesDir string // .staghorn/languages/
@@ -170,6 +226,7 @@ func NewProjectPaths(projectRoot string) *ProjectPaths {
 		Root:         projectRoot,
 		StaghornDir:  staghornDir,
 		SourceMD:     filepath.Join(staghornDir, "project.md"),
+		TemplateMD:   filepath.Join(staghornDir, "template.md"),
 		OutputMD:     filepath.Join(projectRoot, "CLAUDE.md"),
 		CommandsDir:  filepath.Join(staghornDir, "commands"),
 		LanguagesDir: filepath.Join(staghornDir, "languages"),
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"github.com/HartBrook/staghorn/internal/errors"
+	"github.com/HartBrook/staghorn/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfig is the optional per-project config in .staghorn/config.yaml.
+// It is committed with the project, so it holds nothing user-specific.
+type ProjectConfig struct {
+	// Template is the team template the project was created from. Its content
+	// is kept in .staghorn/template.md and merged beneath project.md.
+	Template string `yaml:"template,omitempty"`
+
+	// TemplateHash is the hex sha256 of the template content as last copied,
+	// so upgrades can tell whether template.md was edited by hand.
+	TemplateHash string `yaml:"template_hash,omitempty"`
+}
+
+// SetTemplate records the template the project uses and the content copied from it.
+func (c *ProjectConfig) SetTemplate(name, content string) {
+	c.Template = name
+	c.TemplateHash = contentHash(content)
+}
+
+// IsTemplateEdited reports whether content differs from the template as last
+// copied. Projects that never recorded a hash are treated as unedited.
+func (c *ProjectConfig) IsTemplateEdited(content string) bool {
+	return c.TemplateHash != "" && c.TemplateHash != contentHash(content)
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadProjectConfig reads a project config. A missing file loads empty.
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ProjectConfig{}, nil
+		}
+		return nil, errors.Wrap(errors.ErrConfigInvalid, "failed to read project config", "", err)
+	}
+
+	var cfg ProjectConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.Wrap(errors.ErrConfigInvalid, "failed to parse project config YAML", "Check "+path+" syntax", err)
+	}
+	return &cfg, nil
+}
+
+// SaveProjectConfig writes a project config.
+func SaveProjectConfig(cfg *ProjectConfig, path string) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return errors.Wrap(errors.ErrConfigInvalid, "failed to marshal project config", "", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(errors.ErrConfigInvalid, "failed to create .staghorn directory", "", err)
+	}
+	return fsutil.WriteFile(path, data, 0644)
+}
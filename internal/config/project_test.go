@@ -0,0 +1,45 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectConfig_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".staghorn", "config.yaml")
+
+	cfg, err := LoadProjectConfig(path)
+	if err != nil {
+		t.Fatalf("missing config should load empty: %v", err)
+	}
+	if cfg.Template != "" {
+		t.Errorf("Template = %q, want empty", cfg.Template)
+	}
+
+	cfg.SetTemplate("backend-service", "# Backend\n")
+	if err := SaveProjectConfig(cfg, path); err != nil {
+		t.Fatalf("SaveProjectConfig() error = %v", err)
+	}
+	loaded, err := LoadProjectConfig(path)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error = %v", err)
+	}
+	if *loaded != *cfg {
+		t.Errorf("loaded %+v, want %+v", loaded, cfg)
+	}
+}
+
+func TestProjectConfig_IsTemplateEdited(t *testing.T) {
+	cfg := &ProjectConfig{}
+	if cfg.IsTemplateEdited("anything") {
+		t.Error("a project without a recorded hash shouldn't count as edited")
+	}
+
+	cfg.SetTemplate("backend-service", "# Backend\n")
+	if cfg.IsTemplateEdited("# Backend\n") {
+		t.Error("the copied content shouldn't count as edited")
+	}
+	if !cfg.IsTemplateEdited("# Backend\n\nMore.\n") {
+		t.Error("changed content should count as edited")
+	}
+}
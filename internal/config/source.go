@@ -2,7 +2,11 @@
 package config
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
+	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -12,9 +16,13 @@ import (
 // repoPattern matches owner/repo format.
 var repoPattern = regexp.MustCompile(`^([a-zA-Z0-9_.-]+)/([a-zA-Z0-9_.-]+)$`)
 
+// refPattern matches git refs: tags, branches (including slashes), and commit SHAs.
+var refPattern = regexp.MustCompile(`^[a-zA-Z0-9_./-]+$`)
+
 // SourceConfig supports both simple string and multi-source configurations.
-// Simple: source: "owner/repo"
+// Simple: source: "owner/repo" or "owner/repo@v1.4.0"
 // Multi:  source: { default: "owner/repo", base: "other/repo", languages: {...} }
+// Layered: source: { default: "owner/repo", layers: ["org/standards", "team/standards"] }
 type SourceConfig struct {
 	// Default is the fallback source for all items not explicitly configured.
 	Default string `yaml:"default,omitempty"`
@@ -22,8 +30,13 @@ type SourceConfig struct {
 	// Base overrides the source for the main CLAUDE.md file.
 	Base string `yaml:"base,omitempty"`
 
+	// Layers stacks the main CLAUDE.md from several repos, broadest first.
+	// Each layer is merged over the previous one; personal config goes on top.
+	// Example: ["acme/standards", "acme/platform-standards"]
+	Layers []string `yaml:"layers,omitempty"`
+
 	// Languages maps language IDs to their source repos.
-	// Example: { "python": "acme/python-standards" }
+	// Example: { "python": "acme/python-standards@v2" }
 	Languages map[string]string `yaml:"languages,omitempty"`
 
 	// Commands maps command names to their source repos.
@@ -33,6 +46,23 @@ type SourceConfig struct {
 	// Skills maps skill names to their source repos.
 	// Example: { "react": "vercel-labs/agent-skills/skills/react" }
 	Skills map[string]string `yaml:"skills,omitempty"`
+
+	// Rules maps rule paths, relative to rules/, to their source repos.
+	// A directory key covers every rule under it; the longest match wins.
+	// Example: { "security/": "acme/security-standards", "api.md": "acme/api" }
+	Rules map[string]string `yaml:"rules,omitempty"`
+
+	// Evals maps eval names to their source repos.
+	// Example: { "security-baseline": "acme/security-standards" }
+	Evals map[string]string `yaml:"evals,omitempty"`
+
+	// Templates maps project template names to their source repos.
+	// Example: { "service": "acme/platform-templates" }
+	Templates map[string]string `yaml:"templates,omitempty"`
+
+	// Host is the GitHub Enterprise host for these sources, overriding github.host.
+	// Individual sources can still name a host: "github:ghe.example.com/owner/repo".
+	Host string `yaml:"host,omitempty"`
 }
 
 // Source wraps the flexible source configuration.
@@ -43,6 +73,10 @@ type Source struct {
 
 	// Multi holds the structured config when source is an object.
 	Multi *SourceConfig
+
+	// written maps local paths resolved by resolveLocalPaths back to how
+	// they appear in the config file, so saving keeps them relative.
+	written map[string]string
 }
 
 // UnmarshalYAML implements custom unmarshaling to handle both string and object formats.
@@ -64,12 +98,99 @@ func (s *Source) UnmarshalYAML(node *yaml.Node) error {
 
 // MarshalYAML implements custom marshaling to output the appropriate format.
 func (s Source) MarshalYAML() (interface{}, error) {
+	if len(s.written) > 0 {
+		s.mapRepos(func(repo string) string {
+			if orig, ok := s.written[repo]; ok {
+				return orig
+			}
+			return repo
+		})
+	}
 	if s.Simple != "" {
 		return s.Simple, nil
 	}
 	return s.Multi, nil
 }
 
+// mapRepos replaces every repo string in the source with fn's result. Multi
+// is replaced rather than modified, so copies of s are left alone.
+func (s *Source) mapRepos(fn func(string) string) {
+	mapRepo := func(repo string) string {
+		if repo == "" {
+			return ""
+		}
+		return fn(repo)
+	}
+	mapValues := func(m map[string]string) map[string]string {
+		if m == nil {
+			return nil
+		}
+		mapped := make(map[string]string, len(m))
+		for k, v := range m {
+			mapped[k] = mapRepo(v)
+		}
+		return mapped
+	}
+
+	s.Simple = mapRepo(s.Simple)
+	if s.Multi == nil {
+		return
+	}
+	m := *s.Multi
+	m.Default = mapRepo(m.Default)
+	m.Base = mapRepo(m.Base)
+	if m.Layers != nil {
+		m.Layers = make([]string, len(s.Multi.Layers))
+		for i, repo := range s.Multi.Layers {
+			m.Layers[i] = mapRepo(repo)
+		}
+	}
+	m.Languages = mapValues(m.Languages)
+	m.Commands = mapValues(m.Commands)
+	m.Skills = mapValues(m.Skills)
+	m.Rules = mapValues(m.Rules)
+	m.Evals = mapValues(m.Evals)
+	m.Templates = mapValues(m.Templates)
+	s.Multi = &m
+}
+
+// resolveLocalPaths makes relative local sources absolute, resolving them
+// against dir (the config file's directory) rather than wherever stag runs.
+func (s *Source) resolveLocalPaths(dir string) {
+	s.resolveRepos(func(repo string) string {
+		return ResolveLocalPath(repo, dir)
+	})
+}
+
+// resolveGitHubURLs rewrites URLs on the given GitHub Enterprise hosts to
+// the "github:host/owner/repo" form.
+func (s *Source) resolveGitHubURLs(hosts ...string) {
+	s.resolveRepos(func(repo string) string {
+		for _, host := range hosts {
+			repo = ResolveGitHubURL(repo, host)
+		}
+		return repo
+	})
+}
+
+// resolveRepos replaces every repo string with fn's result, remembering
+// how changed ones were written so saving restores them.
+func (s *Source) resolveRepos(fn func(string) string) {
+	s.mapRepos(func(repo string) string {
+		resolved := fn(repo)
+		if resolved != repo {
+			if orig, ok := s.written[repo]; ok {
+				repo = orig
+			}
+			if s.written == nil {
+				s.written = make(map[string]string)
+			}
+			s.written[resolved] = repo
+		}
+		return resolved
+	})
+}
+
 // IsMultiSource returns true if this is a multi-source configuration.
 func (s *Source) IsMultiSource() bool {
 	return s.Multi != nil
@@ -87,13 +208,26 @@ func (s *Source) DefaultRepo() string {
 }
 
 // RepoForBase returns the repository to use for the base CLAUDE.md.
+// When layers are configured, this is the most specific (last) layer.
 func (s *Source) RepoForBase() string {
+	if s.Multi != nil && len(s.Multi.Layers) > 0 {
+		return s.Multi.Layers[len(s.Multi.Layers)-1]
+	}
 	if s.Multi != nil && s.Multi.Base != "" {
 		return s.Multi.Base
 	}
 	return s.DefaultRepo()
 }
 
+// BaseRepos returns the repositories whose CLAUDE.md files are merged to form
+// the team config, broadest first. Without layers this is just RepoForBase.
+func (s *Source) BaseRepos() []string {
+	if s.Multi != nil && len(s.Multi.Layers) > 0 {
+		return s.Multi.Layers
+	}
+	return []string{s.RepoForBase()}
+}
+
 // RepoForLanguage returns the repository to use for a specific language config.
 func (s *Source) RepoForLanguage(lang string) string {
 	if s.Multi != nil && s.Multi.Languages != nil {
@@ -124,6 +258,48 @@ func (s *Source) RepoForSkill(skill string) string {
 	return s.DefaultRepo()
 }
 
+// RepoForRule returns the repository to use for a rule, given its path
+// relative to rules/ (e.g., "security/secrets.md"). An exact entry wins over
+// a directory entry, and a deeper directory over a shallower one.
+func (s *Source) RepoForRule(relPath string) string {
+	if s.Multi != nil && s.Multi.Rules != nil {
+		if repo, ok := s.Multi.Rules[relPath]; ok {
+			return repo
+		}
+		best, bestLen := "", -1
+		for key, repo := range s.Multi.Rules {
+			dir := strings.TrimSuffix(key, "/")
+			if strings.HasPrefix(relPath, dir+"/") && len(dir) > bestLen {
+				best, bestLen = repo, len(dir)
+			}
+		}
+		if bestLen >= 0 {
+			return best
+		}
+	}
+	return s.DefaultRepo()
+}
+
+// RepoForEval returns the repository to use for a specific eval.
+func (s *Source) RepoForEval(name string) string {
+	if s.Multi != nil && s.Multi.Evals != nil {
+		if repo, ok := s.Multi.Evals[name]; ok {
+			return repo
+		}
+	}
+	return s.DefaultRepo()
+}
+
+// RepoForTemplate returns the repository to use for a specific project template.
+func (s *Source) RepoForTemplate(name string) string {
+	if s.Multi != nil && s.Multi.Templates != nil {
+		if repo, ok := s.Multi.Templates[name]; ok {
+			return repo
+		}
+	}
+	return s.DefaultRepo()
+}
+
 // AllRepos returns all unique repositories referenced by this source config.
 // Useful for syncing all sources at once.
 func (s *Source) AllRepos() []string {
@@ -141,6 +317,9 @@ func (s *Source) AllRepos() []string {
 
 	if s.Multi != nil {
 		addRepo(s.Multi.Base)
+		for _, repo := range s.Multi.Layers {
+			addRepo(repo)
+		}
 		for _, repo := range s.Multi.Languages {
 			addRepo(repo)
 		}
@@ -150,12 +329,521 @@ func (s *Source) AllRepos() []string {
 		for _, repo := range s.Multi.Skills {
 			addRepo(repo)
 		}
+		for _, repo := range s.Multi.Rules {
+			addRepo(repo)
+		}
+		for _, repo := range s.Multi.Evals {
+			addRepo(repo)
+		}
+		for _, repo := range s.Multi.Templates {
+			addRepo(repo)
+		}
 	}
 
 	return repos
 }
 
+// Source providers a repo can be fetched from.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+	ProviderGitea  = "gitea"
+	ProviderGit    = "git"   // Plain git remote, fetched with the git CLI
+	ProviderLocal  = "local" // Directory on disk, read directly
+)
+
+// Default hosts for hosted providers when a source doesn't name one.
+const (
+	DefaultGitHubHost = "github.com"
+	DefaultGitLabHost = "gitlab.com"
+	DefaultGiteaHost  = "gitea.com"
+)
+
+// segmentPattern matches a single owner, group, or repo path segment.
+var segmentPattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// hostPattern matches a hostname with an optional port.
+var hostPattern = regexp.MustCompile(`^[a-zA-Z0-9.-]+(:[0-9]+)?$`)
+
+// cacheKeyPattern matches characters that must be replaced in cache keys.
+var cacheKeyPattern = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// RepoSpec is a parsed repository reference with an optional pinned ref.
+type RepoSpec struct {
+	Provider string // ProviderGitHub, ProviderGitLab, ProviderGitea, ProviderGit, or ProviderLocal
+	Host     string // Host for hosted providers; empty means the provider's public host
+	URL      string // Remote URL (git provider only)
+	Path     string // Directory as written in the config (local provider only)
+
+	Owner  string // Owner, or group path for GitLab (may contain "/")
+	Repo   string
+	Ref    string // Tag, branch, or commit SHA; empty means the default branch
+	Subdir string // Directory within the repo that holds the source layout; empty means the root
+}
+
+// FullName returns the source location without any ref: "owner/repo" for
+// github.com, "gitlab:group/repo" style for other providers, the git URL, or
+// the local path, followed by "//subdir" for sources rooted in a subdirectory.
+func (r *RepoSpec) FullName() string {
+	if r.Subdir != "" {
+		return r.repoName() + "//" + r.Subdir
+	}
+	return r.repoName()
+}
+
+// repoName returns the location of the repo hosting the source.
+func (r *RepoSpec) repoName() string {
+	switch {
+	case r.Provider == ProviderGit:
+		return r.URL
+	case r.Provider == ProviderLocal:
+		return r.Path
+	case r.Provider == ProviderGitHub && r.Host == "":
+		return r.Owner + "/" + r.Repo
+	case r.Host != "":
+		return r.Provider + ":" + r.Host + "/" + r.Owner + "/" + r.Repo
+	default:
+		return r.Provider + ":" + r.Owner + "/" + r.Repo
+	}
+}
+
+// SourcePath returns the repo path of a file in the source layout, such as
+// "CLAUDE.md" or "rules/api.md", accounting for Subdir.
+func (r *RepoSpec) SourcePath(p string) string {
+	if r.Subdir == "" {
+		return p
+	}
+	return path.Join(r.Subdir, p)
+}
+
+// String returns the canonical "location[@ref]" form.
+func (r *RepoSpec) String() string {
+	if r.Ref != "" {
+		return r.FullName() + "@" + r.Ref
+	}
+	return r.FullName()
+}
+
+// WebURL returns the source's browsable URL. defaultGitHubHost is used for
+// GitHub sources that don't name a host ("" means github.com).
+func (r *RepoSpec) WebURL(defaultGitHubHost string) string {
+	host := r.Host
+	switch r.Provider {
+	case ProviderGit:
+		return r.URL
+	case ProviderLocal:
+		return r.Path
+	case ProviderGitLab:
+		if host == "" {
+			host = DefaultGitLabHost
+		}
+	case ProviderGitHub:
+		if host == "" {
+			host = normalizeGitHubHost(defaultGitHubHost)
+		}
+		if host == "" {
+			host = DefaultGitHubHost
+		}
+	}
+	return "https://" + host + "/" + r.Owner + "/" + r.Repo
+}
+
+// IsPinned returns true if the spec pins a specific ref.
+func (r *RepoSpec) IsPinned() bool {
+	return r.Ref != ""
+}
+
+// CacheKey returns the owner and repo used to name local cache files.
+// github.com sources use their plain owner and repo; other providers are
+// namespaced by provider and host so sources never share a cache entry.
+// A subdirectory is appended to the repo ("monorepo--platform-ai-standards"),
+// so sources rooted in different directories of one repo stay apart.
+func (r *RepoSpec) CacheKey() (owner, repo string) {
+	owner, repo = r.repoCacheKey()
+	if r.Subdir != "" {
+		repo += "--" + cacheKeyPattern.ReplaceAllString(r.Subdir, "-")
+	}
+	return owner, repo
+}
+
+// repoCacheKey returns the cache key of the repo hosting the source.
+// Other providers join their parts with "_", which GitHub logins can't
+// contain, so their keys never match a github.com owner.
+func (r *RepoSpec) repoCacheKey() (owner, repo string) {
+	if r.Provider == ProviderGitHub && r.Host == "" {
+		return r.Owner, r.Repo
+	}
+
+	parts := []string{r.Provider}
+	if r.Provider == ProviderLocal {
+		// Directories can share a name, so key by a hash of the path too
+		sum := sha1.Sum([]byte(r.Path))
+		parts = append(parts, hex.EncodeToString(sum[:])[:8])
+	}
+	if r.Host != "" {
+		parts = append(parts, r.Host)
+	}
+	if r.Owner != "" {
+		parts = append(parts, r.Owner)
+	}
+	owner = cacheKeyPattern.ReplaceAllString(strings.Join(parts, "_"), "-")
+	return owner, cacheKeyPattern.ReplaceAllString(r.Repo, "-")
+}
+
+// sameRepo reports whether r and o are hosted by the same repository,
+// ignoring refs and subdirectories.
+func (r *RepoSpec) sameRepo(o *RepoSpec) bool {
+	if r.Provider != o.Provider {
+		return false
+	}
+	if r.Provider == ProviderLocal {
+		return path.Clean(r.Path) == path.Clean(o.Path)
+	}
+	return strings.EqualFold(r.Host, o.Host) && strings.EqualFold(r.Owner, o.Owner) && strings.EqualFold(r.Repo, o.Repo)
+}
+
+// ParseRepoSpec parses a repository string with an optional "@ref" suffix.
+// Accepts everything ParseRepo does, plus:
+//   - "owner/repo@v1.4.0" (tag)
+//   - "owner/repo@release" (branch)
+//   - "owner/repo@3f2a9c1" (commit SHA)
+//   - "owner/repo/path@v1" (skill sources with a path)
+//
+// Sources on other providers are selected by prefix or URL:
+//   - "gitlab:group/repo", "gitlab:gitlab.example.com/group/sub/repo"
+//   - "gitea:gitea.example.com/owner/repo"
+//   - "github:owner/repo"
+//   - "https://git.example.com/team/standards.git@v1" (plain git remote)
+//   - "git@git.example.com:team/standards.git"
+//
+// Local directories are read from disk and can't be pinned:
+//   - "./standards", "../standards", "/opt/standards", "~/standards"
+//   - "file:///opt/standards"
+//
+// Any of these can be rooted in a subdirectory of the repo, for standards
+// hosted in a monorepo: "owner/repo//platform/ai-standards@v1".
+func ParseRepoSpec(repoStr string) (*RepoSpec, error) {
+	repoStr, subdir, err := splitSubdir(repoStr)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := parseRepoLocation(repoStr)
+	if err != nil {
+		return nil, err
+	}
+	spec.Subdir = subdir
+	return spec, nil
+}
+
+// splitSubdir separates a "//subdir" suffix from a repository string,
+// keeping any "@ref" (written before or after the subdirectory) on the repo.
+// The "//" of a URL scheme is not a separator.
+func splitSubdir(repoStr string) (repo, subdir string, err error) {
+	start := 0
+	if i := strings.Index(repoStr, "://"); i != -1 {
+		start = i + len("://")
+	}
+	idx := strings.Index(repoStr[start:], "//")
+	if idx == -1 {
+		return repoStr, "", nil
+	}
+	idx += start
+
+	repo = repoStr[:idx]
+	subdir, ref, hasRef := strings.Cut(repoStr[idx+2:], "@")
+	if hasRef {
+		repo += "@" + ref
+	}
+
+	subdir = strings.TrimSuffix(subdir, "/")
+	if subdir == "" {
+		return "", "", fmt.Errorf("invalid repository format: %s (empty subdirectory after //)", repoStr)
+	}
+	for _, seg := range strings.Split(subdir, "/") {
+		if seg == "." || seg == ".." || !segmentPattern.MatchString(seg) {
+			return "", "", fmt.Errorf("invalid subdirectory %q in %s", subdir, repoStr)
+		}
+	}
+	return repo, subdir, nil
+}
+
+// parseRepoLocation parses a repository string without a subdirectory.
+func parseRepoLocation(repoStr string) (*RepoSpec, error) {
+	if isLocalPath(repoStr) {
+		return parseLocalPath(repoStr)
+	}
+
+	if isGitRemote(repoStr) {
+		return parseGitRemote(repoStr)
+	}
+
+	if provider, rest, ok := splitProviderPrefix(repoStr); ok {
+		return parseHostedSpec(provider, rest)
+	}
+
+	// Full gitlab.com URLs map to the GitLab provider. URLs on other hosts
+	// (besides github.com) don't say what serves them, so they must be git
+	// remotes, use a provider prefix, or be on the configured GitHub host
+	if rest, ok := cutScheme(repoStr); ok {
+		if path, ok := strings.CutPrefix(rest, DefaultGitLabHost+"/"); ok {
+			return parseHostedSpec(ProviderGitLab, path)
+		}
+		if host, _, _ := strings.Cut(rest, "/"); !strings.EqualFold(host, DefaultGitHubHost) {
+			return nil, fmt.Errorf("unknown source host in %s (use github:%s/owner/repo for GitHub Enterprise, or a git remote URL ending in .git)", repoStr, host)
+		}
+	}
+
+	repoPart, ref, err := splitRef(repoStr)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, repo, err := parseOwnerRepo(repoPart)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RepoSpec{Provider: ProviderGitHub, Owner: owner, Repo: repo, Ref: ref}, nil
+}
+
+// cutScheme strips an http(s) scheme, reporting whether one was present.
+func cutScheme(repoStr string) (string, bool) {
+	if rest, ok := strings.CutPrefix(repoStr, "https://"); ok {
+		return rest, true
+	}
+	return strings.CutPrefix(repoStr, "http://")
+}
+
+// normalizeGitHubHost maps github.com to "" so it matches unhosted specs.
+func normalizeGitHubHost(host string) string {
+	if strings.EqualFold(host, DefaultGitHubHost) {
+		return ""
+	}
+	return strings.ToLower(host)
+}
+
+// splitProviderPrefix separates a "provider:" prefix from a repository string.
+func splitProviderPrefix(repoStr string) (provider, rest string, ok bool) {
+	for _, p := range []string{ProviderGitHub, ProviderGitLab, ProviderGitea} {
+		if rest, ok := strings.CutPrefix(repoStr, p+":"); ok {
+			return p, rest, true
+		}
+	}
+	return "", "", false
+}
+
+// parseHostedSpec parses "[host/]owner/repo[@ref]" for a hosted provider.
+// A leading segment containing a dot or colon is treated as the host.
+func parseHostedSpec(provider, s string) (*RepoSpec, error) {
+	repoPart, ref, err := splitRef(s)
+	if err != nil {
+		return nil, err
+	}
+
+	repoPart = strings.Trim(strings.TrimSuffix(strings.TrimSuffix(repoPart, "/"), ".git"), "/")
+	segments := strings.Split(repoPart, "/")
+
+	spec := &RepoSpec{Provider: provider, Ref: ref}
+	if len(segments) > 0 && strings.ContainsAny(segments[0], ".:") {
+		spec.Host = segments[0]
+		segments = segments[1:]
+	}
+	if provider == ProviderGitea && spec.Host == "" {
+		spec.Host = DefaultGiteaHost
+	}
+	if provider == ProviderGitLab && spec.Host == DefaultGitLabHost {
+		spec.Host = ""
+	}
+	if provider == ProviderGitHub {
+		spec.Host = normalizeGitHubHost(spec.Host)
+	}
+
+	// GitLab projects can live in nested groups; other providers are owner/repo
+	// (extra segments, such as a skill path, are ignored)
+	if provider != ProviderGitLab && len(segments) > 2 {
+		segments = segments[:2]
+	}
+	if len(segments) < 2 {
+		return nil, fmt.Errorf("invalid repository format: %s (expected %s:owner/repo)", s, provider)
+	}
+	for _, seg := range segments {
+		if !segmentPattern.MatchString(seg) {
+			return nil, fmt.Errorf("invalid repository format: %s (expected %s:owner/repo)", s, provider)
+		}
+	}
+
+	spec.Owner = strings.Join(segments[:len(segments)-1], "/")
+	spec.Repo = segments[len(segments)-1]
+	return spec, nil
+}
+
+// isLocalPath returns true if repoStr refers to a directory on disk.
+func isLocalPath(repoStr string) bool {
+	if strings.HasPrefix(repoStr, "file://") {
+		return true
+	}
+	if repoStr == "." || repoStr == "~" {
+		return true
+	}
+	for _, prefix := range []string{"./", "../", "/", "~/"} {
+		if strings.HasPrefix(repoStr, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveLocalPath returns repoStr with a relative local directory made
+// absolute against dir. Anything else, including "~" paths, is returned as is.
+func ResolveLocalPath(repoStr, dir string) string {
+	if !isLocalPath(repoStr) {
+		return repoStr
+	}
+	repo, subdir, err := splitSubdir(repoStr)
+	if err != nil {
+		return repoStr
+	}
+	local := strings.TrimPrefix(repo, "file://")
+	if local == "" || local == "~" || strings.HasPrefix(local, "~/") || filepath.IsAbs(local) {
+		return repoStr
+	}
+
+	resolved := filepath.Join(dir, filepath.FromSlash(local))
+	if subdir != "" {
+		resolved += "//" + subdir
+	}
+	return resolved
+}
+
+// ResolveGitHubURL returns repoStr in the "github:host/owner/repo" form if
+// it is an http(s) URL on host, a GitHub Enterprise host. Other strings, and
+// all strings when host is empty or github.com, are returned unchanged.
+func ResolveGitHubURL(repoStr, host string) string {
+	host = normalizeGitHubHost(host)
+	if host == "" || isGitRemote(repoStr) {
+		return repoStr
+	}
+	rest, ok := cutScheme(repoStr)
+	if !ok {
+		return repoStr
+	}
+	urlHost, path, _ := strings.Cut(rest, "/")
+	if !strings.EqualFold(urlHost, host) {
+		return repoStr
+	}
+	return ProviderGitHub + ":" + host + "/" + path
+}
+
+// parseLocalPath parses a local directory source. Relative paths are kept as
+// written; config resolves them against its own directory when loaded.
+func parseLocalPath(repoStr string) (*RepoSpec, error) {
+	dir := strings.TrimPrefix(repoStr, "file://")
+	if dir == "" {
+		return nil, fmt.Errorf("invalid local source: %s (empty path)", repoStr)
+	}
+	if dir != "/" {
+		dir = strings.TrimSuffix(dir, "/")
+	}
+
+	name := path.Base(path.Clean(dir))
+	if name == "." || name == "/" || name == "~" || name == ".." {
+		name = "root"
+	}
+
+	return &RepoSpec{Provider: ProviderLocal, Path: dir, Repo: name}, nil
+}
+
+// isGitRemote returns true if repoStr is a plain git remote URL rather than a
+// hosted provider reference.
+func isGitRemote(repoStr string) bool {
+	for _, prefix := range []string{"git@", "ssh://", "git://", "git+ssh://", "git+https://", "git+http://"} {
+		if strings.HasPrefix(repoStr, prefix) {
+			return true
+		}
+	}
+
+	// https URLs ending in .git are git remotes unless they point at a known host
+	rest, ok := strings.CutPrefix(repoStr, "https://")
+	if !ok {
+		rest, ok = strings.CutPrefix(repoStr, "http://")
+	}
+	if !ok || strings.HasPrefix(rest, "github.com/") || strings.HasPrefix(rest, DefaultGitLabHost+"/") {
+		return false
+	}
+	return strings.HasSuffix(repoStr, ".git") || strings.Contains(repoStr, ".git@")
+}
+
+// parseGitRemote parses a git remote URL with an optional "@ref" after ".git".
+func parseGitRemote(repoStr string) (*RepoSpec, error) {
+	remote, ref := repoStr, ""
+	if idx := strings.LastIndex(repoStr, ".git@"); idx != -1 {
+		remote, ref = repoStr[:idx+len(".git")], repoStr[idx+len(".git@"):]
+		if ref == "" {
+			return nil, fmt.Errorf("invalid repository format: %s (empty ref after @)", repoStr)
+		}
+		if !refPattern.MatchString(ref) || strings.Contains(ref, "..") {
+			return nil, fmt.Errorf("invalid ref %q in %s", ref, repoStr)
+		}
+	}
+	remote = strings.TrimPrefix(remote, "git+")
+
+	var host, path string
+	if rest, ok := strings.CutPrefix(remote, "git@"); ok {
+		// scp-like syntax: git@host:path
+		var found bool
+		host, path, found = strings.Cut(rest, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid git remote: %s", repoStr)
+		}
+	} else {
+		_, rest, _ := strings.Cut(remote, "://")
+		hostPart, p, _ := strings.Cut(rest, "/")
+		if at := strings.LastIndex(hostPart, "@"); at != -1 {
+			hostPart = hostPart[at+1:]
+		}
+		host, path = hostPart, p
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if host == "" || len(segments) == 0 || segments[len(segments)-1] == "" {
+		return nil, fmt.Errorf("invalid git remote: %s", repoStr)
+	}
+
+	return &RepoSpec{
+		Provider: ProviderGit,
+		Host:     host,
+		URL:      remote,
+		Owner:    strings.Join(segments[:len(segments)-1], "/"),
+		Repo:     segments[len(segments)-1],
+		Ref:      ref,
+	}, nil
+}
+
+// splitRef separates an optional "@ref" suffix from a repository string.
+// Only an "@" after the first path separator is treated as a ref delimiter,
+// so user-info style prefixes are never mistaken for refs.
+func splitRef(repoStr string) (repoPart, ref string, err error) {
+	slash := strings.Index(repoStr, "/")
+	at := strings.LastIndex(repoStr, "@")
+	if slash == -1 || at < slash {
+		return repoStr, "", nil
+	}
+
+	repoPart, ref = repoStr[:at], repoStr[at+1:]
+	if ref == "" {
+		return "", "", fmt.Errorf("invalid repository format: %s (empty ref after @)", repoStr)
+	}
+	if !refPattern.MatchString(ref) || strings.Contains(ref, "..") {
+		return "", "", fmt.Errorf("invalid ref %q in %s", ref, repoStr)
+	}
+	return repoPart, ref, nil
+}
+
 // ParseRepo extracts owner and repo name from a repository string.
+// Any "@ref" suffix is ignored; use ParseRepoSpec to retrieve it.
+// For sources outside github.com this returns the spec's CacheKey, so the
+// result always identifies the source's local cache.
 // Accepts formats:
 //   - "https://github.com/owner/repo"
 //   - "https://github.com/owner/repo.git"
@@ -163,7 +851,19 @@ func (s *Source) AllRepos() []string {
 //   - "https://github.com/owner/repo/blob/main/file.md"
 //   - "github.com/owner/repo"
 //   - "owner/repo"
+//   - "owner/repo@ref"
+//   - "owner/repo//subdir" (the subdirectory is part of the cache key)
 func ParseRepo(repoStr string) (owner, repo string, err error) {
+	spec, err := ParseRepoSpec(repoStr)
+	if err != nil {
+		return "", "", err
+	}
+	owner, repo = spec.CacheKey()
+	return owner, repo, nil
+}
+
+// parseOwnerRepo extracts owner and repo name from a repository string without a ref.
+func parseOwnerRepo(repoStr string) (owner, repo string, err error) {
 	if repoStr == "" {
 		return "", "", fmt.Errorf("repository string is empty")
 	}
@@ -220,6 +920,19 @@ func (s *Source) Validate() error {
 			if _, _, err := ParseRepo(s.Multi.Base); err != nil {
 				return fmt.Errorf("invalid base source: %w", err)
 			}
+			if len(s.Multi.Layers) > 0 {
+				return fmt.Errorf("source.base and source.layers cannot both be set")
+			}
+		}
+		seenLayers := make(map[string]bool)
+		for i, repo := range s.Multi.Layers {
+			if _, _, err := ParseRepo(repo); err != nil {
+				return fmt.Errorf("invalid source for layer %d: %w", i+1, err)
+			}
+			if seenLayers[repo] {
+				return fmt.Errorf("source layer %q is listed more than once", repo)
+			}
+			seenLayers[repo] = true
 		}
 		for lang, repo := range s.Multi.Languages {
 			if _, _, err := ParseRepo(repo); err != nil {
@@ -236,7 +949,53 @@ func (s *Source) Validate() error {
 				return fmt.Errorf("invalid source for skill %q: %w", skill, err)
 			}
 		}
+		for rule, repo := range s.Multi.Rules {
+			if _, _, err := ParseRepo(repo); err != nil {
+				return fmt.Errorf("invalid source for rule %q: %w", rule, err)
+			}
+		}
+		for name, repo := range s.Multi.Evals {
+			if _, _, err := ParseRepo(repo); err != nil {
+				return fmt.Errorf("invalid source for eval %q: %w", name, err)
+			}
+		}
+		for name, repo := range s.Multi.Templates {
+			if _, _, err := ParseRepo(repo); err != nil {
+				return fmt.Errorf("invalid source for template %q: %w", name, err)
+			}
+		}
 	}
 
+	return checkRefs(s.AllRepos())
+}
+
+// checkRefs returns an error if a repo is configured at more than one ref.
+// Entries for one repo share a cache entry and a lockfile entry, so they
+// must all sync the same ref.
+func checkRefs(repos []string) error {
+	refs := make(map[string]string)
+	names := make(map[string]string)
+	for _, repo := range repos {
+		spec, err := ParseRepoSpec(repo)
+		if err != nil {
+			return err
+		}
+		owner, name := spec.CacheKey()
+		key := strings.ToLower(owner + "/" + name)
+		if ref, ok := refs[key]; ok && ref != spec.Ref {
+			return fmt.Errorf("source %s is configured at both %s and %s; every entry for a repo must use the same ref",
+				names[key], describeRef(ref), describeRef(spec.Ref))
+		}
+		refs[key] = spec.Ref
+		names[key] = spec.FullName()
+	}
 	return nil
 }
+
+// describeRef returns a ref for display, naming the default branch when empty.
+func describeRef(ref string) string {
+	if ref == "" {
+		return "the default branch"
+	}
+	return ref
+}
@@ -1,7 +1,14 @@
 // Package config handles staghorn configuration.
 package config
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/HartBrook/staghorn/internal/signing"
+)
 
 // DefaultTrustedSources contains sources that are trusted by default.
 // These are official staghorn repositories maintained by HartBrook.
@@ -12,35 +19,41 @@ var DefaultTrustedSources = []string{
 // IsTrusted checks if a repository is in the trusted list.
 // The trusted list can contain:
 //   - Full repo references: "owner/repo"
-//   - Org-level trust: "owner" (trusts all repos from that owner)
+//   - Org-level trust: "owner" (trusts all github.com repos from that owner)
+//   - A repo subdirectory: "owner/repo//subdir" (trusts only that source)
 func IsTrusted(repo string, trusted []string) bool {
 	if len(trusted) == 0 {
 		return false
 	}
 
-	// Parse the repo once upfront
-	repoOwner, repoName, err := ParseRepo(repo)
+	// Parse the repo once upfront; trust is granted to the hosting repo, so
+	// sources rooted in its subdirectories are covered too
+	repoSpec, err := ParseRepoSpec(repo)
 	if err != nil {
 		return false
 	}
 
 	for _, t := range trusted {
-		t = strings.ToLower(strings.TrimSpace(t))
+		t = strings.TrimSpace(t)
 		if t == "" {
 			continue
 		}
 
-		// Check for org-level trust (just "owner" without slash)
+		// Check for org-level trust (just a github.com "owner" without slash)
 		if !strings.Contains(t, "/") {
-			if strings.EqualFold(repoOwner, t) {
+			if repoSpec.Provider == ProviderGitHub && repoSpec.Host == "" && strings.EqualFold(repoSpec.Owner, t) {
 				return true
 			}
 			continue
 		}
 
-		// Parse the trusted entry and compare owner/repo
-		tOwner, tRepo, err := ParseRepo(t)
-		if err == nil && strings.EqualFold(tOwner, repoOwner) && strings.EqualFold(tRepo, repoName) {
+		// Parse the trusted entry and compare the hosting repos; an entry
+		// naming a subdirectory only trusts that subdirectory
+		tSpec, err := ParseRepoSpec(t)
+		if err != nil {
+			continue
+		}
+		if tSpec.sameRepo(repoSpec) && (tSpec.Subdir == "" || strings.EqualFold(tSpec.Subdir, repoSpec.Subdir)) {
 			return true
 		}
 	}
@@ -48,12 +61,142 @@ func IsTrusted(repo string, trusted []string) bool {
 	return false
 }
 
+// SkillPolicy controls how skill changes that add hooks, widen allowed-tools,
+// or add executable files are handled for a source.
+type SkillPolicy string
+
+const (
+	// SkillPolicyReview holds risky skill changes until 'staghorn approve'. The default.
+	SkillPolicyReview SkillPolicy = "review"
+	// SkillPolicyAllow installs risky skill changes without asking.
+	SkillPolicyAllow SkillPolicy = "allow"
+	// SkillPolicyDeny never installs risky skill changes.
+	SkillPolicyDeny SkillPolicy = "deny"
+)
+
+// TrustedSource is an entry in the trusted list: a repo ("owner/repo") or an
+// org ("owner"), optionally with a policy for its skills. In YAML it is either
+// a plain string or an object:
+//
+//	trusted:
+//	  - acme-corp
+//	  - repo: acme-corp/standards
+//	    skills: allow
+//	    keys:
+//	      - ssh-ed25519 AAAA... release@acme
+type TrustedSource struct {
+	Repo   string      `yaml:"repo"`
+	Skills SkillPolicy `yaml:"skills,omitempty"`
+
+	// Keys pins the public keys (SSH or minisign) that must sign the repo's
+	// checksums manifest. When set, sync refuses unsigned or mismatched content.
+	Keys []string `yaml:"keys,omitempty"`
+
+	written string // Repo as written in the config file, if resolveLocalPaths changed it
+}
+
+// UnmarshalYAML accepts either a string or an object.
+func (t *TrustedSource) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		t.Repo = node.Value
+		return nil
+	}
+	if node.Kind == yaml.MappingNode {
+		type plain TrustedSource
+		return node.Decode((*plain)(t))
+	}
+	return fmt.Errorf("trusted entry must be a string or object, got %v", node.Kind)
+}
+
+// MarshalYAML writes entries without a policy or keys as plain strings.
+func (t TrustedSource) MarshalYAML() (interface{}, error) {
+	if t.written != "" {
+		t.Repo = t.written
+	}
+	if t.Skills == "" && len(t.Keys) == 0 {
+		return t.Repo, nil
+	}
+	type plain TrustedSource
+	return plain(t), nil
+}
+
+// Validate checks the entry has a repo and a known skill policy.
+func (t TrustedSource) Validate() error {
+	if strings.TrimSpace(t.Repo) == "" {
+		return fmt.Errorf("trusted entry is missing a repo")
+	}
+	switch t.Skills {
+	case "", SkillPolicyReview, SkillPolicyAllow, SkillPolicyDeny:
+	default:
+		return fmt.Errorf("trusted entry %s has invalid skills policy %q (use review, allow, or deny)", t.Repo, t.Skills)
+	}
+	if _, err := signing.ParseKeys(t.Keys); err != nil {
+		return fmt.Errorf("trusted entry %s: %w", t.Repo, err)
+	}
+	return nil
+}
+
+// TrustedRepos returns the repos and orgs in a trusted list.
+func TrustedRepos(trusted []TrustedSource) []string {
+	repos := make([]string, 0, len(trusted))
+	for _, t := range trusted {
+		repos = append(repos, t.Repo)
+	}
+	return repos
+}
+
+// SkillPolicyFor returns the skill policy a trusted list sets for repo.
+// A repo entry takes precedence over its org; unlisted repos get SkillPolicyReview.
+func SkillPolicyFor(repo string, trusted []TrustedSource) SkillPolicy {
+	t := findTrusted(repo, trusted, func(t TrustedSource) bool { return t.Skills != "" })
+	if t == nil {
+		return SkillPolicyReview
+	}
+	return t.Skills
+}
+
+// KeysFor returns the public keys a trusted list pins for repo, or nil if
+// the repo's content doesn't need to be signed. A repo entry takes precedence over its org.
+func KeysFor(repo string, trusted []TrustedSource) []string {
+	t := findTrusted(repo, trusted, func(t TrustedSource) bool { return len(t.Keys) > 0 })
+	if t == nil {
+		return nil
+	}
+	return t.Keys
+}
+
+// findTrusted returns the most specific entry matching repo for which has
+// returns true: an exact or owner/repo match first, then the first org match.
+func findTrusted(repo string, trusted []TrustedSource, has func(TrustedSource) bool) *TrustedSource {
+	var org *TrustedSource
+	for i, t := range trusted {
+		if !has(t) {
+			continue
+		}
+		// Exact matches cover sources IsTrusted can't parse, like file:// paths
+		if strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(t.Repo), "file://"), strings.TrimPrefix(repo, "file://")) {
+			return &trusted[i]
+		}
+		if !IsTrusted(repo, []string{t.Repo}) {
+			continue
+		}
+		if strings.Contains(t.Repo, "/") {
+			return &trusted[i]
+		}
+		if org == nil {
+			org = &trusted[i]
+		}
+	}
+	return org
+}
+
 // TrustWarning returns a warning message for untrusted sources.
-func TrustWarning(repo string) string {
+// url is where the source can be reviewed (see RepoSpec.WebURL).
+func TrustWarning(repo, url string) string {
 	return `⚠️  You're installing from an untrusted source: ` + repo + `
 
     This source is not in your trusted list.
-    Review the source before proceeding: https://github.com/` + repo + `
+    Review the source before proceeding: ` + url + `
 
     To trust this source, add it to your config:
       trusted:
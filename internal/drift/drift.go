@@ -0,0 +1,168 @@
+// Package drift remembers what staghorn last wrote to each generated file, so
+// hand edits can be found and rescued before the next write overwrites them.
+package drift
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/HartBrook/staghorn/internal/fsutil"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ledgerFile lists the checksum of every generated file, inside the ledger directory.
+// The content written is kept alongside it, named by checksum.
+const ledgerFile = "ledger.json"
+
+// Ledger maps generated files to the checksum of the content staghorn wrote.
+type Ledger struct {
+	dir   string
+	Files map[string]string `json:"files"` // Absolute path -> hex sha256 of written content
+}
+
+// Load reads the ledger stored in dir. A missing ledger loads empty.
+func Load(dir string) (*Ledger, error) {
+	l := &Ledger{dir: dir, Files: make(map[string]string)}
+	data, err := os.ReadFile(filepath.Join(dir, ledgerFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, ledgerFile), err)
+	}
+	if l.Files == nil {
+		l.Files = make(map[string]string)
+	}
+	return l, nil
+}
+
+// Save writes the ledger, forgetting files that no longer exist and removing
+// stored content no file refers to.
+func (l *Ledger) Save() error {
+	for path := range l.Files {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(l.Files, path)
+		}
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return err
+	}
+	if err := fsutil.WriteFile(filepath.Join(l.dir, ledgerFile), append(data, '\n'), 0644); err != nil {
+		return err
+	}
+
+	used := make(map[string]bool, len(l.Files))
+	for _, sum := range l.Files {
+		used[sum] = true
+	}
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); name != ledgerFile && !used[name] && !fsutil.IsTemp(name) {
+			_ = os.Remove(filepath.Join(l.dir, name))
+		}
+	}
+	return nil
+}
+
+// Record notes that staghorn wrote content to path.
+func (l *Ledger) Record(path string, content []byte) error {
+	sum := checksum(content)
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return err
+	}
+	stored := filepath.Join(l.dir, sum)
+	if _, err := os.Stat(stored); os.IsNotExist(err) {
+		if err := fsutil.WriteFile(stored, content, 0644); err != nil {
+			return err
+		}
+	}
+	l.Files[path] = sum
+	return nil
+}
+
+// Forget stops tracking path, so the next write replaces it without complaint.
+func (l *Ledger) Forget(path string) {
+	delete(l.Files, path)
+}
+
+// IsDrifted reports whether path was written by staghorn and has been edited
+// since. Untracked and deleted files haven't drifted.
+func (l *Ledger) IsDrifted(path string) bool {
+	sum, ok := l.Files[path]
+	if !ok {
+		return false
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return checksum(content) != sum
+}
+
+// Drifted returns every tracked file that has been edited, sorted.
+func (l *Ledger) Drifted() []string {
+	var drifted []string
+	for path := range l.Files {
+		if l.IsDrifted(path) {
+			drifted = append(drifted, path)
+		}
+	}
+	sort.Strings(drifted)
+	return drifted
+}
+
+// Original returns the content staghorn last wrote to path.
+func (l *Ledger) Original(path string) ([]byte, error) {
+	sum, ok := l.Files[path]
+	if !ok {
+		return nil, fmt.Errorf("%s is not tracked", path)
+	}
+	content, err := os.ReadFile(filepath.Join(l.dir, sum))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the original of %s: %w", path, err)
+	}
+	return content, nil
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Edits compares a generated file with its hand-edited version. It returns
+// each run of added or changed lines as a block, and how many original lines
+// were deleted or changed.
+func Edits(original, current string) (added []string, removed int) {
+	a := difflib.SplitLines(original)
+	b := difflib.SplitLines(current)
+	for _, op := range difflib.NewMatcher(a, b).GetOpCodes() {
+		switch op.Tag {
+		case 'i', 'r':
+			if block := strings.TrimSpace(strings.Join(b[op.J1:op.J2], "")); block != "" {
+				added = append(added, block)
+			}
+		}
+		switch op.Tag {
+		case 'd', 'r':
+			removed += op.I2 - op.I1
+		}
+	}
+	return added, removed
+}
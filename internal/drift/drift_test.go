@@ -0,0 +1,72 @@
+package drift
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedger(t *testing.T) {
+	dir := t.TempDir()
+	ledgerDir := filepath.Join(dir, "drift")
+	claudeMD := filepath.Join(dir, "CLAUDE.md")
+	command := filepath.Join(dir, "review.md")
+
+	l, err := Load(ledgerDir)
+	require.NoError(t, err)
+	assert.Empty(t, l.Files, "missing ledger should load empty")
+
+	for path, content := range map[string]string{claudeMD: "## Team\n", command: "Review it\n"} {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		require.NoError(t, l.Record(path, []byte(content)))
+	}
+	require.NoError(t, l.Save())
+
+	l, err = Load(ledgerDir)
+	require.NoError(t, err)
+	assert.False(t, l.IsDrifted(claudeMD))
+	assert.Empty(t, l.Drifted())
+
+	require.NoError(t, os.WriteFile(claudeMD, []byte("## Team\n\nMy note\n"), 0644))
+	assert.True(t, l.IsDrifted(claudeMD))
+	assert.Equal(t, []string{claudeMD}, l.Drifted())
+	assert.False(t, l.IsDrifted(filepath.Join(dir, "untracked.md")))
+
+	original, err := l.Original(claudeMD)
+	require.NoError(t, err)
+	assert.Equal(t, "## Team\n", string(original))
+
+	t.Run("deleted files are forgotten on save", func(t *testing.T) {
+		require.NoError(t, os.Remove(command))
+		assert.False(t, l.IsDrifted(command))
+		require.NoError(t, l.Save())
+		assert.NotContains(t, l.Files, command)
+
+		entries, err := os.ReadDir(ledgerDir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2, "only the ledger and CLAUDE.md's original should remain")
+	})
+
+	t.Run("forget", func(t *testing.T) {
+		l.Forget(claudeMD)
+		assert.False(t, l.IsDrifted(claudeMD))
+		_, err := l.Original(claudeMD)
+		assert.Error(t, err)
+	})
+}
+
+func TestEdits(t *testing.T) {
+	original := "## Team\n\n- Use tabs\n- Write tests\n\n## Review\n\n- Two approvals\n"
+	current := "## Team\n\n- Use tabs\n- Prefer table tests\n\n## Review\n\n- Two approvals\n\n## Mine\n\n- Be brief\n"
+
+	added, removed := Edits(original, current)
+	assert.Equal(t, []string{"- Prefer table tests", "## Mine\n\n- Be brief"}, added)
+	assert.Equal(t, 1, removed)
+
+	added, removed = Edits(original, original)
+	assert.Empty(t, added)
+	assert.Zero(t, removed)
+}
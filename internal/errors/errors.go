@@ -1,7 +1,11 @@
 // Package errors provides typed errors for staghorn.
 package errors
 
-import "fmt"
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+)
 
 // ErrorCode identifies the type of error.
 type ErrorCode string
@@ -19,6 +23,13 @@ const (
 	ErrAnthropicAuthFailed ErrorCode = "ANTHROPIC_AUTH_FAILED"
 	ErrOptimizationFailed  ErrorCode = "OPTIMIZATION_FAILED"
 	ErrValidationFailed    ErrorCode = "VALIDATION_FAILED"
+	ErrLockfileMismatch    ErrorCode = "LOCKFILE_MISMATCH"
+	ErrBusy                ErrorCode = "BUSY"
+	ErrSignatureInvalid    ErrorCode = "SIGNATURE_INVALID"
+	ErrLocalEdits          ErrorCode = "LOCAL_EDITS"
+	ErrUpdatesAvailable    ErrorCode = "UPDATES_AVAILABLE"
+	ErrCheckFailed         ErrorCode = "CHECK_FAILED"
+	ErrUpgradeConflicts    ErrorCode = "UPGRADE_CONFLICTS"
 )
 
 // StaghornError represents a typed error with user-friendly hints.
@@ -142,3 +153,87 @@ func ValidationFailed(missing []string) *StaghornError {
 		Hint:    "Use --force to apply anyway, or report this issue",
 	}
 }
+
+// LockfileMismatch returns an error when a frozen sync doesn't match staghorn.lock.
+func LockfileMismatch(reason string) *StaghornError {
+	return &StaghornError{
+		Code:    ErrLockfileMismatch,
+		Message: fmt.Sprintf("sync does not match lockfile: %s", reason),
+		Hint:    "Run `staghorn sync --force` without --frozen to update staghorn.lock",
+	}
+}
+
+// Busy returns an error when another staghorn process holds the run lock.
+func Busy(cause error) *StaghornError {
+	return &StaghornError{
+		Code:    ErrBusy,
+		Message: "another staghorn process is still running",
+		Hint:    "Wait for it to finish and try again",
+		Cause:   cause,
+	}
+}
+
+// SignatureInvalid returns an error when a source with pinned keys serves
+// content that isn't covered by a valid signature.
+func SignatureInvalid(repo, reason string) *StaghornError {
+	return &StaghornError{
+		Code:    ErrSignatureInvalid,
+		Message: fmt.Sprintf("%s failed signature verification: %s", repo, reason),
+		Hint:    "Check the keys pinned for this source under trusted in your config, or ask its maintainers to re-sign .staghorn/checksums.json",
+	}
+}
+
+// LocalEdits returns an error when writing would overwrite generated files
+// that were edited by hand.
+func LocalEdits(files []string) *StaghornError {
+	return &StaghornError{
+		Code:    ErrLocalEdits,
+		Message: fmt.Sprintf("generated files were edited by hand: %s", strings.Join(files, ", ")),
+		Hint:    "Run `staghorn rescue` to move the edits into your own config, or `staghorn rescue --discard` to let staghorn overwrite them",
+	}
+}
+
+// UpdatesAvailable returns an error when sources have changed upstream since
+// the last sync, so scripts can check with the exit code.
+func UpdatesAvailable(count int) *StaghornError {
+	return &StaghornError{
+		Code:    ErrUpdatesAvailable,
+		Message: fmt.Sprintf("%d source(s) have upstream changes", count),
+		Hint:    "Run `staghorn sync --force` to fetch them",
+	}
+}
+
+// CheckFailed returns an error when some sources couldn't be checked for
+// upstream changes; outdated counts those that could and have changed.
+func CheckFailed(failed, outdated int) *StaghornError {
+	message := fmt.Sprintf("failed to check %d source(s)", failed)
+	if outdated > 0 {
+		message += fmt.Sprintf("; %d other source(s) have upstream changes", outdated)
+	}
+	return &StaghornError{
+		Code:    ErrCheckFailed,
+		Message: message,
+		Hint:    "Check your network connection and credentials for the sources listed above",
+	}
+}
+
+// UpgradeConflicts returns an error when a project template upgrade overlaps
+// the project's own edits, so scripts upgrading many projects can find them.
+func UpgradeConflicts(count int) *StaghornError {
+	return &StaghornError{
+		Code:    ErrUpgradeConflicts,
+		Message: fmt.Sprintf("template upgrade has %d conflict(s) to review", count),
+		Hint:    "Review the listed sections in .staghorn/project.md and ./CLAUDE.md",
+	}
+}
+
+// ExitCode returns the process exit status for err: 2 when a check couldn't
+// be completed, so scripts can tell it apart from a check that found
+// something (1), and 1 for every other error.
+func ExitCode(err error) int {
+	var se *StaghornError
+	if stderrors.As(err, &se) && se.Code == ErrCheckFailed {
+		return 2
+	}
+	return 1
+}
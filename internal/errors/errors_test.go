@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -95,3 +96,15 @@ func TestWrap(t *testing.T) {
 	assert.Equal(t, "wrapper hint", err.Hint)
 	assert.Equal(t, cause, err.Cause)
 }
+
+func TestCheckFailed(t *testing.T) {
+	assert.Equal(t, "failed to check 2 source(s)", CheckFailed(2, 0).Error())
+	assert.Equal(t, "failed to check 1 source(s); 3 other source(s) have upstream changes", CheckFailed(1, 3).Error())
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 2, ExitCode(CheckFailed(1, 0)))
+	assert.Equal(t, 2, ExitCode(fmt.Errorf("outdated: %w", CheckFailed(1, 2))))
+	assert.Equal(t, 1, ExitCode(UpdatesAvailable(1)))
+	assert.Equal(t, 1, ExitCode(errors.New("boom")))
+}
@@ -0,0 +1,82 @@
+// Package fsutil provides crash-safe file writes and an advisory process lock
+// so concurrent or interrupted staghorn runs never leave half-written files.
+package fsutil
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// tempMarker appears in the name of every temp file this package creates, so
+// files left behind by an interrupted write can be found and removed.
+const tempMarker = ".staghorn-tmp-"
+
+// WriteFile writes data to a temp file beside path and renames it into place.
+// Readers see either the old content or the new, never a partial write.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := CreateTemp(path)
+	if err != nil {
+		return err
+	}
+	name := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(name)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(name)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	if err := os.Chmod(name, perm); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	if err := os.Rename(name, path); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	return nil
+}
+
+// CreateTemp creates an empty temp file in the same directory as path, for
+// staging new content that is later renamed over path.
+func CreateTemp(path string) (*os.File, error) {
+	return os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+tempMarker+"*")
+}
+
+// IsTemp reports whether name is a temp file created by this package.
+func IsTemp(name string) bool {
+	return strings.Contains(filepath.Base(name), tempMarker)
+}
+
+// RemoveTemp deletes temp files left under dir by interrupted writes.
+// Returns the number of files removed. A missing dir is not an error.
+func RemoveTemp(dir string) (int, error) {
+	removed := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !IsTemp(d.Name()) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,66 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "CLAUDE.md")
+
+	require.NoError(t, WriteFile(path, []byte("v1"), 0600))
+	require.NoError(t, WriteFile(path, []byte("v2"), 0644))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(content))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temp file left behind")
+}
+
+func TestWriteFile_MissingDir(t *testing.T) {
+	err := WriteFile(filepath.Join(t.TempDir(), "missing", "file.md"), []byte("x"), 0644)
+	assert.Error(t, err)
+}
+
+func TestRemoveTemp(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+
+	keep := filepath.Join(dir, "sub", "review.md")
+	require.NoError(t, os.WriteFile(keep, []byte("keep"), 0644))
+
+	// Simulate writes interrupted before the rename
+	for _, path := range []string{filepath.Join(dir, "CLAUDE.md"), keep} {
+		tmp, err := CreateTemp(path)
+		require.NoError(t, err)
+		require.NoError(t, tmp.Close())
+		assert.True(t, IsTemp(tmp.Name()))
+	}
+
+	removed, err := RemoveTemp(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	assert.FileExists(t, keep)
+
+	removed, err = RemoveTemp(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+}
+
+func TestIsTemp(t *testing.T) {
+	assert.True(t, IsTemp("/a/.CLAUDE.md.staghorn-tmp-123"))
+	assert.False(t, IsTemp("/a/CLAUDE.md"))
+	assert.False(t, IsTemp("/a/.staghorn-manifest.json"))
+}
@@ -0,0 +1,94 @@
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrLocked is returned when another process holds the lock past the timeout.
+var ErrLocked = errors.New("locked by another process")
+
+// pollInterval is how often a waiting Acquire retries the lock.
+const pollInterval = 100 * time.Millisecond
+
+// Lock is an advisory lock held on a file. The operating system releases it
+// if the process dies, so a crashed run never leaves the lock stuck.
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes an exclusive lock on path, creating the file if needed.
+// If another process holds it, Acquire calls onWait once and keeps retrying
+// until timeout, then returns an error wrapping ErrLocked.
+func Acquire(path string, timeout time.Duration, onWait func(holder int)) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	waited := false
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+
+		holder := readPID(path)
+		if !waited && onWait != nil {
+			onWait(holder)
+		}
+		waited = true
+
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			if holder > 0 {
+				return nil, fmt.Errorf("%w (pid %d)", ErrLocked, holder)
+			}
+			return nil, ErrLocked
+		}
+		time.Sleep(pollInterval)
+	}
+
+	// Record the holder so a waiting process can say who it's waiting for
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{file: f}, nil
+}
+
+// Release drops the lock. It is safe to call on a nil Lock.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	_ = l.file.Truncate(0)
+	err := unlock(l.file)
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	l.file = nil
+	return err
+}
+
+// readPID returns the process ID recorded in a lock file, or 0 if unknown.
+func readPID(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
@@ -0,0 +1,51 @@
+package fsutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "run.lock")
+
+	lock, err := Acquire(path, time.Second, nil)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), readPID(path))
+
+	t.Run("second acquire waits then times out", func(t *testing.T) {
+		var holder int
+		waits := 0
+		_, err := Acquire(path, 250*time.Millisecond, func(pid int) {
+			holder = pid
+			waits++
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrLocked))
+		assert.Equal(t, 1, waits)
+		assert.Equal(t, os.Getpid(), holder)
+	})
+
+	t.Run("acquire succeeds once released", func(t *testing.T) {
+		done := make(chan error, 1)
+		go func() {
+			l, err := Acquire(path, 5*time.Second, nil)
+			if err == nil {
+				err = l.Release()
+			}
+			done <- err
+		}()
+
+		time.Sleep(150 * time.Millisecond)
+		require.NoError(t, lock.Release())
+		require.NoError(t, <-done)
+	})
+
+	assert.NoError(t, lock.Release(), "release is idempotent")
+	assert.NoError(t, (*Lock)(nil).Release())
+}
@@ -0,0 +1,313 @@
+// Package generation keeps numbered snapshots of the files sync manages in
+// ~/.claude so a bad update can be rolled back.
+package generation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/HartBrook/staghorn/internal/fsutil"
+	"github.com/HartBrook/staghorn/internal/manifest"
+)
+
+// MetaFile is the metadata stored in each generation directory.
+const MetaFile = "generation.json"
+
+// DefaultKeep is how many generations are kept before the oldest are removed.
+const DefaultKeep = 10
+
+// defaultMode is the permissions of files without an entry in Generation.Modes.
+const defaultMode os.FileMode = 0644
+
+// Generation is a snapshot of every managed file after an apply.
+type Generation struct {
+	ID      int       `json:"id"`
+	Created time.Time `json:"created"`
+	Sources []Source  `json:"sources,omitempty"`
+	Files   []string  `json:"files"`          // Slash-separated, relative to the Claude directory
+	Note    string    `json:"note,omitempty"` // Why it was recorded, if not a sync (e.g. "rollback to 3")
+
+	// Modes holds the permissions of files that aren't plain 0644 ones,
+	// such as executable skill scripts.
+	Modes map[string]os.FileMode `json:"modes,omitempty"`
+
+	dir string
+}
+
+// Source is a source repo and the commit it was synced at.
+type Source struct {
+	Repo   string `json:"repo"`
+	Ref    string `json:"ref,omitempty"`
+	Commit string `json:"commit,omitempty"`
+}
+
+// Mode returns the permissions a file had when the generation was recorded.
+func (g *Generation) Mode(rel string) os.FileMode {
+	if mode, ok := g.Modes[rel]; ok {
+		return mode
+	}
+	return defaultMode
+}
+
+// ReadFile returns the snapshotted content of a file in the generation.
+func (g *Generation) ReadFile(rel string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(g.dir, "files", filepath.FromSlash(rel)))
+}
+
+// Store manages the generations recorded for a Claude directory.
+type Store struct {
+	dir  string // Where generations are kept
+	root string // Claude directory the snapshotted files live in
+	keep int
+}
+
+// NewStore creates a store keeping generations in dir for files under root.
+func NewStore(dir, root string) *Store {
+	return &Store{dir: dir, root: root, keep: DefaultKeep}
+}
+
+// List returns all recorded generations, oldest first.
+func (s *Store) List() ([]*Generation, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var gens []*Generation
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		g, err := s.load(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		gens = append(gens, g)
+	}
+
+	sort.Slice(gens, func(i, j int) bool { return gens[i].ID < gens[j].ID })
+	return gens, nil
+}
+
+// Get returns the generation with the given ID.
+func (s *Store) Get(id int) (*Generation, error) {
+	g, err := s.load(filepath.Join(s.dir, strconv.Itoa(id)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("generation %d not found", id)
+		}
+		return nil, err
+	}
+	return g, nil
+}
+
+// Latest returns the most recent generation, or nil if none are recorded.
+func (s *Store) Latest() (*Generation, error) {
+	gens, err := s.List()
+	if err != nil || len(gens) == 0 {
+		return nil, err
+	}
+	return gens[len(gens)-1], nil
+}
+
+func (s *Store) load(dir string) (*Generation, error) {
+	data, err := os.ReadFile(filepath.Join(dir, MetaFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var g Generation
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, MetaFile), err)
+	}
+	g.dir = dir
+	return &g, nil
+}
+
+// Record snapshots files (relative to the Claude directory) as a new generation.
+// Nothing is recorded if the files match the latest generation exactly; the
+// returned generation is nil in that case. Generations beyond the keep limit are removed.
+func (s *Store) Record(files []string, sources []Source, note string) (*Generation, error) {
+	files = append([]string{}, files...)
+	sort.Strings(files)
+
+	latest, err := s.Latest()
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && note == "" && s.matches(latest, files) {
+		return nil, nil
+	}
+
+	id := 1
+	if latest != nil {
+		id = latest.ID + 1
+	}
+	g := &Generation{
+		ID:      id,
+		Created: time.Now(),
+		Sources: sources,
+		Files:   files,
+		Note:    note,
+		dir:     filepath.Join(s.dir, strconv.Itoa(id)),
+	}
+
+	// Build in a temp directory and rename, so a failed snapshot never looks complete
+	tmp := g.dir + ".tmp"
+	_ = os.RemoveAll(tmp)
+	if err := s.snapshot(tmp, g); err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, err
+	}
+	if err := os.Rename(tmp, g.dir); err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, err
+	}
+
+	return g, s.prune()
+}
+
+// snapshot copies the generation's files and metadata into dir, recording
+// the permissions of each file in g.Modes.
+func (s *Store) snapshot(dir string, g *Generation) error {
+	for _, rel := range g.Files {
+		src := filepath.Join(s.root, filepath.FromSlash(rel))
+		content, err := os.ReadFile(src)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", rel, err)
+		}
+		info, err := os.Stat(src)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", rel, err)
+		}
+		if mode := info.Mode().Perm(); mode != defaultMode {
+			if g.Modes == nil {
+				g.Modes = make(map[string]os.FileMode)
+			}
+			g.Modes[rel] = mode
+		}
+		dst := filepath.Join(dir, "files", filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dst, content, 0644); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, MetaFile), append(data, '\n'), 0644)
+}
+
+// matches reports whether files on disk are exactly the generation's files,
+// content, and permissions.
+func (s *Store) matches(g *Generation, files []string) bool {
+	if len(g.Files) != len(files) {
+		return false
+	}
+	for i, rel := range files {
+		if g.Files[i] != rel {
+			return false
+		}
+		path := filepath.Join(s.root, filepath.FromSlash(rel))
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != g.Mode(rel) {
+			return false
+		}
+		saved, err := g.ReadFile(rel)
+		if err != nil || !bytes.Equal(current, saved) {
+			return false
+		}
+	}
+	return true
+}
+
+// prune removes the oldest generations beyond the keep limit.
+func (s *Store) prune() error {
+	gens, err := s.List()
+	if err != nil {
+		return err
+	}
+	for len(gens) > s.keep {
+		if err := os.RemoveAll(gens[0].dir); err != nil {
+			return err
+		}
+		gens = gens[1:]
+	}
+	return nil
+}
+
+// Restore puts the Claude directory back to generation g. Files in current
+// (the managed files now installed) that aren't part of g are removed.
+// Every file is staged next to its destination before any is moved into
+// place, so a file that can't be read or written leaves the directory untouched.
+func (s *Store) Restore(g *Generation, current []string) error {
+	type stagedFile struct{ tmp, dst string }
+	var staged []stagedFile
+	cleanup := func() {
+		for _, f := range staged {
+			_ = os.Remove(f.tmp)
+		}
+	}
+
+	for _, rel := range g.Files {
+		content, err := g.ReadFile(rel)
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("generation %d is missing %s: %w", g.ID, rel, err)
+		}
+		dst := filepath.Join(s.root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			cleanup()
+			return err
+		}
+		tmp, err := fsutil.CreateTemp(dst)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		staged = append(staged, stagedFile{tmp: tmp.Name(), dst: dst})
+		_, err = tmp.Write(content)
+		if cerr := tmp.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = os.Chmod(tmp.Name(), g.Mode(rel))
+		}
+		if err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	for _, f := range staged {
+		if err := os.Rename(f.tmp, f.dst); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	_, err := manifest.Remove(s.root, manifest.Stale(current, g.Files))
+	return err
+}
@@ -0,0 +1,173 @@
+package generation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HartBrook/staghorn/internal/fsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func readFile(t *testing.T, root, rel string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestRecord(t *testing.T) {
+	root := t.TempDir()
+	store := NewStore(filepath.Join(t.TempDir(), "generations"), root)
+
+	writeFile(t, root, "CLAUDE.md", "v1")
+	writeFile(t, root, "commands/review.md", "review")
+
+	sources := []Source{{Repo: "acme/standards", Ref: "main", Commit: "abc123"}}
+	g, err := store.Record([]string{"commands/review.md", "CLAUDE.md"}, sources, "")
+	require.NoError(t, err)
+	require.NotNil(t, g)
+	assert.Equal(t, 1, g.ID)
+	assert.Equal(t, []string{"CLAUDE.md", "commands/review.md"}, g.Files)
+
+	t.Run("unchanged files are not recorded again", func(t *testing.T) {
+		g, err := store.Record([]string{"CLAUDE.md", "commands/review.md"}, sources, "")
+		require.NoError(t, err)
+		assert.Nil(t, g)
+	})
+
+	t.Run("changed files get a new generation", func(t *testing.T) {
+		writeFile(t, root, "CLAUDE.md", "v2")
+		g, err := store.Record([]string{"CLAUDE.md", "commands/review.md"}, sources, "")
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		assert.Equal(t, 2, g.ID)
+
+		saved, err := g.ReadFile("CLAUDE.md")
+		require.NoError(t, err)
+		assert.Equal(t, "v2", string(saved))
+	})
+
+	gens, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, gens, 2)
+	assert.Equal(t, sources, gens[0].Sources)
+
+	first, err := store.Get(1)
+	require.NoError(t, err)
+	saved, err := first.ReadFile("CLAUDE.md")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(saved))
+
+	_, err = store.Get(9)
+	assert.Error(t, err)
+}
+
+func TestRecord_KeepsLimit(t *testing.T) {
+	root := t.TempDir()
+	store := NewStore(filepath.Join(t.TempDir(), "generations"), root)
+	store.keep = 3
+
+	for _, v := range []string{"1", "2", "3", "4", "5"} {
+		writeFile(t, root, "CLAUDE.md", v)
+		_, err := store.Record([]string{"CLAUDE.md"}, nil, "")
+		require.NoError(t, err)
+	}
+
+	gens, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, gens, 3)
+	assert.Equal(t, 3, gens[0].ID)
+	assert.Equal(t, 5, gens[2].ID)
+}
+
+func TestRestore(t *testing.T) {
+	root := t.TempDir()
+	store := NewStore(filepath.Join(t.TempDir(), "generations"), root)
+
+	writeFile(t, root, "CLAUDE.md", "v1")
+	writeFile(t, root, "skills/react/SKILL.md", "react v1")
+	g1, err := store.Record([]string{"CLAUDE.md", "skills/react/SKILL.md"}, nil, "")
+	require.NoError(t, err)
+
+	// A later sync changes CLAUDE.md, drops the react skill, and adds a command
+	writeFile(t, root, "CLAUDE.md", "v2")
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "skills", "react")))
+	writeFile(t, root, "commands/debug.md", "debug")
+	writeFile(t, root, "commands/mine.md", "not managed")
+	current := []string{"CLAUDE.md", "commands/debug.md"}
+	_, err = store.Record(current, nil, "")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Restore(g1, current))
+
+	assert.Equal(t, "v1", readFile(t, root, "CLAUDE.md"))
+	assert.Equal(t, "react v1", readFile(t, root, "skills/react/SKILL.md"))
+	assert.NoFileExists(t, filepath.Join(root, "commands", "debug.md"))
+	assert.Equal(t, "not managed", readFile(t, root, "commands/mine.md"))
+	assertNoTempFiles(t, root)
+}
+
+func TestRestore_KeepsFileModes(t *testing.T) {
+	root := t.TempDir()
+	store := NewStore(filepath.Join(t.TempDir(), "generations"), root)
+
+	writeFile(t, root, "skills/deploy/SKILL.md", "deploy")
+	writeFile(t, root, "skills/deploy/run.sh", "#!/bin/sh\necho v1")
+	script := filepath.Join(root, "skills", "deploy", "run.sh")
+	require.NoError(t, os.Chmod(script, 0755))
+	files := []string{"skills/deploy/SKILL.md", "skills/deploy/run.sh"}
+	g1, err := store.Record(files, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]os.FileMode{"skills/deploy/run.sh": 0755}, g1.Modes)
+
+	// Losing the exec bit is a change worth recording
+	require.NoError(t, os.Chmod(script, 0644))
+	g2, err := store.Record(files, nil, "")
+	require.NoError(t, err)
+	require.NotNil(t, g2)
+	assert.Empty(t, g2.Modes)
+
+	reloaded, err := store.Get(g1.ID)
+	require.NoError(t, err)
+	require.NoError(t, store.Restore(reloaded, files))
+
+	info, err := os.Stat(script)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	info, err = os.Stat(filepath.Join(root, "skills", "deploy", "SKILL.md"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+}
+
+func TestRestore_MissingSnapshotChangesNothing(t *testing.T) {
+	root := t.TempDir()
+	store := NewStore(filepath.Join(t.TempDir(), "generations"), root)
+
+	writeFile(t, root, "CLAUDE.md", "v1")
+	writeFile(t, root, "commands/review.md", "review v1")
+	g, err := store.Record([]string{"CLAUDE.md", "commands/review.md"}, nil, "")
+	require.NoError(t, err)
+
+	writeFile(t, root, "CLAUDE.md", "v2")
+	require.NoError(t, os.Remove(filepath.Join(g.dir, "files", "commands", "review.md")))
+
+	assert.Error(t, store.Restore(g, []string{"CLAUDE.md", "commands/review.md"}))
+	assert.Equal(t, "v2", readFile(t, root, "CLAUDE.md"))
+	assertNoTempFiles(t, root)
+}
+
+func assertNoTempFiles(t *testing.T, root string) {
+	t.Helper()
+	removed, err := fsutil.RemoveTemp(root)
+	require.NoError(t, err)
+	assert.Zero(t, removed, "restore left temp files behind")
+}
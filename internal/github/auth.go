@@ -4,6 +4,7 @@ package github
 import (
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	"github.com/HartBrook/staghorn/internal/errors"
@@ -12,8 +13,29 @@ import (
 const (
 	// EnvGitHubToken is the environment variable for fallback token auth.
 	EnvGitHubToken = "STAGHORN_GITHUB_TOKEN"
+
+	// DefaultHost is the public GitHub host.
+	DefaultHost = "github.com"
 )
 
+// hostEnvPattern matches characters that can't appear in an environment variable name.
+var hostEnvPattern = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// IsDefaultHost returns true if host refers to github.com (empty means github.com).
+func IsDefaultHost(host string) bool {
+	return host == "" || strings.EqualFold(host, DefaultHost)
+}
+
+// EnvTokenForHost returns the token environment variable for a GitHub Enterprise
+// host, e.g. STAGHORN_GITHUB_TOKEN_GHE_EXAMPLE_COM for ghe.example.com.
+// github.com uses STAGHORN_GITHUB_TOKEN.
+func EnvTokenForHost(host string) string {
+	if IsDefaultHost(host) {
+		return EnvGitHubToken
+	}
+	return EnvGitHubToken + "_" + strings.Trim(hostEnvPattern.ReplaceAllString(strings.ToUpper(host), "_"), "_")
+}
+
 // GetToken resolves a GitHub token using the auth chain.
 // Priority: 1) gh auth token, 2) STAGHORN_GITHUB_TOKEN env
 func GetToken() (string, error) {
@@ -47,6 +69,12 @@ func GetTokenFromEnv() string {
 	return os.Getenv(EnvGitHubToken)
 }
 
+// GetTokenFromEnvForHost reads the token variable for host (see EnvTokenForHost).
+// The github.com token is never sent to other hosts.
+func GetTokenFromEnvForHost(host string) string {
+	return os.Getenv(EnvTokenForHost(host))
+}
+
 // IsGHCLIAvailable checks if the gh CLI is installed and authenticated.
 func IsGHCLIAvailable() bool {
 	_, err := GetTokenFromGHCLI()
@@ -3,9 +3,12 @@ package github
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
 )
@@ -31,9 +34,94 @@ type FetchResult struct {
 	NotModified bool // True if server returned 304
 }
 
+// etagKey is the context key carrying an ETag for a conditional request.
+type etagKey struct{}
+
+// conditionalTransport adds If-None-Match to requests whose context carries an ETag.
+// go-gh's RESTClient has no per-request headers, so the ETag travels in the context.
+type conditionalTransport struct {
+	base http.RoundTripper
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if etag, ok := req.Context().Value(etagKey{}).(string); ok && etag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", etag)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Retry limits for GitHub's secondary rate limits.
+const (
+	maxRateLimitRetries = 3
+	maxRetryAfter       = time.Minute
+)
+
+// rateLimitTransport retries requests rejected by GitHub's secondary rate limits,
+// which are signalled by a 403 or 429 carrying a Retry-After header.
+// Primary rate limit exhaustion has no Retry-After and is returned as-is.
+type rateLimitTransport struct {
+	base http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || attempt >= maxRateLimitRetries || req.Body != nil {
+			return resp, err
+		}
+
+		wait, ok := retryAfter(resp)
+		if !ok {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// retryAfter returns how long to wait before retrying a rate-limited response.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	wait := time.Duration(seconds) * time.Second
+	if wait > maxRetryAfter {
+		return 0, false
+	}
+	return wait, true
+}
+
+// newRESTClient creates a REST client that supports conditional requests and
+// backs off when GitHub's secondary rate limits are hit.
+func newRESTClient(opts api.ClientOptions) (*api.RESTClient, error) {
+	base := opts.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	opts.Transport = &conditionalTransport{base: &rateLimitTransport{base: base}}
+	return api.NewRESTClient(opts)
+}
+
 // NewClient creates a GitHub client using go-gh (automatic auth).
 func NewClient() (*Client, error) {
-	client, err := api.DefaultRESTClient()
+	return NewClientForHost("")
+}
+
+// NewClientForHost creates a client for a GitHub Enterprise Server host, using
+// the `gh auth` credentials for that host. An empty host means github.com
+// (or GH_HOST, if set).
+func NewClientForHost(host string) (*Client, error) {
+	client, err := newRESTClient(api.ClientOptions{Host: host})
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +130,13 @@ func NewClient() (*Client, error) {
 
 // NewClientWithToken creates a GitHub client with explicit token.
 func NewClientWithToken(token string) (*Client, error) {
-	client, err := api.NewRESTClient(api.ClientOptions{
+	return NewClientWithTokenForHost("", token)
+}
+
+// NewClientWithTokenForHost creates a client for host with an explicit token.
+func NewClientWithTokenForHost(host, token string) (*Client, error) {
+	client, err := newRESTClient(api.ClientOptions{
+		Host:      host,
 		AuthToken: token,
 	})
 	if err != nil {
@@ -55,7 +149,12 @@ func NewClientWithToken(token string) (*Client, error) {
 // This works for public repositories only and has lower rate limits (60/hour).
 // Use this when accessing public configs without requiring user auth.
 func NewUnauthenticatedClient() (*Client, error) {
-	client, err := api.NewRESTClient(api.ClientOptions{})
+	return NewUnauthenticatedClientForHost("")
+}
+
+// NewUnauthenticatedClientForHost creates a client for host without authentication.
+func NewUnauthenticatedClientForHost(host string) (*Client, error) {
+	client, err := newRESTClient(api.ClientOptions{Host: host})
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +179,13 @@ type fileContentsResponse struct {
 // FetchFile fetches a file from a repo.
 // The context is used for request cancellation and timeouts.
 func (c *Client) FetchFile(ctx context.Context, owner, repo, path, branch string) (*FetchResult, error) {
+	return c.FetchFileIfChanged(ctx, owner, repo, path, branch, "")
+}
+
+// FetchFileIfChanged fetches a file unless it still matches etag.
+// If the server returns 304 Not Modified, the result has NotModified set and no content.
+// An empty etag makes this an unconditional fetch.
+func (c *Client) FetchFileIfChanged(ctx context.Context, owner, repo, path, branch, etag string) (*FetchResult, error) {
 	if owner == "" || repo == "" || path == "" {
 		return nil, fmt.Errorf("owner, repo, and path are required")
 	}
@@ -89,11 +195,23 @@ func (c *Client) FetchFile(ctx context.Context, owner, repo, path, branch string
 		endpoint += "?ref=" + url.QueryEscape(branch)
 	}
 
-	var response fileContentsResponse
-	err := c.rest.DoWithContext(ctx, http.MethodGet, endpoint, nil, &response)
+	if etag != "" {
+		ctx = context.WithValue(ctx, etagKey{}, etag)
+	}
+
+	resp, err := c.rest.RequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
+		if httpErr, ok := err.(*api.HTTPError); ok && httpErr.StatusCode == http.StatusNotModified {
+			return &FetchResult{ETag: etag, NotModified: true}, nil
+		}
 		return nil, err
 	}
+	defer resp.Body.Close()
+
+	var response fileContentsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
 
 	// Decode base64 content
 	content, err := base64.StdEncoding.DecodeString(response.Content)
@@ -103,6 +221,7 @@ func (c *Client) FetchFile(ctx context.Context, owner, repo, path, branch string
 
 	return &FetchResult{
 		Content: string(content),
+		ETag:    resp.Header.Get("ETag"),
 		SHA:     response.SHA,
 	}, nil
 }
@@ -124,6 +243,70 @@ func (c *Client) GetDefaultBranch(ctx context.Context, owner, repo string) (stri
 	return response.DefaultBranch, nil
 }
 
+// ResolveCommit returns the commit SHA that a branch, tag, or SHA points to.
+// The context is used for request cancellation and timeouts.
+func (c *Client) ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error) {
+	endpoint := fmt.Sprintf("repos/%s/%s/commits/%s", owner, repo, url.PathEscape(ref))
+
+	var response struct {
+		SHA string `json:"sha"`
+	}
+
+	err := c.rest.DoWithContext(ctx, http.MethodGet, endpoint, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.SHA, nil
+}
+
+// Commit is a commit in a repo's history.
+type Commit struct {
+	SHA     string
+	Message string
+}
+
+// CompareCommits returns the commits reachable from head but not from base,
+// oldest first. GitHub returns at most 250 commits.
+// The context is used for request cancellation and timeouts.
+func (c *Client) CompareCommits(ctx context.Context, owner, repo, base, head string) ([]Commit, error) {
+	endpoint := fmt.Sprintf("repos/%s/%s/compare/%s...%s", owner, repo, url.PathEscape(base), url.PathEscape(head))
+
+	var response struct {
+		Commits []struct {
+			SHA    string `json:"sha"`
+			Commit struct {
+				Message string `json:"message"`
+			} `json:"commit"`
+		} `json:"commits"`
+	}
+
+	err := c.rest.DoWithContext(ctx, http.MethodGet, endpoint, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, len(response.Commits))
+	for i, rc := range response.Commits {
+		commits[i] = Commit{SHA: rc.SHA, Message: rc.Commit.Message}
+	}
+	return commits, nil
+}
+
+// GetTree returns the full recursive file tree of a repo at ref.
+// The context is used for request cancellation and timeouts.
+func (c *Client) GetTree(ctx context.Context, owner, repo, ref string) (*Tree, error) {
+	endpoint := fmt.Sprintf("repos/%s/%s/git/trees/%s?recursive=1", owner, repo, url.PathEscape(ref))
+
+	var tree Tree
+	err := c.rest.DoWithContext(ctx, http.MethodGet, endpoint, nil, &tree)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tree, nil
+}
+
 // RepoExists checks if a repository exists and is accessible.
 // The context is used for request cancellation and timeouts.
 func (c *Client) RepoExists(ctx context.Context, owner, repo string) (bool, error) {
@@ -0,0 +1,195 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTestClient returns a client whose requests are answered by handler.
+func newTestClient(t *testing.T, handler roundTripFunc) *Client {
+	t.Helper()
+	rest, err := newRESTClient(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "test-token",
+		Transport: handler,
+	})
+	require.NoError(t, err)
+	return &Client{rest: rest}
+}
+
+func jsonResponse(req *http.Request, status int, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestFetchFileIfChanged(t *testing.T) {
+	const etag = `"abc"`
+	content := base64.StdEncoding.EncodeToString([]byte("# Team"))
+
+	client := newTestClient(t, func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("If-None-Match") == etag {
+			return jsonResponse(req, http.StatusNotModified, "", nil), nil
+		}
+		header := http.Header{}
+		header.Set("ETag", etag)
+		return jsonResponse(req, http.StatusOK, `{"type":"file","content":"`+content+`","sha":"sha1"}`, header), nil
+	})
+
+	t.Run("unconditional fetch returns content and etag", func(t *testing.T) {
+		result, err := client.FetchFile(context.Background(), "acme", "standards", "CLAUDE.md", "main")
+		require.NoError(t, err)
+		assert.Equal(t, "# Team", result.Content)
+		assert.Equal(t, "sha1", result.SHA)
+		assert.Equal(t, etag, result.ETag)
+		assert.False(t, result.NotModified)
+	})
+
+	t.Run("matching etag returns not modified", func(t *testing.T) {
+		result, err := client.FetchFileIfChanged(context.Background(), "acme", "standards", "CLAUDE.md", "main", etag)
+		require.NoError(t, err)
+		assert.True(t, result.NotModified)
+		assert.Empty(t, result.Content)
+	})
+
+	t.Run("stale etag returns content", func(t *testing.T) {
+		result, err := client.FetchFileIfChanged(context.Background(), "acme", "standards", "CLAUDE.md", "main", `"old"`)
+		require.NoError(t, err)
+		assert.False(t, result.NotModified)
+		assert.Equal(t, "# Team", result.Content)
+	})
+}
+
+func TestGetTree(t *testing.T) {
+	var gotPath, gotQuery string
+	client := newTestClient(t, func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		gotQuery = req.URL.RawQuery
+		return jsonResponse(req, http.StatusOK, `{"sha":"c1","truncated":false,"tree":[{"path":"CLAUDE.md","type":"blob","sha":"a1","size":6}]}`, nil), nil
+	})
+
+	tree, err := client.GetTree(context.Background(), "acme", "standards", "c1")
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/acme/standards/git/trees/c1", gotPath)
+	assert.Equal(t, "recursive=1", gotQuery)
+	require.Len(t, tree.Entries, 1)
+	assert.Equal(t, "a1", tree.Entries[0].SHA)
+	assert.False(t, tree.Truncated)
+}
+
+func TestCompareCommits(t *testing.T) {
+	var gotPath string
+	client := newTestClient(t, func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		return jsonResponse(req, http.StatusOK, `{"commits":[{"sha":"c2","commit":{"message":"Add testing section"}},{"sha":"c3","commit":{"message":"Tighten review rules\n\nDetails"}}]}`, nil), nil
+	})
+
+	commits, err := client.CompareCommits(context.Background(), "acme", "standards", "c1", "c3")
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/acme/standards/compare/c1...c3", gotPath)
+	assert.Equal(t, []Commit{
+		{SHA: "c2", Message: "Add testing section"},
+		{SHA: "c3", Message: "Tighten review rules\n\nDetails"},
+	}, commits)
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	t.Run("retries secondary rate limit", func(t *testing.T) {
+		calls := 0
+		client := newTestClient(t, func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				header := http.Header{}
+				header.Set("Retry-After", "0")
+				return jsonResponse(req, http.StatusForbidden, `{"message":"secondary rate limit"}`, header), nil
+			}
+			return jsonResponse(req, http.StatusOK, `{"default_branch":"main"}`, nil), nil
+		})
+
+		branch, err := client.GetDefaultBranch(context.Background(), "acme", "standards")
+		require.NoError(t, err)
+		assert.Equal(t, "main", branch)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("does not retry primary rate limit", func(t *testing.T) {
+		calls := 0
+		client := newTestClient(t, func(req *http.Request) (*http.Response, error) {
+			calls++
+			header := http.Header{}
+			header.Set("X-RateLimit-Remaining", "0")
+			return jsonResponse(req, http.StatusForbidden, `{"message":"API rate limit exceeded"}`, header), nil
+		})
+
+		_, err := client.GetDefaultBranch(context.Background(), "acme", "standards")
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		calls := 0
+		client := newTestClient(t, func(req *http.Request) (*http.Response, error) {
+			calls++
+			header := http.Header{}
+			header.Set("Retry-After", "0")
+			return jsonResponse(req, http.StatusTooManyRequests, `{"message":"slow down"}`, header), nil
+		})
+
+		_, err := client.GetDefaultBranch(context.Background(), "acme", "standards")
+		require.Error(t, err)
+		assert.Equal(t, maxRateLimitRetries+1, calls)
+	})
+}
+
+func TestClientForHost(t *testing.T) {
+	var gotURL string
+	rest, err := newRESTClient(api.ClientOptions{
+		Host:      "ghe.example.com",
+		AuthToken: "test-token",
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			gotURL = req.URL.String()
+			return jsonResponse(req, http.StatusOK, `{"default_branch":"main"}`, nil), nil
+		}),
+	})
+	require.NoError(t, err)
+
+	client := &Client{rest: rest}
+	branch, err := client.GetDefaultBranch(context.Background(), "acme", "standards")
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch)
+	assert.Equal(t, "https://ghe.example.com/api/v3/repos/acme/standards", gotURL)
+}
+
+func TestEnvTokenForHost(t *testing.T) {
+	assert.Equal(t, EnvGitHubToken, EnvTokenForHost(""))
+	assert.Equal(t, EnvGitHubToken, EnvTokenForHost("GitHub.com"))
+	assert.Equal(t, "STAGHORN_GITHUB_TOKEN_GHE_EXAMPLE_COM", EnvTokenForHost("ghe.example.com"))
+	assert.Equal(t, "STAGHORN_GITHUB_TOKEN_GIT_CORP_8443", EnvTokenForHost("git.corp:8443"))
+
+	t.Setenv(EnvGitHubToken, "public-token")
+	assert.Empty(t, GetTokenFromEnvForHost("ghe.example.com"), "github.com token must not leak to other hosts")
+
+	t.Setenv("STAGHORN_GITHUB_TOKEN_GHE_EXAMPLE_COM", "ghe-token")
+	assert.Equal(t, "ghe-token", GetTokenFromEnvForHost("ghe.example.com"))
+}
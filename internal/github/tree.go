@@ -0,0 +1,76 @@
+package github
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Tree is a recursive listing of a repo at a single commit.
+type Tree struct {
+	SHA       string      `json:"sha"`
+	Entries   []TreeEntry `json:"tree"`
+	Truncated bool        `json:"truncated"` // True if GitHub omitted entries from a very large tree
+}
+
+// TreeEntry is a file or directory in a tree.
+type TreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"` // "blob" or "tree"
+	SHA  string `json:"sha"`
+	Size int    `json:"size"`
+}
+
+// Find returns the entry at p, or nil.
+func (t *Tree) Find(p string) *TreeEntry {
+	p = strings.Trim(p, "/")
+	for i := range t.Entries {
+		if t.Entries[i].Path == p {
+			return &t.Entries[i]
+		}
+	}
+	return nil
+}
+
+// List returns the direct children of dir in the same shape as ListDirectory.
+// Returns nil if dir doesn't exist.
+func (t *Tree) List(dir string) []DirectoryEntry {
+	dir = strings.Trim(dir, "/")
+	if dir != "" {
+		if entry := t.Find(dir); entry == nil || entry.Type != "tree" {
+			return nil
+		}
+	}
+
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+
+	entries := []DirectoryEntry{}
+	for _, e := range t.Entries {
+		rest, ok := strings.CutPrefix(e.Path, prefix)
+		if !ok || rest == "" || strings.Contains(rest, "/") {
+			continue
+		}
+
+		entryType := "file"
+		if e.Type == "tree" {
+			entryType = "dir"
+		} else if e.Type != "blob" {
+			continue // Skip submodules
+		}
+
+		entries = append(entries, DirectoryEntry{
+			Name: path.Base(e.Path),
+			Path: e.Path,
+			Type: entryType,
+			SHA:  e.SHA,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
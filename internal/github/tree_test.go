@@ -0,0 +1,63 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeList(t *testing.T) {
+	tree := &Tree{Entries: []TreeEntry{
+		{Path: "CLAUDE.md", Type: "blob", SHA: "a1"},
+		{Path: "commands", Type: "tree", SHA: "t1"},
+		{Path: "commands/review.md", Type: "blob", SHA: "b1"},
+		{Path: "commands/debug.md", Type: "blob", SHA: "b2"},
+		{Path: "rules", Type: "tree", SHA: "t2"},
+		{Path: "rules/security", Type: "tree", SHA: "t3"},
+		{Path: "rules/security/auth.md", Type: "blob", SHA: "c1"},
+		{Path: "vendor", Type: "commit", SHA: "s1"},
+	}}
+
+	t.Run("root", func(t *testing.T) {
+		entries := tree.List("")
+		require.Len(t, entries, 3)
+		assert.Equal(t, "CLAUDE.md", entries[0].Name)
+		assert.Equal(t, "file", entries[0].Type)
+		assert.Equal(t, "commands", entries[1].Name)
+		assert.Equal(t, "dir", entries[1].Type)
+	})
+
+	t.Run("subdirectory sorted by name", func(t *testing.T) {
+		entries := tree.List("commands")
+		require.Len(t, entries, 2)
+		assert.Equal(t, DirectoryEntry{Name: "debug.md", Path: "commands/debug.md", Type: "file", SHA: "b2"}, entries[0])
+		assert.Equal(t, "review.md", entries[1].Name)
+	})
+
+	t.Run("only direct children", func(t *testing.T) {
+		entries := tree.List("rules/")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "security", entries[0].Name)
+		assert.Equal(t, "dir", entries[0].Type)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		assert.Nil(t, tree.List("skills"))
+	})
+
+	t.Run("file is not a directory", func(t *testing.T) {
+		assert.Nil(t, tree.List("CLAUDE.md"))
+	})
+}
+
+func TestTreeFind(t *testing.T) {
+	tree := &Tree{Entries: []TreeEntry{
+		{Path: "commands/review.md", Type: "blob", SHA: "b1"},
+	}}
+
+	entry := tree.Find("commands/review.md")
+	require.NotNil(t, entry)
+	assert.Equal(t, "b1", entry.SHA)
+	assert.Nil(t, tree.Find("commands/missing.md"))
+}
@@ -40,6 +40,17 @@ func (a *Asserter) ProvenanceOrder() []string {
 	return merge.ListLayers(a.content)
 }
 
+// RepoOrder returns the repos named by provenance markers in order of appearance.
+func (a *Asserter) RepoOrder() []string {
+	var repos []string
+	for _, section := range merge.ParseProvenanceSections(a.content) {
+		if section.Repo != "" && !slices.Contains(repos, section.Repo) {
+			repos = append(repos, section.Repo)
+		}
+	}
+	return repos
+}
+
 // ContainsSection checks if a ## section header exists.
 func (a *Asserter) ContainsSection(header string) bool {
 	return strings.Contains(a.content, header)
@@ -91,6 +102,12 @@ func (a *Asserter) RunAssertions(assertions FixtureAssertions) {
 				a.t.Errorf("expected provenance order %v, got %v", assertions.Provenance.Order, actual)
 			}
 		}
+		if len(assertions.Provenance.Repos) > 0 {
+			actual := a.RepoOrder()
+			if !slices.Equal(assertions.Provenance.Repos, actual) {
+				a.t.Errorf("expected provenance repos %v, got %v", assertions.Provenance.Repos, actual)
+			}
+		}
 	}
 
 	// Check contains
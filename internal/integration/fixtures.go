@@ -58,6 +58,7 @@ type ConfigSetup struct {
 type SourceConfigSetup struct {
 	Default   string            `yaml:"default"`
 	Base      string            `yaml:"base,omitempty"`
+	Layers    []string          `yaml:"layers,omitempty"`
 	Languages map[string]string `yaml:"languages,omitempty"`
 	Commands  map[string]string `yaml:"commands,omitempty"`
 }
@@ -90,6 +91,7 @@ type ProvenanceCheck struct {
 	HasTeam     bool     `yaml:"has_team"`
 	HasPersonal bool     `yaml:"has_personal"`
 	Order       []string `yaml:"order"`
+	Repos       []string `yaml:"repos"`
 }
 
 // LanguageCheck verifies language section content.
@@ -184,6 +186,13 @@ func (c *ConfigSetup) ToConfig() *config.Config {
 		if base, ok := src["base"].(string); ok {
 			multi.Base = base
 		}
+		if layers, ok := src["layers"].([]interface{}); ok {
+			for _, v := range layers {
+				if vs, ok := v.(string); ok {
+					multi.Layers = append(multi.Layers, vs)
+				}
+			}
+		}
 		if langs, ok := src["languages"].(map[string]interface{}); ok {
 			multi.Languages = make(map[string]string)
 			for k, v := range langs {
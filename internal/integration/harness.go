@@ -266,19 +266,26 @@ func (e *TestEnv) RunSync(owner, repo string, cfg *config.Config) error {
 }
 
 // RunMultiSourceSync executes the merge for multi-source configurations.
-// It reads the base config from the base repo and languages from their respective repos.
+// It reads the base config from each base repo (stacking layers in order)
+// and languages from their respective repos.
 func (e *TestEnv) RunMultiSourceSync(cfg *config.Config) error {
-	// Get base repo
-	baseRepoStr := cfg.Source.RepoForBase()
-	baseOwner, baseRepo, err := config.ParseRepo(baseRepoStr)
-	if err != nil {
-		return err
-	}
-
-	// Read team config from base repo cache
-	teamConfig, err := os.ReadFile(e.Paths.CacheFile(baseOwner, baseRepo))
-	if err != nil {
-		return err
+	// Read team config from each base repo cache
+	baseRepos := cfg.Source.BaseRepos()
+	var layers []merge.Layer
+	for _, baseRepoStr := range baseRepos {
+		baseOwner, baseRepo, err := config.ParseRepo(baseRepoStr)
+		if err != nil {
+			return err
+		}
+		teamConfig, err := os.ReadFile(e.Paths.CacheFile(baseOwner, baseRepo))
+		if err != nil {
+			return err
+		}
+		layer := merge.Layer{Content: string(teamConfig), Source: "team"}
+		if len(baseRepos) > 1 {
+			layer.Repo = baseRepoStr
+		}
+		layers = append(layers, layer)
 	}
 
 	// Read personal config (optional)
@@ -350,10 +357,7 @@ func (e *TestEnv) RunMultiSourceSync(cfg *config.Config) error {
 	}
 
 	// Merge configs
-	layers := []merge.Layer{
-		{Content: string(teamConfig), Source: "team"},
-		{Content: string(personalConfig), Source: "personal"},
-	}
+	layers = append(layers, merge.Layer{Content: string(personalConfig), Source: "personal"})
 	mergeOpts := merge.MergeOptions{
 		AnnotateSources: true,
 		SourceRepo:      cfg.SourceRepo(),
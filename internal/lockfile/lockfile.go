@@ -0,0 +1,172 @@
+// Package lockfile records exactly which upstream files a sync installed.
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/HartBrook/staghorn/internal/fsutil"
+)
+
+// CurrentVersion is the lockfile format version written by this build.
+const CurrentVersion = 1
+
+// Lockfile lists every file fetched during a sync, grouped by source repo.
+type Lockfile struct {
+	Version int       `json:"version"`
+	Sources []*Source `json:"sources"`
+}
+
+// Source is a single repo pinned to a resolved commit.
+type Source struct {
+	Repo   string `json:"repo"`   // owner/repo
+	Ref    string `json:"ref"`    // Ref that was synced (pinned ref or default branch)
+	Commit string `json:"commit"` // Commit SHA the ref resolved to
+	Files  []File `json:"files"`
+}
+
+// File is a fetched file and its blob SHA.
+type File struct {
+	Path string `json:"path"`
+	SHA  string `json:"sha"`
+}
+
+// Load reads a lockfile from disk.
+// Returns an error wrapping os.ErrNotExist if the file doesn't exist.
+func Load(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock Lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile: %w", err)
+	}
+	if lock.Version > CurrentVersion {
+		return nil, fmt.Errorf("lockfile version %d is newer than supported version %d", lock.Version, CurrentVersion)
+	}
+
+	return &lock, nil
+}
+
+// Save writes the lockfile to disk with sources and files sorted for stable diffs.
+func (l *Lockfile) Save(path string) error {
+	l.Version = CurrentVersion
+	l.sort()
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// FindSource returns the locked source for a repo (case-insensitive), or nil.
+func (l *Lockfile) FindSource(repo string) *Source {
+	for _, s := range l.Sources {
+		if strings.EqualFold(s.Repo, repo) {
+			return s
+		}
+	}
+	return nil
+}
+
+// SetSource adds or replaces the locked entry for a repo.
+func (l *Lockfile) SetSource(src *Source) {
+	for i, s := range l.Sources {
+		if strings.EqualFold(s.Repo, src.Repo) {
+			l.Sources[i] = src
+			return
+		}
+	}
+	l.Sources = append(l.Sources, src)
+}
+
+// FindFile returns the locked file at path, or nil.
+func (s *Source) FindFile(path string) *File {
+	for i := range s.Files {
+		if s.Files[i].Path == path {
+			return &s.Files[i]
+		}
+	}
+	return nil
+}
+
+// Diff compares fetched files against the locked files and describes any that
+// are missing from the lockfile or have a different SHA.
+func (s *Source) Diff(fetched []File) []string {
+	var diffs []string
+	for _, f := range fetched {
+		locked := s.FindFile(f.Path)
+		switch {
+		case locked == nil:
+			diffs = append(diffs, fmt.Sprintf("%s not in lockfile", f.Path))
+		case locked.SHA != f.SHA:
+			diffs = append(diffs, fmt.Sprintf("%s changed (locked %s, got %s)", f.Path, ShortSHA(locked.SHA), ShortSHA(f.SHA)))
+		}
+	}
+	return diffs
+}
+
+// ShortSHA abbreviates a commit or file SHA for display.
+func ShortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+func (l *Lockfile) sort() {
+	sort.Slice(l.Sources, func(i, j int) bool {
+		return l.Sources[i].Repo < l.Sources[j].Repo
+	})
+	for _, s := range l.Sources {
+		sort.Slice(s.Files, func(i, j int) bool {
+			return s.Files[i].Path < s.Files[j].Path
+		})
+	}
+}
+
+// Recorder collects fetched files for one source during a sync.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	files map[string]string
+}
+
+// NewRecorder creates an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{files: make(map[string]string)}
+}
+
+// Record notes that path was fetched with the given blob SHA.
+func (r *Recorder) Record(path, sha string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files[path] = sha
+}
+
+// Files returns the recorded files sorted by path.
+func (r *Recorder) Files() []File {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	files := make([]File, 0, len(r.files))
+	for path, sha := range r.files {
+		files = append(files, File{Path: path, SHA: sha})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
@@ -0,0 +1,105 @@
+package lockfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "staghorn.lock")
+
+	lock := &Lockfile{
+		Sources: []*Source{
+			{
+				Repo:   "acme/standards",
+				Ref:    "main",
+				Commit: "abc123",
+				Files: []File{
+					{Path: "rules/security.md", SHA: "sha2"},
+					{Path: "CLAUDE.md", SHA: "sha1"},
+				},
+			},
+			{Repo: "acme/base", Ref: "v1.0.0", Commit: "def456"},
+		},
+	}
+
+	require.NoError(t, lock.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, CurrentVersion, loaded.Version)
+	require.Len(t, loaded.Sources, 2)
+	// Sources and files are sorted for stable diffs
+	assert.Equal(t, "acme/base", loaded.Sources[0].Repo)
+	assert.Equal(t, "acme/standards", loaded.Sources[1].Repo)
+	assert.Equal(t, "CLAUDE.md", loaded.Sources[1].Files[0].Path)
+	assert.Equal(t, "rules/security.md", loaded.Sources[1].Files[1].Path)
+}
+
+func TestLoad_NotFound(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.lock"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLoad_NewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "staghorn.lock")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 99, "sources": []}`), 0644))
+
+	_, err := Load(path)
+	assert.Error(t, err)
+}
+
+func TestFindSourceAndSetSource(t *testing.T) {
+	lock := &Lockfile{}
+	lock.SetSource(&Source{Repo: "acme/standards", Commit: "one"})
+	lock.SetSource(&Source{Repo: "Acme/Standards", Commit: "two"})
+
+	require.Len(t, lock.Sources, 1)
+	src := lock.FindSource("ACME/standards")
+	require.NotNil(t, src)
+	assert.Equal(t, "two", src.Commit)
+	assert.Nil(t, lock.FindSource("other/repo"))
+}
+
+func TestSourceDiff(t *testing.T) {
+	src := &Source{
+		Repo: "acme/standards",
+		Files: []File{
+			{Path: "CLAUDE.md", SHA: "aaaaaaaaaaaa"},
+			{Path: "commands/review.md", SHA: "bbbbbbbbbbbb"},
+		},
+	}
+
+	assert.Empty(t, src.Diff([]File{{Path: "CLAUDE.md", SHA: "aaaaaaaaaaaa"}}))
+
+	diffs := src.Diff([]File{
+		{Path: "CLAUDE.md", SHA: "cccccccccccc"},
+		{Path: "commands/new.md", SHA: "dddd"},
+	})
+	require.Len(t, diffs, 2)
+	assert.Contains(t, diffs[0], "CLAUDE.md changed (locked aaaaaaaa, got cccccccc)")
+	assert.Contains(t, diffs[1], "commands/new.md not in lockfile")
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	r.Record("skills/b/SKILL.md", "sha-b")
+	r.Record("CLAUDE.md", "sha-a")
+	r.Record("CLAUDE.md", "sha-a2") // Later fetch of the same path wins
+
+	files := r.Files()
+	require.Len(t, files, 2)
+	assert.Equal(t, File{Path: "CLAUDE.md", SHA: "sha-a2"}, files[0])
+	assert.Equal(t, File{Path: "skills/b/SKILL.md", SHA: "sha-b"}, files[1])
+}
+
+func TestShortSHA(t *testing.T) {
+	assert.Equal(t, "abcdef12", ShortSHA("abcdef1234567890"))
+	assert.Equal(t, "abc123", ShortSHA("abc123"))
+	assert.Equal(t, "", ShortSHA(""))
+}
@@ -0,0 +1,149 @@
+// Package manifest tracks which files staghorn installed into a directory so
+// files removed upstream can be pruned on the next sync.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/HartBrook/staghorn/internal/fsutil"
+)
+
+// FileName is the manifest stored in each tracked directory.
+const FileName = ".staghorn-manifest.json"
+
+// Manifest lists the files installed into a directory, relative to it.
+type Manifest struct {
+	Files []string `json:"files"`
+}
+
+// Load reads the manifest for dir.
+// Returns nil, nil if the directory has no manifest yet.
+func Load(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest in %s: %w", dir, err)
+	}
+	return &m, nil
+}
+
+// Save writes the manifest into dir with files sorted for stable diffs.
+func (m *Manifest) Save(dir string) error {
+	sort.Strings(m.Files)
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(filepath.Join(dir, FileName), append(data, '\n'), 0644)
+}
+
+// Scan lists every file under dir (excluding the manifest) as slash-separated
+// relative paths. Used to seed a manifest for directories staghorn owns outright.
+func Scan(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || d.Name() == FileName {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Stale returns the files in previous that are not in current, sorted.
+func Stale(previous, current []string) []string {
+	keep := make(map[string]bool, len(current))
+	for _, f := range current {
+		keep[f] = true
+	}
+
+	var stale []string
+	for _, f := range previous {
+		if !keep[f] {
+			stale = append(stale, f)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// Remove deletes the given relative files from dir, then removes any parent
+// directories left empty (never dir itself). Missing files are ignored.
+// Absolute paths and paths with ".." are rejected, so a tampered manifest
+// can't reach outside dir. Returns the files that were actually removed.
+func Remove(dir string, files []string) ([]string, error) {
+	var removed []string
+	for _, f := range files {
+		if !isLocal(f) {
+			return removed, fmt.Errorf("refusing to remove %q: not a path inside %s", f, dir)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return removed, err
+		}
+		removed = append(removed, f)
+		removeEmptyParents(dir, filepath.Dir(path))
+	}
+	return removed, nil
+}
+
+// isLocal reports whether f is a relative slash-separated path that stays
+// within its directory: not empty, not absolute, and without ".." elements.
+func isLocal(f string) bool {
+	if f == "" || path.IsAbs(f) || filepath.IsAbs(filepath.FromSlash(f)) {
+		return false
+	}
+	for _, elem := range strings.Split(f, "/") {
+		if elem == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// removeEmptyParents removes empty directories from start up to (not including) root.
+func removeEmptyParents(root, start string) {
+	root = filepath.Clean(root)
+	for dir := filepath.Clean(start); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			return // Not empty or not removable
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, rel string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(rel), 0644))
+}
+
+func TestLoadSave(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := Load(dir)
+	require.NoError(t, err)
+	assert.Nil(t, m, "missing manifest should load as nil")
+
+	require.NoError(t, (&Manifest{Files: []string{"b.md", "a.md"}}).Save(dir))
+
+	m, err = Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.md", "b.md"}, m.Files)
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.md")
+	writeFile(t, dir, "nested/b.md")
+	require.NoError(t, (&Manifest{}).Save(dir))
+
+	files, err := Scan(dir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a.md", "nested/b.md"}, files)
+
+	files, err = Scan(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestStale(t *testing.T) {
+	stale := Stale([]string{"c.md", "a.md", "b.md"}, []string{"b.md", "d.md"})
+	assert.Equal(t, []string{"a.md", "c.md"}, stale)
+}
+
+func TestRemove(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "keep.md")
+	writeFile(t, dir, "security/auth.md")
+	writeFile(t, dir, "api/rest.md")
+	writeFile(t, dir, "api/graphql.md")
+
+	removed, err := Remove(dir, []string{"security/auth.md", "api/rest.md", "missing.md"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"security/auth.md", "api/rest.md"}, removed)
+
+	assert.NoDirExists(t, filepath.Join(dir, "security"), "emptied directory should be removed")
+	assert.FileExists(t, filepath.Join(dir, "api", "graphql.md"))
+	assert.FileExists(t, filepath.Join(dir, "keep.md"))
+	assert.DirExists(t, dir)
+}
+
+func TestRemove_RejectsPathsOutsideDir(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "commands")
+	writeFile(t, parent, "outside.md")
+	writeFile(t, dir, "inside.md")
+
+	for _, f := range []string{"../outside.md", "sub/../../outside.md", filepath.ToSlash(filepath.Join(parent, "outside.md")), ""} {
+		_, err := Remove(dir, []string{f})
+		assert.Error(t, err, f)
+	}
+	assert.FileExists(t, filepath.Join(parent, "outside.md"))
+	assert.FileExists(t, filepath.Join(dir, "inside.md"))
+}
@@ -0,0 +1,43 @@
+package merge
+
+import "strings"
+
+// SectionChanges summarizes how the H2 sections of a document changed between two versions.
+type SectionChanges struct {
+	Added    []string // Headers of new sections, in document order
+	Removed  []string // Headers of sections that no longer exist
+	Changed  []string // Headers of sections whose content changed
+	Preamble bool     // Content before the first H2 changed
+}
+
+// IsEmpty reports whether no section changed.
+func (c SectionChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0 && !c.Preamble
+}
+
+// DiffSections compares two versions of a document section by section.
+// Sections are matched by header (case-insensitive), so reordering alone isn't a change.
+func DiffSections(before, after string) SectionChanges {
+	oldDoc := Parse(before)
+	newDoc := Parse(after)
+
+	var changes SectionChanges
+	changes.Preamble = oldDoc.Preamble != newDoc.Preamble
+
+	for _, s := range newDoc.Sections {
+		old := oldDoc.FindSection(s.Header)
+		switch {
+		case old == nil:
+			changes.Added = append(changes.Added, s.Header)
+		case strings.TrimSpace(old.Content) != strings.TrimSpace(s.Content):
+			changes.Changed = append(changes.Changed, s.Header)
+		}
+	}
+	for _, s := range oldDoc.Sections {
+		if !newDoc.HasSection(s.Header) {
+			changes.Removed = append(changes.Removed, s.Header)
+		}
+	}
+
+	return changes
+}
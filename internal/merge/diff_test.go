@@ -0,0 +1,46 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSections(t *testing.T) {
+	before := `# Team
+
+## Code Style
+Use gofmt.
+
+## Review
+Two approvals.
+
+## Legacy
+Old rules.
+`
+	after := `# Team Standards
+
+## review
+Two approvals.
+
+## Code Style
+Use gofmt and go vet.
+
+## Security
+No secrets in code.
+`
+
+	got := DiffSections(before, after)
+	want := SectionChanges{
+		Added:    []string{"Security"},
+		Removed:  []string{"Legacy"},
+		Changed:  []string{"Code Style"},
+		Preamble: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffSections() = %+v, want %+v", got, want)
+	}
+
+	if !DiffSections(before, before).IsEmpty() {
+		t.Error("DiffSections() of identical documents should be empty")
+	}
+}
@@ -0,0 +1,96 @@
+package merge
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Override actions recorded when a layer's directive targets an existing section.
+const (
+	OverrideReplaced = "replaced" // Section content was replaced by a later layer
+	OverrideRemoved  = "removed"  // Section was dropped by a later layer
+	OverrideBlocked  = "blocked"  // Directive was ignored because the section is locked
+)
+
+// Override records a directive that changed, or tried to change, an existing section.
+type Override struct {
+	Section string // Header of the targeted section
+	Action  string // OverrideReplaced, OverrideRemoved, or OverrideBlocked
+	Source  string // Layer that issued the directive (personal, project, ...)
+}
+
+// Directive comments, written on their own line:
+//   - <!-- staghorn:replace -->          under a header: replace that section instead of appending
+//   - <!-- staghorn:remove "Name" -->    anywhere: drop the named section
+//   - <!-- staghorn:locked -->           under a header: later layers can't replace or remove it
+var (
+	replaceDirectiveRegex = regexp.MustCompile(`(?m)^[ \t]*<!--\s*staghorn:replace\s*-->[ \t]*\n?`)
+	removeDirectiveRegex  = regexp.MustCompile(`(?m)^[ \t]*<!--\s*staghorn:remove\s+("(?:[^"\\]|\\.)*")\s*-->[ \t]*\n?`)
+	lockedDirectiveRegex  = regexp.MustCompile(`(?m)^[ \t]*<!--\s*staghorn:locked\s*-->[ \t]*\n?`)
+)
+
+// parseLayer parses a layer's content and extracts its directives.
+// Directive comments are stripped so they never reach the merged output;
+// ones inside code blocks are examples and are left alone. A replace or
+// locked directive under a subsection heading is kept for mergeSubsections.
+func parseLayer(content string) *Document {
+	doc := Parse(content)
+	doc.Preamble = trimBlankLines(extractRemovals(doc, doc.Preamble))
+
+	for i := range doc.Sections {
+		section := &doc.Sections[i]
+		body := extractRemovals(doc, section.Content)
+		intro, rest := splitIntro(body, 3)
+
+		if hasDirective(replaceDirectiveRegex, intro) {
+			section.Replace = true
+			intro = replaceOutsideCode(replaceDirectiveRegex, intro, "")
+		}
+		if hasDirective(lockedDirectiveRegex, intro) {
+			section.Locked = true
+			intro = replaceOutsideCode(lockedDirectiveRegex, intro, "")
+		}
+		section.Content = trimBlankLines(intro + rest)
+	}
+
+	return doc
+}
+
+// hasDirective reports whether content has a directive outside code blocks.
+func hasDirective(re *regexp.Regexp, content string) bool {
+	return len(findOutsideCode(re, content)) > 0
+}
+
+// extractRemovals records staghorn:remove directives in content on doc and
+// returns content with them stripped.
+func extractRemovals(doc *Document, content string) string {
+	for _, match := range findOutsideCode(removeDirectiveRegex, content) {
+		quoted := content[match[2]:match[3]]
+		name, err := strconv.Unquote(quoted)
+		if err != nil {
+			name = strings.Trim(quoted, `"`)
+		}
+		if name = strings.TrimSpace(name); name != "" {
+			doc.Removals = append(doc.Removals, name)
+		}
+	}
+	return replaceOutsideCode(removeDirectiveRegex, content, "")
+}
+
+// removeSection drops a section from the document by header (case-insensitive).
+func (d *Document) removeSection(header string) {
+	headerLower := strings.ToLower(header)
+	for i := range d.Sections {
+		if strings.ToLower(d.Sections[i].Header) == headerLower {
+			d.Sections = append(d.Sections[:i], d.Sections[i+1:]...)
+			return
+		}
+	}
+}
+
+// overrideComment returns the marker recording an override in the merged output.
+func overrideComment(o Override) string {
+	return fmt.Sprintf("<!-- staghorn:override:%s %s by=%s -->", o.Action, strconv.Quote(o.Section), o.Source)
+}
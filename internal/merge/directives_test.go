@@ -0,0 +1,168 @@
+package merge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLayerDirectives(t *testing.T) {
+	content := `<!-- staghorn:remove "Security" -->
+Intro.
+
+## Code Style
+<!-- staghorn:replace -->
+
+Tabs, not spaces.
+
+## Testing
+
+<!-- staghorn:remove "Deployment \"Prod\"" -->
+Write tests.
+
+## Compliance
+<!-- staghorn:locked -->
+
+Follow SOC 2.`
+
+	doc := parseLayer(content)
+
+	if doc.Preamble != "Intro." {
+		t.Errorf("Preamble = %q, want directives stripped", doc.Preamble)
+	}
+
+	wantRemovals := []string{"Security", `Deployment "Prod"`}
+	if len(doc.Removals) != len(wantRemovals) {
+		t.Fatalf("Removals = %q, want %q", doc.Removals, wantRemovals)
+	}
+	for i, want := range wantRemovals {
+		if doc.Removals[i] != want {
+			t.Errorf("Removals[%d] = %q, want %q", i, doc.Removals[i], want)
+		}
+	}
+
+	if !doc.Sections[0].Replace || doc.Sections[0].Content != "Tabs, not spaces." {
+		t.Errorf("Code Style = %+v, want Replace with directive stripped", doc.Sections[0])
+	}
+	if doc.Sections[1].Replace || doc.Sections[1].Content != "Write tests." {
+		t.Errorf("Testing = %+v, want plain section with directive stripped", doc.Sections[1])
+	}
+	if !doc.Sections[2].Locked || doc.Sections[2].Content != "Follow SOC 2." {
+		t.Errorf("Compliance = %+v, want Locked with directive stripped", doc.Sections[2])
+	}
+}
+
+func TestMergeDirectives(t *testing.T) {
+	team := `## Code Style
+
+Use spaces.
+
+## Security
+
+Never commit secrets.
+
+## Compliance
+<!-- staghorn:locked -->
+
+Follow SOC 2.`
+
+	tests := []struct {
+		name          string
+		personal      string
+		contains      []string
+		notContains   []string
+		wantOverrides []Override
+	}{
+		{
+			name:        "replace",
+			personal:    "## Code Style\n<!-- staghorn:replace -->\n\nUse tabs.",
+			contains:    []string{"## Code Style\n\nUse tabs."},
+			notContains: []string{"Use spaces.", "Personal Additions", "staghorn:replace"},
+			wantOverrides: []Override{
+				{Section: "Code Style", Action: OverrideReplaced, Source: "personal"},
+			},
+		},
+		{
+			name:        "remove",
+			personal:    `<!-- staghorn:remove "security" -->`,
+			contains:    []string{"## Code Style", "## Compliance"},
+			notContains: []string{"## Security", "Never commit secrets.", "staghorn:remove"},
+			wantOverrides: []Override{
+				{Section: "Security", Action: OverrideRemoved, Source: "personal"},
+			},
+		},
+		{
+			name:        "remove missing section is ignored",
+			personal:    `<!-- staghorn:remove "Nonexistent" -->`,
+			contains:    []string{"## Security"},
+			notContains: []string{"staghorn:override"},
+		},
+		{
+			name:        "locked section can't be removed",
+			personal:    `<!-- staghorn:remove "Compliance" -->`,
+			contains:    []string{"## Compliance", "Follow SOC 2."},
+			notContains: []string{"staghorn:locked"},
+			wantOverrides: []Override{
+				{Section: "Compliance", Action: OverrideBlocked, Source: "personal"},
+			},
+		},
+		{
+			name:        "locked section falls back to append",
+			personal:    "## Compliance\n<!-- staghorn:replace -->\n\nSkip audits.",
+			contains:    []string{"Follow SOC 2.", "### Personal Additions\n\nSkip audits."},
+			notContains: []string{"staghorn:replace"},
+			wantOverrides: []Override{
+				{Section: "Compliance", Action: OverrideBlocked, Source: "personal"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layers := []Layer{
+				{Content: team, Source: "team"},
+				{Content: tt.personal, Source: "personal"},
+			}
+			result := Merge(layers, MergeOptions{AnnotateSources: true})
+
+			for _, want := range tt.contains {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected %q in result:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(result, unwanted) {
+					t.Errorf("did not expect %q in result:\n%s", unwanted, result)
+				}
+			}
+
+			overrides := ParseOverrides(result)
+			if len(overrides) != len(tt.wantOverrides) {
+				t.Fatalf("ParseOverrides() = %+v, want %+v", overrides, tt.wantOverrides)
+			}
+			for i, want := range tt.wantOverrides {
+				if overrides[i] != want {
+					t.Errorf("override %d = %+v, want %+v", i, overrides[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeReplacedSectionProvenance(t *testing.T) {
+	layers := []Layer{
+		{Content: "## Code Style\n\nUse spaces.\n\n## Testing\n\nWrite tests.", Source: "team"},
+		{Content: "## Code Style\n<!-- staghorn:replace -->\nUse tabs.", Source: "personal"},
+	}
+	result := Merge(layers, MergeOptions{AnnotateSources: true})
+
+	byLayer := ParseProvenanceByLayer(result)
+	if !strings.Contains(byLayer["personal"], "Use tabs.") {
+		t.Errorf("replaced section should be attributed to personal, got %q", byLayer["personal"])
+	}
+	if strings.Contains(byLayer["team"], "Use tabs.") || !strings.Contains(byLayer["team"], "Write tests.") {
+		t.Errorf("team should keep only its own sections, got %q", byLayer["team"])
+	}
+	if strings.Contains(byLayer["team"]+byLayer["personal"], "staghorn:override") {
+		t.Error("override markers should not be attributed to any layer")
+	}
+}
@@ -0,0 +1,191 @@
+package merge
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// heading is an ATX (## Title) or setext (Title / ---) heading found by scan.
+type heading struct {
+	level  int
+	text   string
+	start  int  // Offset of the heading's first line
+	end    int  // Offset just past the heading's last line
+	setext bool // Underlined rather than #-prefixed
+}
+
+// span is a byte range of content.
+type span struct {
+	start, end int
+}
+
+// outline is the block structure of a markdown document that matters for
+// merging: its headings, and the code blocks whose text must be left alone.
+type outline struct {
+	headings []heading
+	code     []span // Fenced, indented, and raw HTML code blocks
+}
+
+// mdParser is the CommonMark block parser behind scan.
+var mdParser = goldmark.DefaultParser()
+
+// scan parses content as CommonMark and returns the top-level headings and
+// the code blocks. Headings nested in list items or block quotes aren't
+// section boundaries, and a "## " line inside a code block or an HTML block
+// isn't a heading at all. Offsets index into content, so callers can slice it
+// byte for byte.
+func scan(content string) *outline {
+	src := []byte(content)
+	doc := mdParser.Parse(text.NewReader(src))
+	o := &outline{}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Heading:
+			if n.Parent() == doc {
+				o.headings = append(o.headings, headingAt(content, n))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.FencedCodeBlock:
+			// The closing fence holds nothing but the fence, so the block
+			// runs from the opening fence to its last line of code.
+			o.code = append(o.code, span{lineStart(content, n.Pos()), linesEnd(content, n.Lines(), n.Pos())})
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeBlock:
+			if n.Lines().Len() > 0 {
+				first := n.Lines().At(0).Start
+				o.code = append(o.code, span{lineStart(content, first), linesEnd(content, n.Lines(), first)})
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock:
+			// Raw blocks (<pre>, <script>, ...) are literal text. Comments
+			// and other HTML stay outside code: that's where directives
+			// and provenance markers live.
+			if n.HTMLBlockType == ast.HTMLBlockType1 {
+				end := linesEnd(content, n.Lines(), n.Pos())
+				if n.HasClosure() {
+					end = lineEnd(content, n.ClosureLine.Start)
+				}
+				o.code = append(o.code, span{lineStart(content, n.Pos()), end})
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return o
+}
+
+// headingAt returns the heading n, spanning from the start of its first line
+// to the end of its last: the heading line for ATX, the underline for setext.
+func headingAt(content string, n *ast.Heading) heading {
+	lines := n.Lines()
+	parts := make([]string, 0, lines.Len())
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		parts = append(parts, strings.TrimSpace(content[seg.Start:seg.Stop]))
+	}
+
+	h := heading{level: n.Level, text: strings.Join(parts, " "), start: lineStart(content, n.Pos())}
+	// An ATX heading starts at its #s, before its text; a setext one at its text
+	h.setext = lines.Len() > 0 && lines.At(0).Start == n.Pos()
+	h.end = lineEnd(content, n.Pos())
+	if h.setext {
+		// The underline is the line after the text
+		h.end = lineEnd(content, lineEnd(content, lines.At(lines.Len()-1).Start))
+	}
+	return h
+}
+
+// lineStart returns the offset of the start of the line holding offset.
+func lineStart(content string, offset int) int {
+	return strings.LastIndexByte(content[:offset], '\n') + 1
+}
+
+// lineEnd returns the offset just past the line holding offset.
+func lineEnd(content string, offset int) int {
+	if offset >= len(content) {
+		return len(content)
+	}
+	if i := strings.IndexByte(content[offset:], '\n'); i >= 0 {
+		return offset + i + 1
+	}
+	return len(content)
+}
+
+// linesEnd returns the offset just past the last of lines, or past the line
+// holding from when there are none.
+func linesEnd(content string, lines *text.Segments, from int) int {
+	if lines.Len() > 0 {
+		from = lines.At(lines.Len() - 1).Start
+	}
+	return lineEnd(content, from)
+}
+
+// inCode reports whether offset falls inside a code block.
+func (o *outline) inCode(offset int) bool {
+	for _, s := range o.code {
+		if offset >= s.start && offset < s.end {
+			return true
+		}
+	}
+	return false
+}
+
+// findOutsideCode returns the matches of re in content that don't start
+// inside a code block, so examples of directives and markers are left alone.
+func findOutsideCode(re *regexp.Regexp, content string) [][]int {
+	matches := re.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	o := scan(content)
+	kept := matches[:0]
+	for _, m := range matches {
+		if !o.inCode(m[0]) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// replaceOutsideCode replaces the matches of re outside code blocks with repl.
+func replaceOutsideCode(re *regexp.Regexp, content, repl string) string {
+	matches := findOutsideCode(re, content)
+	if len(matches) == 0 {
+		return content
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(content[last:m[0]])
+		b.WriteString(repl)
+		last = m[1]
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// trimBlankLines drops the blank lines before content and the whitespace after
+// it, keeping the indentation of its first line (an indented code block, say).
+func trimBlankLines(s string) string {
+	s = strings.TrimRight(s, " \t\r\n")
+	for {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 || strings.TrimSpace(s[:i]) != "" {
+			break
+		}
+		s = s[i+1:]
+	}
+	if strings.TrimSpace(s) == "" {
+		return ""
+	}
+	return s
+}
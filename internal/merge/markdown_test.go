@@ -0,0 +1,175 @@
+package merge
+
+import (
+	"testing"
+)
+
+func TestScanHeadings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string // "level text" for each heading
+	}{
+		{
+			name:    "ATX headings",
+			content: "# Title\n\n## Section ##\n\n### Sub",
+			want:    []string{"1 Title", "2 Section", "3 Sub"},
+		},
+		{
+			name:    "fenced code is skipped",
+			content: "## Real\n\n```bash\n## not a heading\n```\n\n~~~\n# nor this\n~~~",
+			want:    []string{"2 Real"},
+		},
+		{
+			name:    "longer fence needs a longer close",
+			content: "````\n```\n## inside\n````\n## After",
+			want:    []string{"2 After"},
+		},
+		{
+			name:    "indented code is skipped",
+			content: "Text.\n\n    ## indented\n\n## Real",
+			want:    []string{"2 Real"},
+		},
+		{
+			name:    "HTML comment is skipped",
+			content: "<!--\n## commented out\n-->\n## Real",
+			want:    []string{"2 Real"},
+		},
+		{
+			name:    "setext headings",
+			content: "Title\n=====\n\nSection\n-------\n\nText.",
+			want:    []string{"1 Title", "2 Section"},
+		},
+		{
+			name:    "thematic break after a blank line",
+			content: "Text.\n\n---\n\n## Real",
+			want:    []string{"2 Real"},
+		},
+		{
+			name:    "list item isn't a setext heading",
+			content: "- item\n---",
+			want:    nil,
+		},
+		{
+			name:    "unclosed fence in a list item ends with the item",
+			content: "## A\n\n- item\n  ```\n  code\n\n## B\n",
+			want:    []string{"2 A", "2 B"},
+		},
+		{
+			name:    "fence in a list item is skipped",
+			content: "- item\n\n  ```\n  ## inside\n  ```\n\n## Real",
+			want:    []string{"2 Real"},
+		},
+		{
+			name:    "fence in a block quote is skipped",
+			content: "> ```\n> ## inside\n> ```\n\n## Real",
+			want:    []string{"2 Real"},
+		},
+		{
+			name:    "unclosed fence in a block quote ends with the quote",
+			content: "> ```\n> code\n\n## Real",
+			want:    []string{"2 Real"},
+		},
+		{
+			name:    "headings in containers aren't sections",
+			content: "- item\n  ## in a list\n\n> ## in a quote\n\n## Real",
+			want:    []string{"2 Real"},
+		},
+		{
+			name:    "raw HTML block is skipped",
+			content: "<pre>\n## preformatted\n</pre>\n\n## Real",
+			want:    []string{"2 Real"},
+		},
+		{
+			name:    "hash without a space",
+			content: "#hashtag\n\n## Real",
+			want:    []string{"2 Real"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, h := range scan(tt.content).headings {
+				got = append(got, string(rune('0'+h.level))+" "+h.text)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("headings = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("headings[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParse_CodeFences(t *testing.T) {
+	content := "## Shell\n\nRun:\n\n```markdown\n## Not a section\n<!-- staghorn:replace -->\n```\n\n## Next\n\nMore."
+
+	doc := parseLayer(content)
+
+	if len(doc.Sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(doc.Sections))
+	}
+	want := "Run:\n\n```markdown\n## Not a section\n<!-- staghorn:replace -->\n```"
+	if doc.Sections[0].Content != want {
+		t.Errorf("Shell content = %q, want %q", doc.Sections[0].Content, want)
+	}
+	if doc.Sections[0].Replace {
+		t.Error("Directive inside a code fence should be ignored")
+	}
+}
+
+func TestParse_ContainerCodeFences(t *testing.T) {
+	content := "## Quoted\n\n> ```markdown\n> <!-- staghorn:replace -->\n> ```\n\n## Listed\n\n- Run:\n  ```\n  ## Not a section\n"
+
+	doc := parseLayer(content)
+
+	if len(doc.Sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(doc.Sections))
+	}
+	if doc.Sections[0].Replace {
+		t.Error("Directive inside a quoted code fence should be ignored")
+	}
+	want := "- Run:\n  ```\n  ## Not a section"
+	if doc.Sections[1].Content != want {
+		t.Errorf("Listed content = %q, want %q", doc.Sections[1].Content, want)
+	}
+}
+
+func TestParse_ByteExactContent(t *testing.T) {
+	body := "    indented code\n    stays put\n\nText  \nwith a hard break."
+	doc := Parse("## Code\n\n" + body + "\n\n\n")
+
+	if doc.Sections[0].Content != body {
+		t.Errorf("Content = %q, want %q", doc.Sections[0].Content, body)
+	}
+}
+
+func TestParse_Title(t *testing.T) {
+	doc := Parse("# Guidelines\n\nIntro.\n\n## Style\n\nTabs.\n\n# Appendix\n\nNotes.")
+
+	if doc.Title != "Guidelines" {
+		t.Errorf("Title = %q, want %q", doc.Title, "Guidelines")
+	}
+	if len(doc.Sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(doc.Sections))
+	}
+	if doc.Sections[1].Header != "Appendix" || doc.Sections[1].Level != 1 {
+		t.Errorf("Sections[1] = %+v, want H1 Appendix", doc.Sections[1])
+	}
+	if doc.Sections[0].Content != "Tabs." {
+		t.Errorf("Style content = %q, want H1 to end the section", doc.Sections[0].Content)
+	}
+}
+
+func TestDemoteHeaders_SkipsCode(t *testing.T) {
+	input := "Setext\n------\n\n```\n## code\n```\n\n### Real\n\n#tag\n---"
+	want := "### Setext\n\n```\n## code\n```\n\n#### Real\n\n### #tag"
+
+	if got := demoteHeaders(input); got != want {
+		t.Errorf("demoteHeaders() = %q, want %q", got, want)
+	}
+}
@@ -2,7 +2,6 @@ package merge
 
 import (
 	"fmt"
-	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -14,6 +13,7 @@ import (
 type Layer struct {
 	Content string
 	Source  string // "team" | "personal" | "project"
+	Repo    string // Source repo, set when several team layers are stacked (e.g., "acme/standards")
 }
 
 // MergeOptions controls merge behavior.
@@ -22,10 +22,11 @@ type MergeOptions struct {
 	SourceRepo      string                              // For header annotation (e.g., "acme/standards")
 	Languages       []string                            // Active languages to include
 	LanguageFiles   map[string][]*language.LanguageFile // Language files by language ID
+	OmitHeader      bool                                // Leave out the managed header, for callers that write their own
 }
 
 // Merge combines layers into a single document.
-// Order: team (base) -> personal -> project
+// Order: team layers (org first, base) -> personal -> project
 // Returns the merged markdown content.
 func Merge(layers []Layer, opts MergeOptions) string {
 	if len(layers) == 0 {
@@ -34,14 +35,17 @@ func Merge(layers []Layer, opts MergeOptions) string {
 
 	// Start with the first non-empty layer as base
 	var baseDoc *Document
-	var baseSource string
-	for _, layer := range layers {
+	var baseLayer Layer
+	baseIndex := -1
+	for i, layer := range layers {
 		if strings.TrimSpace(layer.Content) != "" {
-			baseDoc = Parse(layer.Content)
-			baseSource = layer.Source
+			baseDoc = parseLayer(layer.Content)
+			baseLayer = layer
+			baseIndex = i
 			// Mark all base sections with their source
-			for i := range baseDoc.Sections {
-				baseDoc.Sections[i].Source = baseSource
+			for j := range baseDoc.Sections {
+				baseDoc.Sections[j].Source = layer.Source
+				baseDoc.Sections[j].Repo = layer.Repo
 			}
 			break
 		}
@@ -51,22 +55,49 @@ func Merge(layers []Layer, opts MergeOptions) string {
 		return ""
 	}
 
-	// Merge additional layers into base
-	for _, layer := range layers {
-		if layer.Source == baseSource || strings.TrimSpace(layer.Content) == "" {
+	// Merge the remaining layers into base, in order
+	for _, layer := range layers[baseIndex+1:] {
+		if strings.TrimSpace(layer.Content) == "" {
 			continue
 		}
 		mergeLayer(baseDoc, layer, opts.AnnotateSources)
 	}
 
 	// Render the merged document
-	return render(baseDoc, opts, baseSource)
+	return render(baseDoc, opts, baseLayer)
 }
 
 // mergeLayer merges a layer into the base document.
+// Sections are appended under a sub-header unless the layer's directives
+// replace or remove them; locked sections only accept additions. Subsections
+// a layer repeats are merged into the matching subsection, and a section
+// headed with a path like "Testing > ### Mocks" targets one directly.
 func mergeLayer(base *Document, layer Layer, annotate bool) {
-	doc := Parse(layer.Content)
-	label := formatAdditionLabel(layer.Source)
+	doc := parseLayer(layer.Content)
+	label := formatAdditionLabel(layer)
+
+	// Apply removals first so a layer can drop a section and redefine it
+	for _, header := range doc.Removals {
+		path := targetPath(base, header)
+		existingSection := base.FindSection(path[0])
+		if existingSection == nil {
+			continue
+		}
+		override := Override{Section: strings.Join(append([]string{existingSection.Header}, path[1:]...), pathSeparator), Action: OverrideRemoved, Source: layer.Source}
+		switch {
+		case existingSection.Locked:
+			override.Action = OverrideBlocked
+		case len(path) > 1:
+			content, ok := removeSubsection(existingSection.Content, path[1:], 3)
+			if !ok {
+				continue
+			}
+			existingSection.Content = content
+		default:
+			base.removeSection(header)
+		}
+		base.Overrides = append(base.Overrides, override)
+	}
 
 	// Merge sections
 	for _, section := range doc.Sections {
@@ -75,36 +106,74 @@ func mergeLayer(base *Document, layer Layer, annotate bool) {
 			continue
 		}
 
+		if path := targetPath(base, section.Header); len(path) > 1 {
+			section.Header = path[0]
+			section.Content = nestUnder(path[1:], section.Content, 3, section.Replace)
+			section.Replace = false
+		}
+
 		existingSection := base.FindSection(section.Header)
+		if existingSection != nil && section.Replace {
+			if !existingSection.Locked {
+				existingSection.Content = section.Content
+				existingSection.Source = layer.Source
+				existingSection.Repo = layer.Repo
+				base.Overrides = append(base.Overrides, Override{Section: existingSection.Header, Action: OverrideReplaced, Source: layer.Source})
+				continue
+			}
+			// Locked sections fall back to the usual append
+			base.Overrides = append(base.Overrides, Override{Section: existingSection.Header, Action: OverrideBlocked, Source: layer.Source})
+		}
+
 		if existingSection != nil {
-			// Append to existing section with sub-header and source annotation
-			existingSection.Content = appendWithSubHeader(
-				existingSection.Content,
-				section.Content,
-				label,
-				layer.Source,
-				annotate,
-			)
+			// Merge into existing section with sub-headers and source annotations
+			m := &subsectionMerge{
+				doc:      base,
+				layer:    layer,
+				label:    label,
+				marker:   sourceComment(layer.Source, layer.Repo),
+				owner:    sourceComment(existingSection.Source, existingSection.Repo),
+				locked:   existingSection.Locked,
+				annotate: annotate,
+			}
+			existingSection.Content = m.mergeSubsections(existingSection.Header, existingSection.Content, section.Content, 3)
 		} else {
 			// Add as new section with source
 			base.Sections = append(base.Sections, Section{
+				Level:   section.Level,
 				Header:  section.Header,
-				Content: section.Content,
+				Content: stripSubsectionDirectives(section.Content),
 				Source:  layer.Source,
+				Repo:    layer.Repo,
+				Locked:  section.Locked,
 			})
 		}
 	}
 }
 
-// formatAdditionLabel returns the sub-header label for a source.
-func formatAdditionLabel(source string) string {
-	switch source {
+// targetPath returns the headings a layer's section header refers to: one
+// for a section of base, or several for a path like "Testing > Mocks".
+// A base section whose header merely contains " > " is matched as is.
+func targetPath(base *Document, header string) []string {
+	if base.HasSection(header) {
+		return []string{header}
+	}
+	return splitPath(header)
+}
+
+// formatAdditionLabel returns the sub-header label for a layer.
+// Stacked team layers are labeled with their repo so each overlay is identifiable.
+func formatAdditionLabel(layer Layer) string {
+	switch layer.Source {
 	case "personal":
 		return "Personal Additions"
 	case "project":
 		return "Project Additions"
 	default:
-		return titleCase(source) + " Additions"
+		if layer.Repo != "" {
+			return "Additions from " + layer.Repo
+		}
+		return titleCase(layer.Source) + " Additions"
 	}
 }
 
@@ -116,72 +185,100 @@ func titleCase(s string) string {
 	return strings.ToUpper(s[:1]) + s[1:]
 }
 
-// appendWithSubHeader appends content under a ### sub-header.
-// If annotate is true, adds a source provenance comment before the addition.
-func appendWithSubHeader(base, addition, label, source string, annotate bool) string {
+// appendWithSubHeader appends content under a sub-header at level (### for a
+// section's additions). If annotate is true, adds the marker comment before the addition.
+func appendWithSubHeader(base, addition, label, marker string, annotate bool, level int) string {
 	if strings.TrimSpace(addition) == "" {
 		return base
 	}
+	subHeader := strings.Repeat("#", level) + " " + label
 	if annotate {
-		return fmt.Sprintf("%s\n\n%s\n### %s\n\n%s",
+		return fmt.Sprintf("%s\n\n%s\n%s\n\n%s",
 			base,
-			sourceStartComment(source),
-			label,
+			marker,
+			subHeader,
 			addition,
 		)
 	}
-	return fmt.Sprintf("%s\n\n### %s\n\n%s", base, label, addition)
+	return fmt.Sprintf("%s\n\n%s\n\n%s", base, subHeader, addition)
 }
 
 // sourceStartComment returns the provenance comment for a source.
-// If language is non-empty, creates a language-specific marker (e.g., "team:python").
 func sourceStartComment(source string) string {
 	return fmt.Sprintf("<!-- staghorn:source:%s -->", source)
 }
 
+// sourceComment returns the provenance comment for a source, naming the repo
+// it came from when known (e.g., "<!-- staghorn:source:team repo=acme/standards -->").
+func sourceComment(source, repo string) string {
+	if repo == "" {
+		return sourceStartComment(source)
+	}
+	return fmt.Sprintf("<!-- staghorn:source:%s repo=%s -->", source, repo)
+}
+
 // sourceLanguageComment returns the provenance comment for a language-specific source.
 func sourceLanguageComment(source, language string) string {
 	return fmt.Sprintf("<!-- staghorn:source:%s:%s -->", source, language)
 }
 
-// headerRegex matches markdown headers (## Header, ### Header, etc.)
-var headerRegex = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
-
 // demoteHeaders shifts all markdown headers down one level (## becomes ###, etc.)
-// Headers at H6 remain at H6 (can't go deeper).
+// Headers at H6 remain at H6 (can't go deeper). Setext headings are rewritten
+// as ATX ones, and "#" lines inside code blocks are left alone.
 func demoteHeaders(content string) string {
-	lines := strings.Split(content, "\n")
-	for i, line := range lines {
-		if match := headerRegex.FindStringSubmatch(line); match != nil {
-			hashes := match[1]
-			text := match[2]
-			if len(hashes) < 6 {
-				lines[i] = "#" + hashes + " " + text
+	var b strings.Builder
+	last := 0
+	for _, h := range scan(content).headings {
+		line := content[h.start:h.end]
+		b.WriteString(content[last:h.start])
+		last = h.end
+		if !h.setext {
+			// ATX: one more # keeps the rest of the line as written
+			if i := strings.IndexByte(line, '#'); h.level < 6 {
+				line = line[:i] + "#" + line[i:]
 			}
+			b.WriteString(line)
+			continue
+		}
+		b.WriteString(strings.Repeat("#", min(h.level+1, 6)) + " " + h.text)
+		if strings.HasSuffix(line, "\n") {
+			b.WriteString("\n")
 		}
 	}
-	return strings.Join(lines, "\n")
+	b.WriteString(content[last:])
+	return b.String()
 }
 
 // render converts a Document back to markdown.
-// baseSource is the source of the base layer (used for section provenance).
-func render(doc *Document, opts MergeOptions, baseSource string) string {
+// base is the base layer (used for section provenance).
+func render(doc *Document, opts MergeOptions, base Layer) string {
 	var b strings.Builder
 
 	// Header comment
 	if opts.AnnotateSources {
 		timestamp := time.Now().Format("2006-01-02")
-		if opts.SourceRepo != "" {
+		switch {
+		case opts.OmitHeader:
+		case opts.SourceRepo != "":
 			b.WriteString(fmt.Sprintf("%s | Source: %s | Do not edit directly | %s -->\n\n", HeaderManagedPrefix, opts.SourceRepo, timestamp))
-		} else {
+		default:
 			b.WriteString(fmt.Sprintf("%s | Do not edit directly | %s -->\n\n", HeaderManagedPrefix, timestamp))
 		}
+
+		// Record overrides up front, ahead of any source markers
+		for _, o := range doc.Overrides {
+			b.WriteString(overrideComment(o))
+			b.WriteString("\n")
+		}
+		if len(doc.Overrides) > 0 {
+			b.WriteString("\n")
+		}
 	}
 
 	// Preamble (from base source)
 	if doc.Preamble != "" {
-		if opts.AnnotateSources && baseSource != "" {
-			b.WriteString(sourceStartComment(baseSource))
+		if opts.AnnotateSources && base.Source != "" {
+			b.WriteString(sourceComment(base.Source, base.Repo))
 			b.WriteString("\n")
 		}
 		b.WriteString(doc.Preamble)
@@ -189,27 +286,36 @@ func render(doc *Document, opts MergeOptions, baseSource string) string {
 	}
 
 	// Sections - only emit source marker when source changes
-	currentSource := ""
-	if doc.Preamble != "" && baseSource != "" {
-		currentSource = baseSource // preamble already set the source
+	currentMarker := ""
+	if doc.Preamble != "" && base.Source != "" {
+		currentMarker = sourceComment(base.Source, base.Repo) // preamble already set the source
 	}
 
 	for i, section := range doc.Sections {
-		source := section.Source
+		source, repo := section.Source, section.Repo
 		if source == "" {
-			source = baseSource
+			source, repo = base.Source, base.Repo
 		}
 
 		// Only add source annotation when source changes
-		if opts.AnnotateSources && source != "" && source != currentSource {
-			b.WriteString(sourceStartComment(source))
-			b.WriteString("\n")
-			currentSource = source
+		if opts.AnnotateSources && source != "" {
+			if marker := sourceComment(source, repo); marker != currentMarker {
+				b.WriteString(marker)
+				b.WriteString("\n")
+				currentMarker = marker
+			}
 		}
 
-		b.WriteString(fmt.Sprintf("## %s\n\n", section.Header))
+		b.WriteString(section.headingLine())
+		b.WriteString("\n\n")
 		b.WriteString(section.Content)
 
+		// Additions appended to this section carry their own markers, so the
+		// next section must re-establish its source
+		if opts.AnnotateSources && strings.Contains(section.Content, "<!-- staghorn:source:") {
+			currentMarker = ""
+		}
+
 		if i < len(doc.Sections)-1 {
 			b.WriteString("\n\n")
 		}
@@ -315,7 +421,7 @@ func buildLanguageSection(languages []string, files map[string][]*language.Langu
 				b.WriteString(content)
 			} else {
 				// Personal/project additions get sub-headers (### under the H2 language)
-				label := formatAdditionLabel(file.Source)
+				label := formatAdditionLabel(Layer{Source: file.Source})
 				if !annotate {
 					// No marker was written, need spacing
 					b.WriteString("\n\n")
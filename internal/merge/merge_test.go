@@ -329,6 +329,60 @@ func TestSourceCommentFormat(t *testing.T) {
 	}
 }
 
+func TestMergeStackedTeamLayers(t *testing.T) {
+	layers := []Layer{
+		{Content: "Org preamble.\n\n## Code Style\n\nOrg rules.\n\n## Security\n\nNo secrets.", Source: "team", Repo: "acme/standards"},
+		{Content: "## Code Style\n\nPlatform rules.\n\n## On-Call\n\nPage platform.", Source: "team", Repo: "acme/platform-standards"},
+		{Content: "## Code Style\n\nMy prefs.", Source: "personal"},
+	}
+
+	result := Merge(layers, MergeOptions{AnnotateSources: true})
+
+	for _, want := range []string{
+		"<!-- staghorn:source:team repo=acme/standards -->\nOrg preamble.",
+		"<!-- staghorn:source:team repo=acme/platform-standards -->\n### Additions from acme/platform-standards\n\nPlatform rules.",
+		"<!-- staghorn:source:personal -->\n### Personal Additions\n\nMy prefs.",
+		"<!-- staghorn:source:team repo=acme/platform-standards -->\n## On-Call",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in result:\n%s", want, result)
+		}
+	}
+
+	// Layers merge in order: org, then platform, then personal
+	org := strings.Index(result, "Org rules.")
+	platform := strings.Index(result, "Platform rules.")
+	personal := strings.Index(result, "My prefs.")
+	if org >= platform || platform >= personal {
+		t.Errorf("layers out of order: org=%d platform=%d personal=%d", org, platform, personal)
+	}
+
+	// The section after appended additions re-establishes its source
+	sections := ParseProvenanceSections(result)
+	for _, section := range sections {
+		if strings.Contains(section.Content, "No secrets.") && section.Repo != "acme/standards" {
+			t.Errorf("Security section attributed to %q/%q, want team acme/standards", section.Source, section.Repo)
+		}
+	}
+}
+
+func TestMergeSkipsEmptyBaseLayers(t *testing.T) {
+	layers := []Layer{
+		{Content: "", Source: "team", Repo: "acme/standards"},
+		{Content: "## Code Style\n\nPlatform rules.", Source: "team", Repo: "acme/platform-standards"},
+		{Content: "## Code Style\n\nMore team rules.", Source: "team", Repo: "acme/web-standards"},
+	}
+
+	result := Merge(layers, MergeOptions{})
+
+	if !strings.HasPrefix(result, "## Code Style\n\nPlatform rules.") {
+		t.Errorf("first non-empty layer should be the base, got:\n%s", result)
+	}
+	if !strings.Contains(result, "### Additions from acme/web-standards\n\nMore team rules.") {
+		t.Errorf("later team layer with the same source should still merge, got:\n%s", result)
+	}
+}
+
 func TestMergeProvenanceOrdering(t *testing.T) {
 	// Verify that markers appear in correct order: team section first, then personal additions
 	layers := []Layer{
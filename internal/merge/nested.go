@@ -0,0 +1,211 @@
+package merge
+
+import (
+	"strings"
+)
+
+// pathSeparator joins the headings of a nested section, as in "Testing > Mocks".
+const pathSeparator = " > "
+
+// subsection is a heading at a given level inside a section, and everything
+// until the next heading at that level or above.
+type subsection struct {
+	header  string // Heading text
+	heading string // The heading line(s) as written
+	body    string // Content under the heading, without surrounding blank lines
+	changed bool   // A layer added to or replaced the body
+}
+
+// splitIntro splits content at its first heading at level or above, returning
+// the text before it and the rest.
+func splitIntro(content string, level int) (intro, rest string) {
+	for _, h := range scan(content).headings {
+		if h.level <= level {
+			return content[:h.start], content[h.start:]
+		}
+	}
+	return content, ""
+}
+
+// splitSubsections splits section content into its intro and the subsections
+// headed at level. Deeper headings stay in their subsection's body.
+func splitSubsections(content string, level int) (string, []subsection) {
+	var starts []heading
+	for _, h := range scan(content).headings {
+		if h.level <= level {
+			starts = append(starts, h)
+		}
+	}
+	if len(starts) == 0 {
+		return trimBlankLines(content), nil
+	}
+
+	subs := make([]subsection, len(starts))
+	for i, h := range starts {
+		end := len(content)
+		if i+1 < len(starts) {
+			end = starts[i+1].start
+		}
+		subs[i] = subsection{
+			header:  h.text,
+			heading: strings.TrimRight(content[h.start:h.end], " \t\r\n"),
+			body:    trimBlankLines(content[h.end:end]),
+		}
+	}
+	return trimBlankLines(content[:starts[0].start]), subs
+}
+
+// joinSubsections reassembles section content. When annotate is set, marker
+// is repeated after each changed subsection, so the layer content merged into
+// it isn't taken to run on into the subsections that follow.
+func joinSubsections(intro string, subs []subsection, marker string, annotate bool) string {
+	var parts []string
+	if intro != "" {
+		parts = append(parts, intro)
+	}
+	for i, sub := range subs {
+		if annotate && i > 0 && subs[i-1].changed {
+			parts = append(parts, marker+"\n"+sub.heading)
+		} else {
+			parts = append(parts, sub.heading)
+		}
+		if sub.body != "" {
+			parts[len(parts)-1] += "\n\n" + sub.body
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// findSubsection returns the subsection with header (case-insensitive), or nil.
+func findSubsection(subs []subsection, header string) *subsection {
+	for i := range subs {
+		if strings.EqualFold(subs[i].header, header) {
+			return &subs[i]
+		}
+	}
+	return nil
+}
+
+// splitPath splits a section header written as a path, such as
+// "Testing > ### Mocks", into its headings. A plain header is one element.
+func splitPath(header string) []string {
+	if !strings.Contains(header, pathSeparator) {
+		return []string{header}
+	}
+	var path []string
+	for _, part := range strings.Split(header, pathSeparator) {
+		part = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(part), "#"))
+		if part == "" {
+			return []string{header}
+		}
+		path = append(path, part)
+	}
+	return path
+}
+
+// nestUnder wraps content in headings for each element of path, starting at
+// level, so a "## Testing > ### Mocks" section merges like a "### Mocks"
+// subsection of "## Testing". A replace directive moves to the innermost heading.
+func nestUnder(path []string, content string, level int, replace bool) string {
+	var b strings.Builder
+	for i, header := range path {
+		b.WriteString(strings.Repeat("#", min(level+i, 6)) + " " + header + "\n\n")
+	}
+	if replace {
+		b.WriteString("<!-- staghorn:replace -->\n\n")
+	}
+	b.WriteString(content)
+	return b.String()
+}
+
+// subsectionMerge carries what mergeSubsections needs from the layer being merged.
+type subsectionMerge struct {
+	doc      *Document // Base document, to record overrides on
+	layer    Layer
+	label    string // Heading for additions that don't match a subsection
+	marker   string // Provenance marker of the layer
+	owner    string // Provenance marker of the section being merged into
+	locked   bool   // The section is locked, so subsections can't be replaced
+	annotate bool
+}
+
+// mergeSubsections merges addition into existing, the contents of the section
+// at path, whose subsections are headed at level. Subsections of addition
+// that match one in existing are merged into it, recursively, or replace it
+// with staghorn:replace; everything else is appended under the layer's label.
+func (m *subsectionMerge) mergeSubsections(path, existing, addition string, level int) string {
+	if level > 6 {
+		return appendWithSubHeader(existing, stripSubsectionDirectives(addition), m.label, m.marker, m.annotate, 6)
+	}
+
+	intro, subs := splitSubsections(existing, level)
+	addIntro, addSubs := splitSubsections(addition, level)
+
+	var unmatched []subsection
+	matched := false
+	for _, add := range addSubs {
+		target := findSubsection(subs, add.header)
+		if target == nil {
+			unmatched = append(unmatched, add)
+			continue
+		}
+		matched = true
+
+		targetPath := path + pathSeparator + target.header
+		addBody, addRest := splitIntro(add.body, level+1)
+		if hasDirective(replaceDirectiveRegex, addBody) {
+			if !m.locked {
+				target.body = trimBlankLines(replaceOutsideCode(replaceDirectiveRegex, addBody, "") + addRest)
+				if m.annotate {
+					target.body = m.marker + "\n" + target.body
+				}
+				target.changed = true
+				m.doc.Overrides = append(m.doc.Overrides, Override{Section: targetPath, Action: OverrideReplaced, Source: m.layer.Source})
+				continue
+			}
+			// Locked sections fall back to the usual append
+			m.doc.Overrides = append(m.doc.Overrides, Override{Section: targetPath, Action: OverrideBlocked, Source: m.layer.Source})
+			add.body = trimBlankLines(replaceOutsideCode(replaceDirectiveRegex, addBody, "") + addRest)
+		}
+
+		merged := m.mergeSubsections(targetPath, target.body, add.body, level+1)
+		target.changed = merged != target.body
+		target.body = merged
+	}
+
+	if !matched {
+		// Nothing nested, so keep both sides exactly as written
+		return appendWithSubHeader(existing, stripSubsectionDirectives(addition), m.label, m.marker, m.annotate, level)
+	}
+	result := joinSubsections(intro, subs, m.owner, m.annotate)
+	rest := joinSubsections(addIntro, unmatched, "", false)
+	return appendWithSubHeader(result, stripSubsectionDirectives(rest), m.label, m.marker, m.annotate, level)
+}
+
+// removeSubsection drops the subsection at path from section content, whose
+// subsections are headed at level. Reports whether it was found.
+func removeSubsection(content string, path []string, level int) (string, bool) {
+	intro, subs := splitSubsections(content, level)
+	for i := range subs {
+		if !strings.EqualFold(subs[i].header, path[0]) {
+			continue
+		}
+		if len(path) == 1 {
+			subs = append(subs[:i], subs[i+1:]...)
+			return joinSubsections(intro, subs, "", false), true
+		}
+		body, ok := removeSubsection(subs[i].body, path[1:], level+1)
+		if !ok {
+			return content, false
+		}
+		subs[i].body = body
+		return joinSubsections(intro, subs, "", false), true
+	}
+	return content, false
+}
+
+// stripSubsectionDirectives removes replace directives left in content that
+// is added as is, where they have nothing to replace.
+func stripSubsectionDirectives(content string) string {
+	return trimBlankLines(replaceOutsideCode(replaceDirectiveRegex, content, ""))
+}
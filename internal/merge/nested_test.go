@@ -0,0 +1,147 @@
+package merge
+
+import (
+	"strings"
+	"testing"
+)
+
+const nestedTeam = `## Testing
+
+Write tests.
+
+### Mocks
+
+Use fakes.
+
+### Fixtures
+
+Keep them small.`
+
+// mergeWithOverrides merges personal over team with provenance, returning the
+// result and the overrides it records.
+func mergeWithOverrides(team, personal string) (string, []Override) {
+	result := Merge([]Layer{
+		{Content: team, Source: "team"},
+		{Content: personal, Source: "personal"},
+	}, MergeOptions{AnnotateSources: true})
+	return result, ParseOverrides(result)
+}
+
+func TestMergeNestedSubsection(t *testing.T) {
+	personal := "## Testing\n\n### Mocks\n\nPrefer gomock."
+
+	result := Merge([]Layer{
+		{Content: nestedTeam, Source: "team"},
+		{Content: personal, Source: "personal"},
+	}, MergeOptions{})
+
+	want := `## Testing
+
+Write tests.
+
+### Mocks
+
+Use fakes.
+
+#### Personal Additions
+
+Prefer gomock.
+
+### Fixtures
+
+Keep them small.`
+	if strings.TrimSpace(result) != want {
+		t.Errorf("Merge() =\n%s\nwant\n%s", result, want)
+	}
+}
+
+func TestMergeNestedPathHeader(t *testing.T) {
+	for _, header := range []string{"## Testing > ### Mocks", "## Testing > Mocks"} {
+		t.Run(header, func(t *testing.T) {
+			result := Merge([]Layer{
+				{Content: nestedTeam, Source: "team"},
+				{Content: header + "\n\nPrefer gomock.", Source: "personal"},
+			}, MergeOptions{})
+
+			if strings.Contains(result, "## Testing > ") {
+				t.Errorf("Path header should not become a section:\n%s", result)
+			}
+			mocks := result[strings.Index(result, "### Mocks"):strings.Index(result, "### Fixtures")]
+			if !strings.Contains(mocks, "#### Personal Additions\n\nPrefer gomock.") {
+				t.Errorf("Addition should go under Mocks:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestMergeNestedReplace(t *testing.T) {
+	personal := "## Testing > Mocks\n<!-- staghorn:replace -->\n\nPrefer gomock."
+
+	result, overrides := mergeWithOverrides(nestedTeam, personal)
+
+	if strings.Contains(result, "Use fakes.") {
+		t.Errorf("Mocks should be replaced:\n%s", result)
+	}
+	if !strings.Contains(result, "### Mocks\n\n<!-- staghorn:source:personal -->\nPrefer gomock.\n\n<!-- staghorn:source:team -->\n### Fixtures") {
+		t.Errorf("Replacement should stay in place:\n%s", result)
+	}
+	if !strings.Contains(result, "Write tests.") || !strings.Contains(result, "Keep them small.") {
+		t.Errorf("Rest of Testing should be kept:\n%s", result)
+	}
+	if len(overrides) != 1 || overrides[0].Section != "Testing > Mocks" || overrides[0].Action != OverrideReplaced {
+		t.Errorf("overrides = %+v, want Testing > Mocks replaced", overrides)
+	}
+}
+
+func TestMergeNestedRemove(t *testing.T) {
+	personal := `<!-- staghorn:remove "Testing > Fixtures" -->`
+
+	result, overrides := mergeWithOverrides(nestedTeam, personal)
+
+	if strings.Contains(result, "Keep them small.") {
+		t.Errorf("Fixtures should be removed:\n%s", result)
+	}
+	if !strings.Contains(result, "Use fakes.") {
+		t.Errorf("Mocks should be kept:\n%s", result)
+	}
+	if len(overrides) != 1 || overrides[0].Section != "Testing > Fixtures" || overrides[0].Action != OverrideRemoved {
+		t.Errorf("overrides = %+v, want Testing > Fixtures removed", overrides)
+	}
+}
+
+func TestMergeNestedLocked(t *testing.T) {
+	team := strings.Replace(nestedTeam, "## Testing\n", "## Testing\n<!-- staghorn:locked -->\n", 1)
+	personal := "## Testing > Mocks\n<!-- staghorn:replace -->\n\nPrefer gomock."
+
+	result, overrides := mergeWithOverrides(team, personal)
+
+	if !strings.Contains(result, "Use fakes.") || !strings.Contains(result, "Prefer gomock.") {
+		t.Errorf("Locked section should take the addition without replacing:\n%s", result)
+	}
+	if len(overrides) != 1 || overrides[0].Action != OverrideBlocked {
+		t.Errorf("overrides = %+v, want blocked", overrides)
+	}
+}
+
+func TestMergeNestedProvenanceRoundTrip(t *testing.T) {
+	result := Merge([]Layer{
+		{Content: nestedTeam, Source: "team"},
+		{Content: "## Testing\n\n### Mocks\n\nPrefer gomock.", Source: "personal"},
+	}, MergeOptions{AnnotateSources: true})
+
+	byLayer := ParseProvenanceByLayer(result)
+	if !strings.Contains(byLayer["personal"], "Prefer gomock.") || strings.Contains(byLayer["personal"], "Keep them small.") {
+		t.Errorf("personal = %q, want only the addition", byLayer["personal"])
+	}
+	if !strings.Contains(byLayer["team"], "Keep them small.") || strings.Contains(byLayer["team"], "Prefer gomock.") {
+		t.Errorf("team = %q, want the subsections after the addition", byLayer["team"])
+	}
+}
+
+func TestParseProvenance_IgnoresMarkersInCode(t *testing.T) {
+	content := "<!-- staghorn:source:team -->\n## Docs\n\nExample:\n\n```\n<!-- staghorn:source:personal -->\n```"
+
+	if layers := ListLayers(content); len(layers) != 1 || layers[0] != "team" {
+		t.Errorf("ListLayers() = %q, want only team", layers)
+	}
+}
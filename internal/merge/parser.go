@@ -1,28 +1,34 @@
 // Package merge handles CLAUDE.md section parsing and merging.
 package merge
 
-import (
-	"regexp"
-	"strings"
-)
+import "strings"
 
-// Section represents an H2-delimited section of markdown.
+// Section represents a top-level section of markdown: an H2, or an H1 after
+// the first H2, and everything until the next one. Deeper headings stay in
+// Content and are matched as subsections when layers are merged.
 type Section struct {
-	Header  string // The H2 header text (without ##)
-	Content string // Everything until the next H2
+	Level   int    // Heading level: 2, or 1 for an H1 after the first H2 (0 is treated as 2)
+	Header  string // The heading text (without ## or setext underline)
+	Content string // Everything until the next section, byte for byte apart from surrounding blank lines
 	Source  string // Source layer ("team", "personal", "project")
+	Repo    string // Source repo for stacked team layers
+	Replace bool   // Layer asked to replace this section rather than append (staghorn:replace)
+	Locked  bool   // Later layers can't replace or remove this section (staghorn:locked)
 }
 
 // Document represents a parsed markdown document.
 type Document struct {
-	Preamble string    // Content before the first H2
-	Sections []Section // H2-delimited sections
+	Title     string     // Text of the H1 before the first section, if any
+	Preamble  string     // Content before the first section, including the title
+	Sections  []Section  // Top-level sections
+	Removals  []string   // Sections this layer asks to remove (staghorn:remove)
+	Overrides []Override // Directives applied while merging, in order
 }
 
-// h2Pattern matches markdown H2 headers (## Header).
-var h2Pattern = regexp.MustCompile(`(?m)^##\s+(.+)$`)
-
-// Parse splits markdown into sections by H2 headers.
+// Parse splits markdown into sections at its H2 headings, and at H1 headings
+// once the first H2 has started. Headings are found with CommonMark's block
+// rules, so ATX and setext headings count while "## " lines in code blocks
+// and HTML comments don't.
 func Parse(content string) *Document {
 	doc := &Document{
 		Sections: []Section{},
@@ -32,44 +38,51 @@ func Parse(content string) *Document {
 		return doc
 	}
 
-	// Find all H2 header positions
-	matches := h2Pattern.FindAllStringSubmatchIndex(content, -1)
-	if len(matches) == 0 {
-		// No H2 headers, entire content is preamble
-		doc.Preamble = strings.TrimSpace(content)
-		return doc
+	var starts []heading
+	for _, h := range scan(content).headings {
+		switch {
+		case h.level == 1 && len(starts) == 0:
+			if doc.Title == "" {
+				doc.Title = h.text
+			}
+		case h.level <= 2:
+			starts = append(starts, h)
+		}
 	}
 
-	// Content before first H2 is the preamble
-	doc.Preamble = strings.TrimSpace(content[:matches[0][0]])
+	if len(starts) == 0 {
+		// No sections, entire content is preamble
+		doc.Preamble = trimBlankLines(content)
+		return doc
+	}
 
-	// Extract each section
-	for i, match := range matches {
-		headerEnd := match[1]
-		headerTextStart := match[2]
-		headerTextEnd := match[3]
+	// Content before the first section is the preamble
+	doc.Preamble = trimBlankLines(content[:starts[0].start])
 
-		// Determine where this section's content ends
-		var contentEnd int
-		if i+1 < len(matches) {
-			contentEnd = matches[i+1][0]
-		} else {
-			contentEnd = len(content)
+	for i, h := range starts {
+		contentEnd := len(content)
+		if i+1 < len(starts) {
+			contentEnd = starts[i+1].start
 		}
-
-		// Extract the header text and content
-		header := strings.TrimSpace(content[headerTextStart:headerTextEnd])
-		sectionContent := strings.TrimSpace(content[headerEnd:contentEnd])
-
 		doc.Sections = append(doc.Sections, Section{
-			Header:  header,
-			Content: sectionContent,
+			Level:   h.level,
+			Header:  h.text,
+			Content: trimBlankLines(content[h.end:contentEnd]),
 		})
 	}
 
 	return doc
 }
 
+// headingLine returns the ATX heading a section is rendered with.
+func (s Section) headingLine() string {
+	level := s.Level
+	if level == 0 {
+		level = 2
+	}
+	return strings.Repeat("#", level) + " " + s.Header
+}
+
 // FindSection finds a section by header name (case-insensitive).
 func (d *Document) FindSection(header string) *Section {
 	headerLower := strings.ToLower(header)
@@ -2,6 +2,7 @@ package merge
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -17,6 +18,7 @@ const (
 type ProvenanceSection struct {
 	Source   string // The source layer (team, personal, project)
 	Language string // Optional language subsection (python, go, etc.) - empty for main content
+	Repo     string // Optional source repo for stacked team layers (acme/standards, etc.)
 	Content  string // The content from this source
 }
 
@@ -34,11 +36,35 @@ func (p ProvenanceSection) FullSource() string {
 //   - <!-- staghorn:source:personal --> (main personal content)
 //   - <!-- staghorn:source:team:python --> (team's python guidelines)
 //   - <!-- staghorn:source:personal:go --> (personal's go additions)
+//   - <!-- staghorn:source:team repo=acme/standards --> (one of several team layers)
 //
 // Captures:
 //   - Group 1: layer (team, personal, project)
 //   - Group 2: optional language (python, go, etc.)
-var sourceMarkerRegex = regexp.MustCompile(`<!--\s*staghorn:source:(\w+)(?::(\w+))?\s*-->`)
+//   - Group 3: optional repo
+var sourceMarkerRegex = regexp.MustCompile(`<!--\s*staghorn:source:(\w+)(?::(\w+))?(?:\s+repo=([^\s>]+))?\s*-->`)
+
+// overrideMarkerRegex matches override markers in merged configs.
+// Example: <!-- staghorn:override:removed "Security" by=personal -->
+//
+// Captures:
+//   - Group 1: action (replaced, removed, blocked)
+//   - Group 2: quoted section header
+//   - Group 3: layer that issued the directive
+var overrideMarkerRegex = regexp.MustCompile(`<!--\s*staghorn:override:(\w+)\s+("(?:[^"\\]|\\.)*")\s+by=(\w+)\s*-->`)
+
+// ParseOverrides returns the overrides recorded in a merged config, in order.
+func ParseOverrides(content string) []Override {
+	var overrides []Override
+	for _, match := range findOutsideCode(overrideMarkerRegex, content) {
+		section, err := strconv.Unquote(content[match[4]:match[5]])
+		if err != nil {
+			continue
+		}
+		overrides = append(overrides, Override{Section: section, Action: content[match[2]:match[3]], Source: content[match[6]:match[7]]})
+	}
+	return overrides
+}
 
 // ParseProvenance extracts content grouped by full source from a merged config.
 // Returns a map of full source (e.g., "team", "team:python") -> content.
@@ -83,14 +109,16 @@ func ParseProvenanceSections(content string) []ProvenanceSection {
 	var sections []ProvenanceSection
 
 	// Strip header comments (<!-- Managed by staghorn... --> and <!-- Generated by staghorn... -->)
+	// and override markers, which describe the merge rather than any layer's content
 	content = stripHeaderComments(content)
+	content = replaceOutsideCode(overrideMarkerRegex, content, "")
 
-	// Find all source markers and their positions
-	matches := sourceMarkerRegex.FindAllStringSubmatchIndex(content, -1)
+	// Find all source markers and their positions, skipping examples in code blocks
+	matches := findOutsideCode(sourceMarkerRegex, content)
 
 	if len(matches) == 0 {
 		// No provenance markers - return entire content as unknown source
-		trimmed := strings.TrimSpace(content)
+		trimmed := trimBlankLines(content)
 		if trimmed != "" {
 			sections = append(sections, ProvenanceSection{
 				Source:  "unknown",
@@ -111,6 +139,12 @@ func ParseProvenanceSections(content string) []ProvenanceSection {
 			language = content[match[4]:match[5]]
 		}
 
+		// Capture group 3: optional repo
+		var repo string
+		if match[6] != -1 && match[7] != -1 {
+			repo = content[match[6]:match[7]]
+		}
+
 		// Find the end of this section (start of next marker or end of content)
 		var sectionEnd int
 		if i < len(matches)-1 {
@@ -119,12 +153,13 @@ func ParseProvenanceSections(content string) []ProvenanceSection {
 			sectionEnd = len(content)
 		}
 
-		// Extract and clean the content
-		sectionContent := strings.TrimSpace(content[markerEnd:sectionEnd])
+		// Extract and clean the content, keeping the indentation of a leading code block
+		sectionContent := trimBlankLines(content[markerEnd:sectionEnd])
 		if sectionContent != "" {
 			sections = append(sections, ProvenanceSection{
 				Source:   source,
 				Language: language,
+				Repo:     repo,
 				Content:  sectionContent,
 			})
 		}
@@ -177,7 +212,7 @@ func ListLayers(content string) []string {
 
 // HasProvenance checks if the content contains provenance markers.
 func HasProvenance(content string) bool {
-	return sourceMarkerRegex.MatchString(content)
+	return hasDirective(sourceMarkerRegex, content)
 }
 
 // stripHeaderComments removes the staghorn header comments from the content.
@@ -455,3 +455,41 @@ func TestHasProvenanceWithLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestParseProvenanceWithRepo(t *testing.T) {
+	content := `<!-- staghorn:source:team repo=acme/standards -->
+## Code Style
+
+Org rules.
+
+<!-- staghorn:source:team repo=acme/platform-standards -->
+### Additions from acme/platform-standards
+
+Platform rules.
+
+<!-- staghorn:source:personal -->
+### Personal Additions
+
+My prefs.`
+
+	sections := ParseProvenanceSections(content)
+	if len(sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d", len(sections))
+	}
+
+	wantRepos := []string{"acme/standards", "acme/platform-standards", ""}
+	for i, want := range wantRepos {
+		if sections[i].Repo != want {
+			t.Errorf("section %d repo = %q, want %q", i, sections[i].Repo, want)
+		}
+	}
+
+	// Stacked team layers still group under the team layer
+	byLayer := ParseProvenanceByLayer(content)
+	if !strings.Contains(byLayer["team"], "Org rules.") || !strings.Contains(byLayer["team"], "Platform rules.") {
+		t.Errorf("team layer should include all team repos, got:\n%s", byLayer["team"])
+	}
+	if got := ListLayers(content); len(got) != 2 {
+		t.Errorf("ListLayers() = %v, want [team personal]", got)
+	}
+}
@@ -0,0 +1,94 @@
+package merge
+
+import "strings"
+
+// Conflict is a section where a layer's edits overlap a change to the
+// document it is layered on.
+type Conflict struct {
+	Section string // Section header, or "" for the content before the first H2
+	Reason  string
+}
+
+// CheckUpgrade is a three-way merge check for upgrading base to next beneath
+// layer, which was written on top of base. Additions to a section apply to its
+// new content cleanly. A conflict is reported where the layer replaces or
+// removes a section the upgrade changed, adds to a section the upgrade removed,
+// or defines a section the upgrade added.
+func CheckUpgrade(base, next, layer string) []Conflict {
+	baseDoc := parseLayer(base)
+	nextDoc := parseLayer(next)
+	layerDoc := parseLayer(layer)
+
+	changed := func(header string) bool {
+		before, after := baseDoc.FindSection(header), nextDoc.FindSection(header)
+		return before != nil && after != nil && before.Content != after.Content
+	}
+
+	var conflicts []Conflict
+	for _, header := range layerDoc.Removals {
+		if changed(header) {
+			conflicts = append(conflicts, Conflict{Section: header, Reason: "removed by the project, changed in the template"})
+		}
+	}
+	for _, section := range layerDoc.Sections {
+		if strings.TrimSpace(section.Content) == "" {
+			continue
+		}
+		inBase, inNext := baseDoc.HasSection(section.Header), nextDoc.HasSection(section.Header)
+		switch {
+		case inBase && !inNext:
+			conflicts = append(conflicts, Conflict{Section: section.Header, Reason: "removed from the template; the project's content now stands alone"})
+		case !inBase && inNext:
+			conflicts = append(conflicts, Conflict{Section: section.Header, Reason: "added to the template, which the project also defines"})
+		case section.Replace && changed(section.Header):
+			conflicts = append(conflicts, Conflict{Section: section.Header, Reason: "replaced by the project, changed in the template"})
+		}
+	}
+	return conflicts
+}
+
+// Rebase turns doc, a copy of a template edited with no record of the
+// original, into a layer over next that keeps doc's content. Sections that
+// match next are dropped and sections that differ are kept with
+// staghorn:replace; sections only next has are included. Without the original
+// there's no telling whose change a difference was, so each is a conflict.
+func Rebase(doc, next string) (string, []Conflict) {
+	oursDoc := Parse(doc)
+	stripped := parseLayer(doc) // Same sections, without directive comments
+	nextDoc := parseLayer(next)
+
+	var conflicts []Conflict
+	var b strings.Builder
+	if oursDoc.Preamble != "" {
+		b.WriteString(oursDoc.Preamble)
+		b.WriteString("\n\n")
+		if oursDoc.Preamble != nextDoc.Preamble {
+			conflicts = append(conflicts, Conflict{Reason: "differs from the template; the template's is used"})
+		}
+	}
+
+	for i, section := range oursDoc.Sections {
+		theirs := nextDoc.FindSection(section.Header)
+		content := section.Content
+		switch {
+		case theirs == nil:
+			conflicts = append(conflicts, Conflict{Section: section.Header, Reason: "not in the template; kept as a project section"})
+		case stripped.Sections[i].Content == theirs.Content:
+			continue // Unchanged from the template
+		default:
+			conflicts = append(conflicts, Conflict{Section: section.Header, Reason: "differs from the template; the project's version replaces it"})
+			if !replaceDirectiveRegex.MatchString(content) {
+				content = "<!-- staghorn:replace -->\n\n" + content
+			}
+		}
+		b.WriteString(section.headingLine() + "\n\n" + content + "\n\n")
+	}
+
+	for _, section := range nextDoc.Sections {
+		if !oursDoc.HasSection(section.Header) {
+			conflicts = append(conflicts, Conflict{Section: section.Header, Reason: "only in the template; now included"})
+		}
+	}
+
+	return strings.TrimSpace(b.String()) + "\n", conflicts
+}
@@ -0,0 +1,135 @@
+package merge
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckUpgrade(t *testing.T) {
+	base := `# Backend
+
+## Testing
+Table tests.
+
+## Review
+Two approvals.
+
+## Logging
+Use slog.
+
+## Legacy
+Old rules.
+`
+	next := `# Backend
+
+## Testing
+Table tests with t.Run.
+
+## Review
+Two approvals and a green build.
+
+## Logging
+Use slog.
+
+## Security
+No secrets in code.
+`
+	layer := `# Project
+
+## Testing
+Run make test.
+
+## Review
+<!-- staghorn:replace -->
+One approval.
+
+<!-- staghorn:remove "Logging" -->
+
+## Legacy
+Keep the old rules.
+
+## Security
+Rotate keys yearly.
+`
+
+	got := CheckUpgrade(base, next, layer)
+	want := []Conflict{
+		{Section: "Review", Reason: "replaced by the project, changed in the template"},
+		{Section: "Legacy", Reason: "removed from the template; the project's content now stands alone"},
+		{Section: "Security", Reason: "added to the template, which the project also defines"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckUpgrade() = %+v, want %+v", got, want)
+	}
+
+	// Removing a section the template changed conflicts; an unchanged one doesn't
+	got = CheckUpgrade(base, strings.Replace(next, "Use slog.", "Use slog with JSON.", 1), layer)
+	if len(got) != 4 || got[0].Section != "Logging" {
+		t.Errorf("expected a conflict for the removed Logging section, got %+v", got)
+	}
+}
+
+func TestCheckUpgrade_NoChanges(t *testing.T) {
+	base := "# Backend\n\n## Testing\nTable tests."
+	if got := CheckUpgrade(base, base, "## Testing\n<!-- staghorn:replace -->\nRun make test."); len(got) != 0 {
+		t.Errorf("an unchanged template shouldn't conflict, got %+v", got)
+	}
+}
+
+func TestRebase(t *testing.T) {
+	doc := `# Orders Service
+
+## Testing
+Table tests.
+
+## Review
+One approval.
+
+## Context
+Handles orders.
+`
+	next := `# Backend
+
+## Testing
+Table tests.
+
+## Review
+Two approvals.
+
+## Security
+No secrets in code.
+`
+
+	layer, conflicts := Rebase(doc, next)
+	want := `# Orders Service
+
+## Review
+
+<!-- staghorn:replace -->
+
+One approval.
+
+## Context
+
+Handles orders.
+`
+	if layer != want {
+		t.Errorf("Rebase() layer = %q, want %q", layer, want)
+	}
+	wantConflicts := []Conflict{
+		{Reason: "differs from the template; the template's is used"},
+		{Section: "Review", Reason: "differs from the template; the project's version replaces it"},
+		{Section: "Context", Reason: "not in the template; kept as a project section"},
+		{Section: "Security", Reason: "only in the template; now included"},
+	}
+	if !reflect.DeepEqual(conflicts, wantConflicts) {
+		t.Errorf("Rebase() conflicts = %+v, want %+v", conflicts, wantConflicts)
+	}
+
+	// The layer reproduces the project's sections on top of the template
+	merged := Merge([]Layer{{Content: next, Source: "team"}, {Content: layer, Source: "project"}}, MergeOptions{})
+	if !strings.Contains(merged, "One approval.") || strings.Contains(merged, "Two approvals.") {
+		t.Errorf("merged output should keep the project's Review section, got:\n%s", merged)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/fsutil"
 )
 
 // OptimizationMeta tracks optimization state for cached results.
@@ -88,7 +89,7 @@ func (c *OptimizationCache) Write(owner, repo, content string, meta *Optimizatio
 
 	// Write content
 	contentPath := c.paths.OptimizedFile(owner, repo)
-	if err := os.WriteFile(contentPath, []byte(content), 0644); err != nil {
+	if err := fsutil.WriteFile(contentPath, []byte(content), 0644); err != nil {
 		return err
 	}
 
@@ -98,7 +99,7 @@ func (c *OptimizationCache) Write(owner, repo, content string, meta *Optimizatio
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(metaPath, metaData, 0644)
+	return fsutil.WriteFile(metaPath, metaData, 0644)
 }
 
 // IsStale checks if the cached optimization is stale based on source hash.
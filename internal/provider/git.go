@@ -0,0 +1,292 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HartBrook/staghorn/internal/fsutil"
+)
+
+// Git fetches sources from any git remote (Bitbucket, self-hosted servers,
+// file:// URLs, ...) by shallow-fetching into a local bare repository.
+// It requires the git binary and uses the user's existing git credentials.
+type Git struct {
+	url string
+	dir string
+
+	mu      sync.Mutex
+	commits map[string]string // ref -> commit SHA
+	trees   map[string]*Tree  // commit SHA -> tree
+}
+
+// NewGit creates a provider for a git remote URL, caching fetched objects
+// in a bare repository under cacheDir.
+func NewGit(remoteURL, cacheDir string) *Git {
+	sum := sha1.Sum([]byte(remoteURL))
+	return &Git{
+		url:     remoteURL,
+		dir:     filepath.Join(cacheDir, hex.EncodeToString(sum[:])[:16]),
+		commits: make(map[string]string),
+		trees:   make(map[string]*Tree),
+	}
+}
+
+// git runs a git command and returns its stdout.
+func (g *Git) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// local runs a git command against the bare cache repository.
+func (g *Git) local(ctx context.Context, args ...string) (string, error) {
+	return g.git(ctx, append([]string{"--git-dir", g.dir}, args...)...)
+}
+
+// gitLockTimeout is how long to wait for another process using the same cache repository.
+const gitLockTimeout = 2 * time.Minute
+
+// lock takes the lock on the cache repository, creating the repository if
+// needed. Fetches rewrite FETCH_HEAD and the shallow file, so only one
+// process may fetch into a repository at a time; g.mu does the same within one.
+func (g *Git) lock(ctx context.Context) (*fsutil.Lock, error) {
+	l, err := fsutil.Acquire(g.dir+".lock", gitLockTimeout, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock git cache for %s: %w", g.url, err)
+	}
+	if err := g.ensureRepo(ctx); err != nil {
+		_ = l.Release()
+		return nil, err
+	}
+	return l, nil
+}
+
+// ensureRepo creates the bare cache repository if needed.
+func (g *Git) ensureRepo(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(g.dir, "HEAD")); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(g.dir), 0755); err != nil {
+		return err
+	}
+	_, err := g.git(ctx, "init", "--quiet", "--bare", g.dir)
+	return err
+}
+
+// ResolveCommit fetches ref from the remote and returns its commit SHA.
+// An empty ref resolves the remote's HEAD.
+func (g *Git) ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if commit, ok := g.commits[ref]; ok {
+		return commit, nil
+	}
+
+	l, err := g.lock(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = l.Release() }()
+
+	target := "FETCH_HEAD"
+	if _, err := g.local(ctx, "fetch", "--quiet", "--depth", "1", "--no-tags", g.url, ref); err != nil {
+		// Servers only hand out unadvertised commits when allowReachableSHA1InWant
+		// is set, and abbreviated SHAs can never be fetched directly
+		if !commitPattern.MatchString(ref) {
+			return "", err
+		}
+		if ferr := g.fetchAllRefs(ctx); ferr != nil {
+			return "", err
+		}
+		target = ref
+	}
+	out, err := g.local(ctx, "rev-parse", "--verify", "--quiet", target+"^{commit}")
+	if err != nil {
+		if target == ref {
+			return "", fmt.Errorf("commit %s: %w", ref, ErrNotFound)
+		}
+		return "", err
+	}
+
+	commit := strings.TrimSpace(out)
+	g.commits[ref] = commit
+	return commit, nil
+}
+
+// commitPattern matches a full or abbreviated commit SHA.
+var commitPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// fetchAllRefs fetches the full history of every branch and tag, so commits
+// the server won't serve by SHA can be resolved locally.
+func (g *Git) fetchAllRefs(ctx context.Context) error {
+	args := []string{"fetch", "--quiet"}
+	if out, err := g.local(ctx, "rev-parse", "--is-shallow-repository"); err == nil && strings.TrimSpace(out) == "true" {
+		args = append(args, "--unshallow")
+	}
+	args = append(args, g.url, "+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*")
+	_, err := g.local(ctx, args...)
+	return err
+}
+
+// maxCompareCommits bounds how much history CompareCommits fetches, matching
+// the most commits GitHub's compare API returns.
+const maxCompareCommits = 250
+
+// CompareCommits returns the commits reachable from head but not from base,
+// oldest first. The shallow cache is deepened from head to find them, so at
+// most maxCompareCommits are returned.
+func (g *Git) CompareCommits(ctx context.Context, owner, repo, base, head string) ([]Commit, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	l, err := g.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = l.Release() }()
+
+	depth := strconv.Itoa(maxCompareCommits + 1)
+	if _, err := g.local(ctx, "fetch", "--quiet", "--depth", depth, "--no-tags", g.url, head); err != nil {
+		return nil, err
+	}
+	if _, err := g.local(ctx, "cat-file", "-e", base+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("commit %s: %w", base, ErrNotFound)
+	}
+
+	// Records end with RS; the SHA and message are separated by US
+	out, err := g.local(ctx, "log", "--reverse", "--max-count", strconv.Itoa(maxCompareCommits),
+		"--format=%H%x1f%B%x1e", base+"..FETCH_HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		sha, message, ok := strings.Cut(strings.TrimSpace(record), "\x1f")
+		if !ok {
+			continue
+		}
+		commits = append(commits, Commit{SHA: sha, Message: strings.TrimSpace(message)})
+	}
+	return commits, nil
+}
+
+// GetTree returns the full recursive tree of the remote at ref.
+func (g *Git) GetTree(ctx context.Context, owner, repo, ref string) (*Tree, error) {
+	commit, err := g.ResolveCommit(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if tree, ok := g.trees[commit]; ok {
+		return tree, nil
+	}
+
+	out, err := g.local(ctx, "ls-tree", "-r", "-t", "-z", "--full-tree", commit)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &Tree{SHA: commit}
+	for _, line := range strings.Split(out, "\x00") {
+		// Format: <mode> SP <type> SP <object> TAB <path>
+		meta, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 3 {
+			continue
+		}
+		tree.Entries = append(tree.Entries, TreeEntry{Path: path, Type: fields[1], SHA: fields[2]})
+	}
+
+	g.trees[commit] = tree
+	return tree, nil
+}
+
+// FetchFile reads a file from the remote at ref.
+func (g *Git) FetchFile(ctx context.Context, owner, repo, path, ref string) (*FetchResult, error) {
+	tree, err := g.GetTree(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := tree.Find(path)
+	if entry == nil || entry.Type != "blob" {
+		return nil, fmt.Errorf("%s: %w", path, ErrNotFound)
+	}
+
+	content, err := g.local(ctx, "cat-file", "blob", entry.SHA)
+	if err != nil {
+		return nil, err
+	}
+	return &FetchResult{Content: content, SHA: entry.SHA}, nil
+}
+
+// ListDirectory lists a directory in the remote at ref.
+// Returns nil, nil if the directory doesn't exist.
+func (g *Git) ListDirectory(ctx context.Context, owner, repo, path, ref string) ([]DirectoryEntry, error) {
+	tree, err := g.GetTree(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	return tree.List(path), nil
+}
+
+// GetDefaultBranch returns the branch the remote's HEAD points to.
+func (g *Git) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	out, err := g.git(ctx, "ls-remote", "--symref", g.url, "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		if target, ok := strings.CutPrefix(line, "ref: "); ok {
+			target, _, _ = strings.Cut(target, "\t")
+			return strings.TrimPrefix(target, "refs/heads/"), nil
+		}
+	}
+	return "", fmt.Errorf("could not determine default branch of %s", g.url)
+}
+
+// RepoExists checks if the remote is reachable.
+func (g *Git) RepoExists(ctx context.Context, owner, repo string) (bool, error) {
+	if _, err := g.git(ctx, "ls-remote", "--exit-code", g.url, "HEAD"); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return false, err // git isn't installed
+		}
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRemote creates a local git repository with a few files and a tag,
+// returning its file:// URL.
+func newTestRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(path, content string) {
+		full := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	run("init", "--quiet", "--initial-branch", "trunk")
+	write("CLAUDE.md", "# v1")
+	write("commands/review.md", "review")
+	run("add", "-A")
+	run("commit", "--quiet", "-m", "v1")
+	run("tag", "v1")
+
+	write("CLAUDE.md", "# v2")
+	run("commit", "--quiet", "-am", "v2")
+
+	return "file://" + dir
+}
+
+func TestGit(t *testing.T) {
+	remote := newTestRemote(t)
+	g := NewGit(remote, t.TempDir())
+	ctx := context.Background()
+
+	t.Run("default branch", func(t *testing.T) {
+		branch, err := g.GetDefaultBranch(ctx, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "trunk", branch)
+	})
+
+	t.Run("fetch file at head and at tag", func(t *testing.T) {
+		result, err := g.FetchFile(ctx, "", "", "CLAUDE.md", "")
+		require.NoError(t, err)
+		assert.Equal(t, "# v2", result.Content)
+		assert.NotEmpty(t, result.SHA)
+
+		result, err = g.FetchFile(ctx, "", "", "CLAUDE.md", "v1")
+		require.NoError(t, err)
+		assert.Equal(t, "# v1", result.Content)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := g.FetchFile(ctx, "", "", "missing.md", "")
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("list directory", func(t *testing.T) {
+		entries, err := g.ListDirectory(ctx, "", "", "commands", "trunk")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "review.md", entries[0].Name)
+		assert.Equal(t, "file", entries[0].Type)
+
+		entries, err = g.ListDirectory(ctx, "", "", "missing", "trunk")
+		require.NoError(t, err)
+		assert.Nil(t, entries)
+	})
+
+	t.Run("resolve commit is stable", func(t *testing.T) {
+		first, err := g.ResolveCommit(ctx, "", "", "v1")
+		require.NoError(t, err)
+		second, err := g.ResolveCommit(ctx, "", "", "v1")
+		require.NoError(t, err)
+		assert.Len(t, first, 40)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("resolve commit by SHA the server won't serve", func(t *testing.T) {
+		// Protocol v0 servers refuse unadvertised commits without allowReachableSHA1InWant
+		t.Setenv("GIT_CONFIG_COUNT", "1")
+		t.Setenv("GIT_CONFIG_KEY_0", "protocol.version")
+		t.Setenv("GIT_CONFIG_VALUE_0", "0")
+
+		out, err := exec.Command("git", "-C", strings.TrimPrefix(remote, "file://"), "rev-parse", "v1^{commit}").Output()
+		require.NoError(t, err)
+		want := strings.TrimSpace(string(out))
+
+		for _, ref := range []string{want, want[:7]} {
+			g := NewGit(remote, t.TempDir())
+			commit, err := g.ResolveCommit(ctx, "", "", ref)
+			require.NoError(t, err, ref)
+			assert.Equal(t, want, commit)
+
+			result, err := g.FetchFile(ctx, "", "", "CLAUDE.md", commit)
+			require.NoError(t, err)
+			assert.Equal(t, "# v1", result.Content)
+		}
+
+		g := NewGit(remote, t.TempDir())
+		_, err = g.ResolveCommit(ctx, "", "", "0123456789012345678901234567890123456789")
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("compare commits deepens the shallow fetch", func(t *testing.T) {
+		g := NewGit(remote, t.TempDir())
+		base, err := g.ResolveCommit(ctx, "", "", "v1")
+		require.NoError(t, err)
+
+		commits, err := g.CompareCommits(ctx, "", "", base, "trunk")
+		require.NoError(t, err)
+		require.Len(t, commits, 1)
+		assert.Equal(t, "v2", commits[0].Message)
+		assert.Len(t, commits[0].SHA, 40)
+
+		_, err = g.CompareCommits(ctx, "", "", "0123456789012345678901234567890123456789", "trunk")
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("repo exists", func(t *testing.T) {
+		exists, err := g.RepoExists(ctx, "", "")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		missing := NewGit("file://"+filepath.Join(t.TempDir(), "nope"), t.TempDir())
+		exists, err = missing.RepoExists(ctx, "", "")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestGit_SharedCacheDir(t *testing.T) {
+	remote := newTestRemote(t)
+	cacheDir := t.TempDir()
+	ctx := context.Background()
+
+	t.Run("sources on one remote share a provider", func(t *testing.T) {
+		f := &Factory{GitDir: cacheDir}
+		a, err := f.For(&config.RepoSpec{Provider: config.ProviderGit, URL: remote, Subdir: "a"})
+		require.NoError(t, err)
+		b, err := f.For(&config.RepoSpec{Provider: config.ProviderGit, URL: remote, Subdir: "b"})
+		require.NoError(t, err)
+		assert.Same(t, a, b)
+	})
+
+	t.Run("concurrent fetches into one cache repository", func(t *testing.T) {
+		// Two subdir sources through one factory, plus separate providers
+		// standing in for other processes
+		f := &Factory{GitDir: cacheDir}
+		var wg sync.WaitGroup
+		errs := make([]error, 8)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				spec := &config.RepoSpec{Provider: config.ProviderGit, URL: remote, Subdir: "a"}
+				ref := "v1"
+				if i%2 == 1 {
+					spec.Subdir, ref = "b", "trunk"
+				}
+				var p SourceProvider = NewGit(remote, cacheDir)
+				if i < 4 {
+					var err error
+					if p, err = f.For(spec); err != nil {
+						errs[i] = err
+						return
+					}
+				}
+				g := p.(*Git)
+				commit, err := g.ResolveCommit(ctx, "", "", ref)
+				if err == nil {
+					_, err = g.FetchFile(ctx, "", "", "CLAUDE.md", commit)
+				}
+				errs[i] = err
+			}(i)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+	})
+}
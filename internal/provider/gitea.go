@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+)
+
+// Gitea fetches sources through the Gitea (and Forgejo) REST API (v1).
+type Gitea struct {
+	api *apiClient
+}
+
+// NewGitea creates a Gitea provider for an API base URL such as
+// "https://gitea.com/api/v1". An empty token accesses public repos only.
+func NewGitea(baseURL, token string) *Gitea {
+	headers := map[string]string{}
+	if token != "" {
+		headers["Authorization"] = "token " + token
+	}
+	return &Gitea{api: newAPIClient(baseURL, headers)}
+}
+
+func (g *Gitea) repoPath(owner, repo string) string {
+	return fmt.Sprintf("repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+}
+
+// contentsPath returns the contents endpoint for path at ref.
+func (g *Gitea) contentsPath(owner, repo, path, ref string) string {
+	endpoint := g.repoPath(owner, repo) + "/contents/" + escapePath(path)
+	if ref != "" {
+		endpoint += "?ref=" + url.QueryEscape(ref)
+	}
+	return endpoint
+}
+
+// FetchFile fetches a file from a repo.
+func (g *Gitea) FetchFile(ctx context.Context, owner, repo, path, ref string) (*FetchResult, error) {
+	var response struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+		SHA      string `json:"sha"`
+	}
+	if _, err := g.api.get(ctx, g.contentsPath(owner, repo, path, ref), &response); err != nil {
+		return nil, err
+	}
+
+	content, err := base64.StdEncoding.DecodeString(response.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode content: %w", err)
+	}
+
+	return &FetchResult{Content: string(content), SHA: response.SHA}, nil
+}
+
+// ListDirectory lists a directory in a repo.
+// Returns nil, nil if the directory doesn't exist.
+func (g *Gitea) ListDirectory(ctx context.Context, owner, repo, path, ref string) ([]DirectoryEntry, error) {
+	var entries []DirectoryEntry
+	if _, err := g.api.get(ctx, g.contentsPath(owner, repo, path, ref), &entries); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := []DirectoryEntry{}
+	for _, e := range entries {
+		if e.Type == "file" || e.Type == "dir" {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// ResolveCommit returns the commit SHA that a branch, tag, or SHA points to.
+func (g *Gitea) ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error) {
+	var commits []struct {
+		SHA string `json:"sha"`
+	}
+	endpoint := fmt.Sprintf("%s/commits?sha=%s&limit=1&stat=false", g.repoPath(owner, repo), url.QueryEscape(ref))
+	if _, err := g.api.get(ctx, endpoint, &commits); err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("ref %q: %w", ref, ErrNotFound)
+	}
+	return commits[0].SHA, nil
+}
+
+// CompareCommits returns the commits reachable from head but not from base, oldest first.
+func (g *Gitea) CompareCommits(ctx context.Context, owner, repo, base, head string) ([]Commit, error) {
+	var response struct {
+		Commits []struct {
+			SHA    string `json:"sha"`
+			Commit struct {
+				Message string `json:"message"`
+			} `json:"commit"`
+		} `json:"commits"`
+	}
+	endpoint := fmt.Sprintf("%s/compare/%s...%s", g.repoPath(owner, repo), url.PathEscape(base), url.PathEscape(head))
+	if _, err := g.api.get(ctx, endpoint, &response); err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, len(response.Commits))
+	for i, c := range response.Commits {
+		commits[i] = Commit{SHA: c.SHA, Message: c.Commit.Message}
+	}
+	return commits, nil
+}
+
+// GetDefaultBranch returns the repo's default branch.
+func (g *Gitea) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	var response struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if _, err := g.api.get(ctx, g.repoPath(owner, repo), &response); err != nil {
+		return "", err
+	}
+	return response.DefaultBranch, nil
+}
+
+// RepoExists checks if a repo exists and is accessible.
+func (g *Gitea) RepoExists(ctx context.Context, owner, repo string) (bool, error) {
+	if _, err := g.api.get(ctx, g.repoPath(owner, repo), nil); err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
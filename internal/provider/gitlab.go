@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+)
+
+// gitLabPageSize is the page size for paginated GitLab listings (the API maximum).
+const gitLabPageSize = 100
+
+// GitLab fetches sources through the GitLab REST API (v4).
+// Owner is the project's group path, which may include subgroups.
+type GitLab struct {
+	api *apiClient
+}
+
+// NewGitLab creates a GitLab provider for an API base URL such as
+// "https://gitlab.com/api/v4". An empty token accesses public projects only.
+func NewGitLab(baseURL, token string) *GitLab {
+	headers := map[string]string{}
+	if token != "" {
+		headers["PRIVATE-TOKEN"] = token
+	}
+	return &GitLab{api: newAPIClient(baseURL, headers)}
+}
+
+// projectPath returns the URL-encoded project ID for owner/repo.
+func (g *GitLab) projectPath(owner, repo string) string {
+	return "projects/" + url.PathEscape(owner+"/"+repo)
+}
+
+// FetchFile fetches a file from a project.
+func (g *GitLab) FetchFile(ctx context.Context, owner, repo, path, ref string) (*FetchResult, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	endpoint := fmt.Sprintf("%s/repository/files/%s?ref=%s", g.projectPath(owner, repo), url.PathEscape(path), url.QueryEscape(ref))
+
+	var response struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+		BlobID   string `json:"blob_id"`
+	}
+	if _, err := g.api.get(ctx, endpoint, &response); err != nil {
+		return nil, err
+	}
+
+	content, err := base64.StdEncoding.DecodeString(response.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode content: %w", err)
+	}
+
+	return &FetchResult{Content: string(content), SHA: response.BlobID}, nil
+}
+
+// gitLabTreeEntry is an item in GitLab's repository tree listing.
+type gitLabTreeEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // "blob", "tree", or "commit"
+	Path string `json:"path"`
+}
+
+// listTree returns every page of a repository tree listing.
+func (g *GitLab) listTree(ctx context.Context, owner, repo, path, ref string, recursive bool) ([]gitLabTreeEntry, error) {
+	query := url.Values{}
+	query.Set("per_page", fmt.Sprint(gitLabPageSize))
+	if path != "" {
+		query.Set("path", path)
+	}
+	if ref != "" {
+		query.Set("ref", ref)
+	}
+	if recursive {
+		query.Set("recursive", "true")
+	}
+
+	var all []gitLabTreeEntry
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+		var entries []gitLabTreeEntry
+		header, err := g.api.get(ctx, g.projectPath(owner, repo)+"/repository/tree?"+query.Encode(), &entries)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, entries...)
+		page = header.Get("X-Next-Page")
+	}
+	return all, nil
+}
+
+// ListDirectory lists a directory in a project.
+// Returns nil, nil if the directory doesn't exist.
+func (g *GitLab) ListDirectory(ctx context.Context, owner, repo, path, ref string) ([]DirectoryEntry, error) {
+	entries, err := g.listTree(ctx, owner, repo, path, ref, false)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := []DirectoryEntry{}
+	for _, e := range entries {
+		entryType := "file"
+		if e.Type == "tree" {
+			entryType = "dir"
+		} else if e.Type != "blob" {
+			continue // Skip submodules
+		}
+		result = append(result, DirectoryEntry{Name: e.Name, Path: e.Path, Type: entryType, SHA: e.ID})
+	}
+	return result, nil
+}
+
+// GetTree returns the full recursive tree of a project at ref.
+func (g *GitLab) GetTree(ctx context.Context, owner, repo, ref string) (*Tree, error) {
+	entries, err := g.listTree(ctx, owner, repo, "", ref, true)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &Tree{SHA: ref}
+	for _, e := range entries {
+		tree.Entries = append(tree.Entries, TreeEntry{Path: e.Path, Type: e.Type, SHA: e.ID})
+	}
+	return tree, nil
+}
+
+// ResolveCommit returns the commit SHA that a branch, tag, or SHA points to.
+func (g *GitLab) ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error) {
+	var response struct {
+		ID string `json:"id"`
+	}
+	endpoint := g.projectPath(owner, repo) + "/repository/commits/" + url.PathEscape(ref)
+	if _, err := g.api.get(ctx, endpoint, &response); err != nil {
+		return "", err
+	}
+	return response.ID, nil
+}
+
+// CompareCommits returns the commits reachable from head but not from base, oldest first.
+func (g *GitLab) CompareCommits(ctx context.Context, owner, repo, base, head string) ([]Commit, error) {
+	var response struct {
+		Commits []struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+		} `json:"commits"`
+	}
+	query := url.Values{}
+	query.Set("from", base)
+	query.Set("to", head)
+	endpoint := g.projectPath(owner, repo) + "/repository/compare?" + query.Encode()
+	if _, err := g.api.get(ctx, endpoint, &response); err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, len(response.Commits))
+	for i, c := range response.Commits {
+		commits[i] = Commit{SHA: c.ID, Message: c.Message}
+	}
+	return commits, nil
+}
+
+// GetDefaultBranch returns the project's default branch.
+func (g *GitLab) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	var response struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if _, err := g.api.get(ctx, g.projectPath(owner, repo), &response); err != nil {
+		return "", err
+	}
+	return response.DefaultBranch, nil
+}
+
+// RepoExists checks if a project exists and is accessible.
+func (g *GitLab) RepoExists(ctx context.Context, owner, repo string) (bool, error) {
+	if _, err := g.api.get(ctx, g.projectPath(owner, repo), nil); err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiClient issues authenticated JSON requests to a provider's REST API.
+type apiClient struct {
+	baseURL string
+	headers map[string]string
+	http    *http.Client
+}
+
+func newAPIClient(baseURL string, headers map[string]string) *apiClient {
+	return &apiClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		headers: headers,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// get fetches endpoint (relative to the base URL) and decodes the JSON body into v.
+// Returns the response headers, or an *HTTPError for non-2xx responses.
+func (c *apiClient) get(ctx context.Context, endpoint string, v interface{}) (http.Header, error) {
+	reqURL := c.baseURL + "/" + strings.TrimPrefix(endpoint, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, val := range c.headers {
+		req.Header.Set(k, val)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		httpErr := &HTTPError{StatusCode: resp.StatusCode, URL: reqURL}
+		var body struct {
+			Message string `json:"message"`
+		}
+		if data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024)); err == nil {
+			if json.Unmarshal(data, &body) == nil {
+				httpErr.Message = body.Message
+			}
+		}
+		return nil, httpErr
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return nil, fmt.Errorf("failed to decode response from %s: %w", reqURL, err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// escapePath escapes each segment of a slash-separated repo path.
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/HartBrook/staghorn/internal/cache"
+)
+
+// Local reads sources from a directory on disk laid out like a source repo.
+// Refs don't apply: files are always read as they currently are.
+type Local struct {
+	root string
+}
+
+// NewLocal creates a provider for dir. A leading "~" is expanded to the home
+// directory and relative paths are resolved against the working directory;
+// sources from config arrive already resolved against the config file's directory.
+func NewLocal(dir string) (*Local, error) {
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Local{root: root}, nil
+}
+
+// resolve returns the on-disk path for a slash-separated repo path,
+// rejecting paths that would escape the root.
+func (l *Local) resolve(path string) (string, error) {
+	full := filepath.Join(l.root, filepath.FromSlash(path))
+	if full != l.root && !strings.HasPrefix(full, l.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside %s", path, l.root)
+	}
+	return full, nil
+}
+
+// FetchFile reads a file. The ref is ignored.
+func (l *Local) FetchFile(ctx context.Context, owner, repo, path, ref string) (*FetchResult, error) {
+	full, err := l.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(full)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s: %w", path, ErrNotFound)
+		}
+		return nil, err
+	}
+
+	content := string(data)
+	return &FetchResult{Content: content, SHA: cache.BlobSHA(content)}, nil
+}
+
+// ListDirectory lists a directory, skipping .git. The ref is ignored.
+// Returns nil, nil if the directory doesn't exist.
+func (l *Local) ListDirectory(ctx context.Context, owner, repo, path, ref string) ([]DirectoryEntry, error) {
+	full, err := l.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(full)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	prefix := strings.Trim(path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	result := []DirectoryEntry{}
+	for _, e := range entries {
+		if e.Name() == ".git" {
+			continue
+		}
+
+		// Stat follows symlinks, so linked files and directories are included
+		info, err := os.Stat(filepath.Join(full, e.Name()))
+		if err != nil {
+			continue
+		}
+		entryType := "file"
+		if info.IsDir() {
+			entryType = "dir"
+		} else if !info.Mode().IsRegular() {
+			continue
+		}
+
+		result = append(result, DirectoryEntry{Name: e.Name(), Path: prefix + e.Name(), Type: entryType})
+	}
+	return result, nil
+}
+
+// GetDefaultBranch returns an empty branch, since local sources have no refs.
+func (l *Local) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	return "", nil
+}
+
+// RepoExists checks if the directory exists.
+func (l *Local) RepoExists(ctx context.Context, owner, repo string) (bool, error) {
+	info, err := os.Stat(l.root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return info.IsDir(), nil
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HartBrook/staghorn/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "rules", "api"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Team"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "rules", "security.md"), []byte("rule"), 0644))
+
+	l, err := NewLocal(root)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("fetch file", func(t *testing.T) {
+		result, err := l.FetchFile(ctx, "", "", "CLAUDE.md", "")
+		require.NoError(t, err)
+		assert.Equal(t, "# Team", result.Content)
+		assert.Equal(t, cache.BlobSHA("# Team"), result.SHA)
+
+		_, err = l.FetchFile(ctx, "", "", "missing.md", "")
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("rejects paths outside the root", func(t *testing.T) {
+		_, err := l.FetchFile(ctx, "", "", "../outside.md", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("list directory", func(t *testing.T) {
+		entries, err := l.ListDirectory(ctx, "", "", "rules", "")
+		require.NoError(t, err)
+		assert.Equal(t, []DirectoryEntry{
+			{Name: "api", Path: "rules/api", Type: "dir"},
+			{Name: "security.md", Path: "rules/security.md", Type: "file"},
+		}, entries)
+
+		entries, err = l.ListDirectory(ctx, "", "", "", "")
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.NotEqual(t, ".git", e.Name)
+		}
+
+		entries, err = l.ListDirectory(ctx, "", "", "missing", "")
+		require.NoError(t, err)
+		assert.Nil(t, entries)
+	})
+
+	t.Run("home expansion", func(t *testing.T) {
+		t.Setenv("HOME", root)
+		home, err := NewLocal("~/rules")
+		require.NoError(t, err)
+		exists, err := home.RepoExists(ctx, "", "")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+}
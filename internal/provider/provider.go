@@ -0,0 +1,196 @@
+// Package provider abstracts the hosting services staghorn fetches sources from.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/github"
+)
+
+// Shared result types. GitHub's shapes are used as the common vocabulary so the
+// GitHub client satisfies SourceProvider without an adapter.
+type (
+	FetchResult    = github.FetchResult
+	DirectoryEntry = github.DirectoryEntry
+	Tree           = github.Tree
+	TreeEntry      = github.TreeEntry
+	Commit         = github.Commit
+)
+
+// SourceProvider fetches files from a source repository.
+// Implementations bound to a single remote (such as Git) ignore owner and repo.
+type SourceProvider interface {
+	// FetchFile fetches a file at ref (empty ref means the default branch).
+	FetchFile(ctx context.Context, owner, repo, path, ref string) (*FetchResult, error)
+
+	// ListDirectory lists a directory at ref. Returns nil, nil if it doesn't exist.
+	ListDirectory(ctx context.Context, owner, repo, path, ref string) ([]DirectoryEntry, error)
+
+	// GetDefaultBranch returns the repo's default branch.
+	GetDefaultBranch(ctx context.Context, owner, repo string) (string, error)
+
+	// RepoExists checks if the repo exists and is accessible.
+	RepoExists(ctx context.Context, owner, repo string) (bool, error)
+}
+
+// CommitResolver is implemented by providers that can resolve a ref to a commit SHA,
+// letting sync fetch every file from a single commit.
+type CommitResolver interface {
+	ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error)
+}
+
+// CommitLister is implemented by providers that can list the commits between
+// two commits, used to show what changed upstream.
+type CommitLister interface {
+	// CompareCommits returns the commits reachable from head but not from base, oldest first.
+	CompareCommits(ctx context.Context, owner, repo, base, head string) ([]Commit, error)
+}
+
+// TreeLister is implemented by providers that can list a whole repo in one call.
+type TreeLister interface {
+	GetTree(ctx context.Context, owner, repo, ref string) (*Tree, error)
+}
+
+// ConditionalFetcher is implemented by providers that support ETag-based fetches.
+type ConditionalFetcher interface {
+	FetchFileIfChanged(ctx context.Context, owner, repo, path, ref, etag string) (*FetchResult, error)
+}
+
+// ErrNotFound is returned when a repo, ref, or path doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// HTTPError is a non-success response from a provider's HTTP API.
+type HTTPError struct {
+	StatusCode int
+	URL        string
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("HTTP %d: %s (%s)", e.StatusCode, e.Message, e.URL)
+	}
+	return fmt.Sprintf("HTTP %d (%s)", e.StatusCode, e.URL)
+}
+
+// IsNotFound returns true if err means the requested repo, ref, or path doesn't exist.
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrNotFound) || github.IsNotFoundError(err) {
+		return true
+	}
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == 404
+}
+
+// Environment variables holding tokens for non-GitHub providers.
+const (
+	EnvGitLabToken = "STAGHORN_GITLAB_TOKEN"
+	EnvGiteaToken  = "STAGHORN_GITEA_TOKEN"
+)
+
+// tokenFromEnv returns the first non-empty environment variable.
+func tokenFromEnv(names ...string) string {
+	for _, name := range names {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// Factory creates providers for repo specs, sharing clients between sources.
+type Factory struct {
+	// GitHub creates the client for a GitHub host ("" for github.com). It is
+	// called at most once per host, on first use.
+	GitHub func(host string) (*github.Client, error)
+
+	// GitHubHost is the host for GitHub sources that don't name one ("" for github.com).
+	GitHubHost string
+
+	// GitDir is where plain git remotes are cloned.
+	GitDir string
+
+	mu        sync.Mutex
+	ghClients map[string]*github.Client
+	remotes   map[string]*Git
+}
+
+// For returns the provider for a spec.
+func (f *Factory) For(spec *config.RepoSpec) (SourceProvider, error) {
+	switch spec.Provider {
+	case config.ProviderGitHub, "":
+		host := spec.Host
+		if host == "" {
+			host = f.GitHubHost
+		}
+		return f.github(host)
+	case config.ProviderGitLab:
+		host := spec.Host
+		if host == "" {
+			host = config.DefaultGitLabHost
+		}
+		return NewGitLab("https://"+host+"/api/v4", tokenFromEnv(EnvGitLabToken, "GITLAB_TOKEN")), nil
+	case config.ProviderGitea:
+		return NewGitea("https://"+spec.Host+"/api/v1", tokenFromEnv(EnvGiteaToken, "GITEA_TOKEN")), nil
+	case config.ProviderGit:
+		return f.git(spec.URL), nil
+	case config.ProviderLocal:
+		local, err := NewLocal(spec.Path)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(local.root); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("local source %s is not a directory", spec.Path)
+		}
+		return local, nil
+	default:
+		return nil, fmt.Errorf("unsupported source provider %q", spec.Provider)
+	}
+}
+
+// git returns the provider for a git remote. Sources on one remote share a
+// provider, since they fetch into the same cache repository.
+func (f *Factory) git(url string) *Git {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if g, ok := f.remotes[url]; ok {
+		return g
+	}
+	if f.remotes == nil {
+		f.remotes = make(map[string]*Git)
+	}
+	g := NewGit(url, f.GitDir)
+	f.remotes[url] = g
+	return g
+}
+
+func (f *Factory) github(host string) (SourceProvider, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if client, ok := f.ghClients[host]; ok {
+		return client, nil
+	}
+	if f.GitHub == nil {
+		return nil, fmt.Errorf("no GitHub client configured")
+	}
+
+	client, err := f.GitHub(host)
+	if err != nil {
+		return nil, err
+	}
+	if f.ghClients == nil {
+		f.ghClients = make(map[string]*github.Client)
+	}
+	f.ghClients[host] = client
+	return client, nil
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HartBrook/staghorn/internal/config"
+	"github.com/HartBrook/staghorn/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNotFound(t *testing.T) {
+	assert.False(t, IsNotFound(nil))
+	assert.True(t, IsNotFound(ErrNotFound))
+	assert.True(t, IsNotFound(fmt.Errorf("wrapped: %w", ErrNotFound)))
+	assert.True(t, IsNotFound(&HTTPError{StatusCode: 404}))
+	assert.False(t, IsNotFound(&HTTPError{StatusCode: 500}))
+}
+
+func TestFactoryFor(t *testing.T) {
+	f := &Factory{GitDir: t.TempDir()}
+
+	tests := []struct {
+		repo string
+		want interface{}
+	}{
+		{"gitlab:acme/standards", &GitLab{}},
+		{"gitea:git.example.com/acme/standards", &Gitea{}},
+		{"https://bitbucket.org/acme/standards.git", &Git{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			spec, err := config.ParseRepoSpec(tt.repo)
+			require.NoError(t, err)
+
+			p, err := f.For(spec)
+			require.NoError(t, err)
+			assert.IsType(t, tt.want, p)
+		})
+	}
+
+	t.Run("github clients are shared per host", func(t *testing.T) {
+		var hosts []string
+		f := &Factory{
+			GitHubHost: "ghe.example.com",
+			GitHub: func(host string) (*github.Client, error) {
+				hosts = append(hosts, host)
+				return &github.Client{}, nil
+			},
+		}
+
+		for _, repo := range []string{"acme/a", "acme/b", "github:ghe.other.com/acme/c"} {
+			spec, err := config.ParseRepoSpec(repo)
+			require.NoError(t, err)
+			_, err = f.For(spec)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"ghe.example.com", "ghe.other.com"}, hosts)
+	})
+
+	t.Run("github without client factory", func(t *testing.T) {
+		spec, err := config.ParseRepoSpec("acme/standards")
+		require.NoError(t, err)
+
+		_, err = f.For(spec)
+		assert.Error(t, err)
+	})
+}
+
+func TestGitLab(t *testing.T) {
+	content := base64.StdEncoding.EncodeToString([]byte("# Team"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/acme%2Fai%2Fstandards":
+			fmt.Fprint(w, `{"default_branch":"main"}`)
+		case "/api/v4/projects/acme%2Fai%2Fstandards/repository/files/CLAUDE.md":
+			assert.Equal(t, "v1", r.URL.Query().Get("ref"))
+			fmt.Fprintf(w, `{"content":%q,"encoding":"base64","blob_id":"blob1"}`, content)
+		case "/api/v4/projects/acme%2Fai%2Fstandards/repository/tree":
+			// Two pages to exercise pagination
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[{"id":"t1","name":"rules","type":"tree","path":"commands/rules"}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"id":"b1","name":"review.md","type":"blob","path":"commands/review.md"},
+				{"id":"c1","name":"vendored","type":"commit","path":"commands/vendored"}]`)
+		case "/api/v4/projects/acme%2Fai%2Fstandards/repository/commits/v1":
+			fmt.Fprint(w, `{"id":"abc123"}`)
+		case "/api/v4/projects/acme%2Fai%2Fstandards/repository/compare":
+			assert.Equal(t, "abc123", r.URL.Query().Get("from"))
+			assert.Equal(t, "def456", r.URL.Query().Get("to"))
+			fmt.Fprint(w, `{"commits":[{"id":"def456","message":"Add security section"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"404 Project Not Found"}`)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	g := NewGitLab(server.URL+"/api/v4", "secret")
+	ctx := context.Background()
+
+	t.Run("fetch file", func(t *testing.T) {
+		result, err := g.FetchFile(ctx, "acme/ai", "standards", "CLAUDE.md", "v1")
+		require.NoError(t, err)
+		assert.Equal(t, "# Team", result.Content)
+		assert.Equal(t, "blob1", result.SHA)
+	})
+
+	t.Run("list directory follows pages and skips submodules", func(t *testing.T) {
+		entries, err := g.ListDirectory(ctx, "acme/ai", "standards", "commands", "")
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, DirectoryEntry{Name: "rules", Path: "commands/rules", Type: "dir", SHA: "t1"}, entries[0])
+		assert.Equal(t, "file", entries[1].Type)
+	})
+
+	t.Run("resolve commit", func(t *testing.T) {
+		commit, err := g.ResolveCommit(ctx, "acme/ai", "standards", "v1")
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", commit)
+	})
+
+	t.Run("compare commits", func(t *testing.T) {
+		commits, err := g.CompareCommits(ctx, "acme/ai", "standards", "abc123", "def456")
+		require.NoError(t, err)
+		assert.Equal(t, []Commit{{SHA: "def456", Message: "Add security section"}}, commits)
+	})
+
+	t.Run("default branch", func(t *testing.T) {
+		branch, err := g.GetDefaultBranch(ctx, "acme/ai", "standards")
+		require.NoError(t, err)
+		assert.Equal(t, "main", branch)
+	})
+
+	t.Run("missing project", func(t *testing.T) {
+		exists, err := g.RepoExists(ctx, "acme", "missing")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		_, err = g.FetchFile(ctx, "acme", "missing", "CLAUDE.md", "")
+		assert.True(t, IsNotFound(err))
+	})
+}
+
+func TestGitea(t *testing.T) {
+	content := base64.StdEncoding.EncodeToString([]byte("# Team"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/acme/standards", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"default_branch":"trunk"}`)
+	})
+	mux.HandleFunc("/api/v1/repos/acme/standards/contents/CLAUDE.md", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		fmt.Fprintf(w, `{"type":"file","content":%q,"sha":"blob1"}`, content)
+	})
+	mux.HandleFunc("/api/v1/repos/acme/standards/contents/commands", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name":"review.md","path":"commands/review.md","type":"file","sha":"b1"},
+			{"name":"link","path":"commands/link","type":"symlink","sha":"s1"}]`)
+	})
+	mux.HandleFunc("/api/v1/repos/acme/standards/commits", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "v1", r.URL.Query().Get("sha"))
+		fmt.Fprint(w, `[{"sha":"abc123"}]`)
+	})
+	mux.HandleFunc("/api/v1/repos/acme/standards/compare/abc123...def456", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"total_commits":1,"commits":[{"sha":"def456","commit":{"message":"Add security section"}}]}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	g := NewGitea(server.URL+"/api/v1", "secret")
+	ctx := context.Background()
+
+	result, err := g.FetchFile(ctx, "acme", "standards", "CLAUDE.md", "")
+	require.NoError(t, err)
+	assert.Equal(t, "# Team", result.Content)
+	assert.Equal(t, "blob1", result.SHA)
+
+	entries, err := g.ListDirectory(ctx, "acme", "standards", "commands", "")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "review.md", entries[0].Name)
+
+	entries, err = g.ListDirectory(ctx, "acme", "standards", "missing", "")
+	require.NoError(t, err)
+	assert.Nil(t, entries)
+
+	commit, err := g.ResolveCommit(ctx, "acme", "standards", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", commit)
+
+	commits, err := g.CompareCommits(ctx, "acme", "standards", "abc123", "def456")
+	require.NoError(t, err)
+	assert.Equal(t, []Commit{{SHA: "def456", Message: "Add security section"}}, commits)
+
+	branch, err := g.GetDefaultBranch(ctx, "acme", "standards")
+	require.NoError(t, err)
+	assert.Equal(t, "trunk", branch)
+}
@@ -0,0 +1,139 @@
+package rules
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Matches reports whether the rule applies to file, a slash-separated path
+// relative to the project root. Returns the first pattern that matched, or ""
+// for a rule without paths, which applies to every file.
+func (r *Rule) Matches(file string) (string, bool) {
+	if !r.HasPathScope() {
+		return "", true
+	}
+	for _, pattern := range r.Frontmatter.Paths {
+		if MatchGlob(pattern, file) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// MatchGlob reports whether name matches a rule path pattern the way Claude
+// Code applies them: "*", "?", and "[...]" match within a path segment, "**"
+// matches any number of segments, and "{a,b}" matches either alternative.
+// An invalid pattern matches nothing.
+func MatchGlob(pattern, name string) bool {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	for _, p := range expandBraces(pattern) {
+		p = strings.TrimPrefix(p, "./")
+		if matchSegments(strings.Split(p, "/"), strings.Split(name, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateGlob checks a rule path pattern against the syntax MatchGlob
+// accepts. Besides malformed brackets and braces, it rejects absolute
+// patterns, since paths are relative to the project root, and "**" mixed
+// into a segment, as in "src/**.ts", which matches a single level like "*".
+func ValidateGlob(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	if strings.HasPrefix(pattern, "/") {
+		return fmt.Errorf("%q is absolute; patterns are relative to the project root", pattern)
+	}
+	if strings.Count(pattern, "{") != strings.Count(pattern, "}") {
+		return fmt.Errorf("%q has unbalanced braces", pattern)
+	}
+	for _, p := range expandBraces(pattern) {
+		for _, segment := range strings.Split(strings.TrimPrefix(p, "./"), "/") {
+			if segment != "**" && strings.Contains(segment, "**") {
+				return fmt.Errorf("%q: ** must be a whole path segment (did you mean %q?)", pattern, strings.Replace(pattern, "**", "**/*", 1))
+			}
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("%q has a malformed character class", pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidatePaths checks every paths pattern with ValidateGlob.
+func (r *Rule) ValidatePaths() error {
+	for _, pattern := range r.Frontmatter.Paths {
+		if err := ValidateGlob(pattern); err != nil {
+			return fmt.Errorf("invalid paths pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// matchSegments matches path segments against pattern segments, where a "**"
+// segment matches zero or more path segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse repeated ** and try every split point
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range name {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// expandBraces expands the first {a,b} group in pattern, recursively, into
+// the patterns it stands for. Unbalanced braces are left as is.
+func expandBraces(pattern string) []string {
+	open := strings.IndexByte(pattern, '{')
+	if open < 0 {
+		return []string{pattern}
+	}
+
+	depth := 0
+	var alternatives []string
+	start := open + 1
+	for i := open; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[start:i])
+				start = i + 1
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				alternatives = append(alternatives, pattern[start:i])
+				var result []string
+				for _, alt := range alternatives {
+					result = append(result, expandBraces(pattern[:open]+alt+pattern[i+1:])...)
+				}
+				return result
+			}
+		}
+	}
+	return []string{pattern}
+}
@@ -0,0 +1,77 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"src/api/**/*.go", "src/api/users.go", true},
+		{"src/api/**/*.go", "src/api/v1/users/handler.go", true},
+		{"src/api/**/*.go", "src/web/users.go", false},
+		{"src/api/**", "src/api/v1/users.go", true},
+		{"**/*.tsx", "app/page.tsx", true},
+		{"**/*.tsx", "page.tsx", true},
+		{"*.go", "cmd/main.go", false},
+		{"*.go", "main.go", true},
+		{"src/**/*.{ts,tsx}", "src/components/Button.tsx", true},
+		{"src/**/*.{ts,tsx}", "src/components/Button.jsx", false},
+		{"{app,src}/**/test_*.py", "app/tests/test_models.py", true},
+		{"./docs/*.md", "docs/intro.md", true},
+		{"docs/?.md", "docs/a.md", true},
+		{"docs/[ab].md", "docs/c.md", false},
+		{"docs/[.md", "docs/[.md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	scoped := &Rule{Frontmatter: Frontmatter{Paths: []string{"api/**/*.go", "internal/api/**"}}}
+
+	if pattern, ok := scoped.Matches("internal/api/server.go"); !ok || pattern != "internal/api/**" {
+		t.Errorf("Matches() = %q, %v, want the second pattern", pattern, ok)
+	}
+	if _, ok := scoped.Matches("cmd/main.go"); ok {
+		t.Error("Matches() should be false for a file outside the patterns")
+	}
+
+	global := &Rule{}
+	if pattern, ok := global.Matches("cmd/main.go"); !ok || pattern != "" {
+		t.Errorf("Matches() = %q, %v, want a rule without paths to match everything", pattern, ok)
+	}
+}
+
+func TestValidateGlob(t *testing.T) {
+	valid := []string{"src/**/*.ts", "**/*.{ts,tsx}", "./docs/*.md", "api/[a-z]*.go", "*"}
+	for _, pattern := range valid {
+		if err := ValidateGlob(pattern); err != nil {
+			t.Errorf("ValidateGlob(%q) = %v, want nil", pattern, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"":              "empty",
+		"/src/**":       "absolute",
+		"src/api/**.ts": "src/api/**/*.ts",
+		"src/{a,b":      "unbalanced",
+		"docs/[a-.md":   "character class",
+	}
+	for pattern, want := range invalid {
+		err := ValidateGlob(pattern)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateGlob(%q) = %v, want error mentioning %q", pattern, err, want)
+		}
+	}
+}
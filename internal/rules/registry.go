@@ -1,6 +1,9 @@
 package rules
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+)
 
 // Registry manages rules from multiple sources with precedence handling.
 // Precedence (highest to lowest): project > personal > team
@@ -69,6 +72,35 @@ func (r *Registry) Get(relPath string) *Rule {
 	return r.rules[relPath]
 }
 
+// AllVersions returns every version of a rule across sources, highest
+// precedence (the one in use) first.
+func (r *Registry) AllVersions(relPath string) []*Rule {
+	var versions []*Rule
+	for _, rules := range r.bySource {
+		for _, rule := range rules {
+			if rule.RelPath == relPath {
+				versions = append(versions, rule)
+			}
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return sourcePrecedence(versions[i].Source) > sourcePrecedence(versions[j].Source)
+	})
+	return versions
+}
+
+// Match returns the rules that apply to file, a slash-separated path relative
+// to the project root, judged by the paths of the version in use.
+func (r *Registry) Match(file string) []*Rule {
+	var matched []*Rule
+	for _, rule := range r.All() {
+		if _, ok := rule.Matches(file); ok {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
 // BySource returns all rules from a specific source.
 func (r *Registry) BySource(source Source) []*Rule {
 	return r.bySource[source]
@@ -81,41 +113,65 @@ func (r *Registry) Count() int {
 
 // LoadRegistry creates a registry by loading rules from all sources.
 // Empty string for any directory means that source is not available.
+// Rules that fail to parse or have invalid paths patterns are skipped; they
+// are reported in a *ParseErrors alongside the registry of every rule that
+// did load, so one bad file never hides the rest.
 func LoadRegistry(teamDir, personalDir, projectDir string) (*Registry, error) {
 	registry := NewRegistry()
-
-	// Load team rules (lowest precedence)
-	if teamDir != "" {
-		teamRules, err := LoadFromDirectory(teamDir, SourceTeam)
-		if err != nil {
+	var skipped []error
+
+	// Team rules have the lowest precedence, project rules the highest
+	for _, src := range []struct {
+		dir    string
+		source Source
+	}{
+		{teamDir, SourceTeam},
+		{personalDir, SourcePersonal},
+		{projectDir, SourceProject},
+	} {
+		if src.dir == "" {
+			continue
+		}
+		loaded, err := LoadValidFromDirectory(src.dir, src.source)
+		if parseErrs, ok := err.(*ParseErrors); ok {
+			skipped = append(skipped, parseErrs.Errors...)
+		} else if err != nil {
 			return nil, err
 		}
-		for _, rule := range teamRules {
+		for _, rule := range loaded {
 			registry.Add(rule)
 		}
 	}
 
-	// Load personal rules (middle precedence)
-	if personalDir != "" {
-		personalRules, err := LoadFromDirectory(personalDir, SourcePersonal)
-		if err != nil {
-			return nil, err
-		}
-		for _, rule := range personalRules {
-			registry.Add(rule)
-		}
+	if len(skipped) > 0 {
+		return registry, &ParseErrors{Errors: skipped}
 	}
+	return registry, nil
+}
 
-	// Load project rules (highest precedence)
-	if projectDir != "" {
-		projectRules, err := LoadFromDirectory(projectDir, SourceProject)
-		if err != nil {
-			return nil, err
-		}
-		for _, rule := range projectRules {
-			registry.Add(rule)
+// LoadValidFromDirectory loads rules like LoadFromDirectory, and also skips
+// rules whose paths patterns are invalid, since Claude Code can't apply them.
+// Skipped files are reported in the returned *ParseErrors.
+func LoadValidFromDirectory(dir string, source Source) ([]*Rule, error) {
+	loaded, err := LoadFromDirectory(dir, source)
+	var skipped []error
+	if parseErrs, ok := err.(*ParseErrors); ok {
+		skipped = parseErrs.Errors
+	} else if err != nil {
+		return nil, err
+	}
+
+	var valid []*Rule
+	for _, rule := range loaded {
+		if err := rule.ValidatePaths(); err != nil {
+			skipped = append(skipped, fmt.Errorf("skipped %s: %w", rule.FilePath, err))
+			continue
 		}
+		valid = append(valid, rule)
 	}
 
-	return registry, nil
+	if len(skipped) > 0 {
+		return valid, &ParseErrors{Errors: skipped}
+	}
+	return valid, nil
 }
@@ -176,6 +176,29 @@ func TestLoadRegistry(t *testing.T) {
 	}
 }
 
+func TestLoadRegistry_SkipsInvalidRules(t *testing.T) {
+	teamDir := t.TempDir()
+	files := map[string]string{
+		"security.md": "# Security",
+		"api.md":      "---\npaths:\n  - \"src/api/**.ts\"\n---\n# API",
+		"broken.md":   "---\npaths: [oops\n---\n# Broken",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(teamDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry, err := LoadRegistry(teamDir, "", "")
+	parseErrs, ok := err.(*ParseErrors)
+	if !ok || len(parseErrs.Errors) != 2 {
+		t.Fatalf("LoadRegistry() error = %v, want ParseErrors for api.md and broken.md", err)
+	}
+	if registry == nil || registry.Count() != 1 || registry.Get("security.md") == nil {
+		t.Errorf("registry should keep the valid rule, got %v", registry)
+	}
+}
+
 func TestLoadRegistry_EmptyDirs(t *testing.T) {
 	registry, err := LoadRegistry("", "", "")
 	if err != nil {
@@ -252,3 +275,27 @@ func TestSourcePrecedence(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistry_AllVersionsAndMatch(t *testing.T) {
+	r := NewRegistry()
+	team := &Rule{RelPath: "api.md", Source: SourceTeam, Frontmatter: Frontmatter{Paths: []string{"src/api/**"}}}
+	personal := &Rule{RelPath: "api.md", Source: SourcePersonal, Frontmatter: Frontmatter{Paths: []string{"api/**"}}}
+	global := &Rule{RelPath: "security.md", Source: SourceTeam}
+	r.Add(team)
+	r.Add(global)
+	r.Add(personal)
+
+	versions := r.AllVersions("api.md")
+	if len(versions) != 2 || versions[0] != personal || versions[1] != team {
+		t.Errorf("AllVersions() = %v, want personal then team", versions)
+	}
+
+	// The personal version wins, so its paths decide
+	matched := r.Match("src/api/users.go")
+	if len(matched) != 1 || matched[0] != global {
+		t.Errorf("Match(src/api/users.go) = %v, want only security.md", matched)
+	}
+	if matched := r.Match("api/users.go"); len(matched) != 2 {
+		t.Errorf("Match(api/users.go) = %v, want api.md and security.md", matched)
+	}
+}
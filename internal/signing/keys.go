@@ -0,0 +1,217 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrNoMatchingKey is returned when a signature is well formed but wasn't made
+// by any pinned key.
+var ErrNoMatchingKey = errors.New("not signed by a pinned key")
+
+// Key is a pinned public key that can verify a checksums signature.
+type Key interface {
+	// Verify checks signature over message. Signatures in another key's format
+	// or made by a different key return ErrNoMatchingKey.
+	Verify(message, signature []byte) error
+}
+
+// ParseKey parses a pinned public key: an SSH public key in authorized_keys
+// format ("ssh-ed25519 AAAA... comment") or a minisign public key ("RWQ...").
+func ParseKey(s string) (Key, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty key")
+	}
+
+	if strings.HasPrefix(s, "ssh-") || strings.HasPrefix(s, "ecdsa-") || strings.HasPrefix(s, "sk-") {
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSH public key: %w", err)
+		}
+		return &sshKey{pub: pub}, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != "Ed" {
+		return nil, fmt.Errorf("unrecognized key %q: expected an SSH public key or a minisign public key", truncate(s))
+	}
+	k := &minisignKey{pub: ed25519.PublicKey(raw[10:])}
+	copy(k.id[:], raw[2:10])
+	return k, nil
+}
+
+// ParseKeys parses every pinned key, failing on the first invalid one.
+func ParseKeys(keys []string) ([]Key, error) {
+	parsed := make([]Key, 0, len(keys))
+	for _, s := range keys {
+		k, err := ParseKey(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, k)
+	}
+	return parsed, nil
+}
+
+// VerifyAny checks that signature over message was made by one of keys.
+func VerifyAny(message, signature []byte, keys []Key) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("no keys pinned")
+	}
+	var malformed error
+	for _, k := range keys {
+		err := k.Verify(message, signature)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrNoMatchingKey) && malformed == nil {
+			malformed = err
+		}
+	}
+	if malformed != nil {
+		return malformed
+	}
+	return ErrNoMatchingKey
+}
+
+func truncate(s string) string {
+	if len(s) > 16 {
+		return s[:16] + "..."
+	}
+	return s
+}
+
+// sshKey verifies signatures made with 'ssh-keygen -Y sign -n staghorn'.
+type sshKey struct {
+	pub ssh.PublicKey
+}
+
+// sshSignature is the SSHSIG blob after its magic preamble (PROTOCOL.sshsig).
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData is what an SSHSIG signature actually signs, after the preamble.
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+const sshMagic = "SSHSIG"
+
+func (k *sshKey) Verify(message, signature []byte) error {
+	block, _ := pem.Decode(signature)
+	if block == nil {
+		return ErrNoMatchingKey // Not an SSH signature
+	}
+	if block.Type != "SSH SIGNATURE" {
+		return fmt.Errorf("unexpected %s block in SSH signature", block.Type)
+	}
+	if !bytes.HasPrefix(block.Bytes, []byte(sshMagic)) {
+		return fmt.Errorf("malformed SSH signature")
+	}
+
+	var sig sshSignature
+	if err := ssh.Unmarshal(block.Bytes[len(sshMagic):], &sig); err != nil {
+		return fmt.Errorf("malformed SSH signature: %w", err)
+	}
+	if sig.Version != 1 {
+		return fmt.Errorf("unsupported SSH signature version %d", sig.Version)
+	}
+	if sig.Namespace != Namespace {
+		return fmt.Errorf("SSH signature namespace is %q, expected %q", sig.Namespace, Namespace)
+	}
+	if !bytes.Equal(sig.PublicKey, k.pub.Marshal()) {
+		return ErrNoMatchingKey
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha512":
+		h = sha512.New()
+	case "sha256":
+		h = sha256.New()
+	default:
+		return fmt.Errorf("unsupported SSH signature hash %q", sig.HashAlgorithm)
+	}
+	h.Write(message)
+
+	var inner ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &inner); err != nil {
+		return fmt.Errorf("malformed SSH signature: %w", err)
+	}
+	signed := append([]byte(sshMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...)
+	if err := k.pub.Verify(signed, &inner); err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	return nil
+}
+
+// minisignKey verifies signatures made with minisign (https://jedisct1.github.io/minisign/).
+type minisignKey struct {
+	id  [8]byte
+	pub ed25519.PublicKey
+}
+
+func (k *minisignKey) Verify(message, signature []byte) error {
+	lines := strings.Split(strings.ReplaceAll(string(signature), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[0], "untrusted comment:") {
+		return ErrNoMatchingKey // Not a minisign signature
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("malformed minisign signature")
+	}
+	if !bytes.Equal(sig[2:10], k.id[:]) {
+		return ErrNoMatchingKey
+	}
+
+	signed := message
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED": // Prehashed, the default since minisign 0.10
+		sum := blake2b.Sum512(message)
+		signed = sum[:]
+	default:
+		return fmt.Errorf("unsupported minisign signature algorithm %q", sig[:2])
+	}
+	if !ed25519.Verify(k.pub, signed, sig[10:]) {
+		return fmt.Errorf("invalid minisign signature")
+	}
+
+	// The trusted comment is signed too, so it can't be swapped
+	trusted, ok := strings.CutPrefix(lines[2], "trusted comment: ")
+	if !ok {
+		return fmt.Errorf("malformed minisign signature: missing trusted comment")
+	}
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || !ed25519.Verify(k.pub, append(append([]byte{}, sig[10:]...), trusted...), global) {
+		return fmt.Errorf("invalid minisign trusted comment signature")
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+// minisignPair generates a minisign-format key and returns its public key string
+// and a function that signs messages the way 'minisign -S' does.
+func minisignPair(t *testing.T, prehashed bool) (string, func(message []byte) []byte) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	id := []byte("keyid123")
+
+	pubKey := base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), id...), pub...))
+	sign := func(message []byte) []byte {
+		alg, signed := "Ed", message
+		if prehashed {
+			sum := blake2b.Sum512(message)
+			alg, signed = "ED", sum[:]
+		}
+		sig := ed25519.Sign(priv, signed)
+		trusted := "timestamp:1700000000"
+		global := ed25519.Sign(priv, append(append([]byte{}, sig...), trusted...))
+		return []byte("untrusted comment: signature from minisign secret key\n" +
+			base64.StdEncoding.EncodeToString(append(append([]byte(alg), id...), sig...)) + "\n" +
+			"trusted comment: " + trusted + "\n" +
+			base64.StdEncoding.EncodeToString(global) + "\n")
+	}
+	return pubKey, sign
+}
+
+func TestVerify_SSHKeygenFixture(t *testing.T) {
+	message := readFixture(t, "checksums.json")
+	signature := readFixture(t, "checksums.json.sig")
+
+	key, err := ParseKey(string(readFixture(t, "ssh_ed25519.pub")))
+	require.NoError(t, err)
+	require.NoError(t, VerifyAny(message, signature, []Key{key}))
+
+	t.Run("tampered message", func(t *testing.T) {
+		tampered := []byte(strings.Replace(string(message), "CLAUDE.md", "CLAUDE.mx", 1))
+		err := VerifyAny(tampered, signature, []Key{key})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid SSH signature")
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		other, _ := minisignPair(t, false)
+		otherKey, err := ParseKey(other)
+		require.NoError(t, err)
+		err = VerifyAny(message, signature, []Key{otherKey})
+		assert.True(t, errors.Is(err, ErrNoMatchingKey))
+	})
+}
+
+func TestVerify_Minisign(t *testing.T) {
+	for _, prehashed := range []bool{false, true} {
+		pub, sign := minisignPair(t, prehashed)
+		key, err := ParseKey(pub)
+		require.NoError(t, err)
+
+		message := []byte(`{"version":1,"files":{}}`)
+		signature := sign(message)
+		require.NoError(t, VerifyAny(message, signature, []Key{key}))
+
+		err = VerifyAny([]byte(`{"version":1,"files":{"x":"y"}}`), signature, []Key{key})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid minisign signature")
+
+		// Swapping the trusted comment breaks the global signature
+		swapped := strings.Replace(string(signature), "timestamp:1700000000", "timestamp:1", 1)
+		err = VerifyAny(message, []byte(swapped), []Key{key})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "trusted comment")
+	}
+}
+
+func TestVerifyAny_TriesEveryKey(t *testing.T) {
+	sshKey, err := ParseKey(string(readFixture(t, "ssh_ed25519.pub")))
+	require.NoError(t, err)
+	pub, sign := minisignPair(t, true)
+	miniKey, err := ParseKey(pub)
+	require.NoError(t, err)
+
+	message := []byte("content")
+	assert.NoError(t, VerifyAny(message, sign(message), []Key{sshKey, miniKey}))
+	assert.Error(t, VerifyAny(message, sign(message), nil))
+}
+
+func TestParseKey_Invalid(t *testing.T) {
+	for _, s := range []string{"", "ssh-ed25519 notbase64!", "RWQnotakey", "hello"} {
+		_, err := ParseKey(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestChecksums(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write("CLAUDE.md", "## Team\n\nUse tabs.")
+	write("commands/review.md", "Review it")
+	write(".git/HEAD", "ref: refs/heads/main")
+	write(ChecksumsFile, "old")
+	write(SSHSignatureFile, "old")
+
+	c, err := Generate(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CLAUDE.md", "commands/review.md"}, c.Paths())
+
+	data, err := c.Marshal()
+	require.NoError(t, err)
+	parsed, err := ParseChecksums(data)
+	require.NoError(t, err)
+
+	assert.NoError(t, parsed.Check("CLAUDE.md", []byte("## Team\n\nUse tabs.")))
+	err = parsed.Check("CLAUDE.md", []byte("## Team\n\nUse spaces."))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match")
+	err = parsed.Check("rules/new.md", []byte("x"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not in the signed checksums")
+
+	// The checked-in fixture lists the same CLAUDE.md content
+	fixture, err := ParseChecksums(readFixture(t, "checksums.json"))
+	require.NoError(t, err)
+	assert.NoError(t, fixture.Check("CLAUDE.md", []byte("## Team\n\nUse tabs.")))
+}
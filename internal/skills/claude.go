@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/HartBrook/staghorn/internal/fsutil"
 )
 
 // Header prefix used to identify staghorn-managed skills.
@@ -151,7 +153,7 @@ func SyncToClaude(skill *Skill, claudeSkillsDir string) (int, error) {
 
 	// Write the converted SKILL.md
 	content := ConvertToClaude(skill)
-	if err := os.WriteFile(destSkillMD, []byte(content), 0644); err != nil {
+	if err := fsutil.WriteFile(destSkillMD, []byte(content), 0644); err != nil {
 		return filesWritten, fmt.Errorf("failed to write SKILL.md: %w", err)
 	}
 	filesWritten++
@@ -188,14 +190,11 @@ func copyFile(src, dst string) error {
 		return err
 	}
 
-	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, srcInfo.Mode())
+	content, err := io.ReadAll(srcFile)
 	if err != nil {
 		return err
 	}
-	defer dstFile.Close()
-
-	_, err = io.Copy(dstFile, srcFile)
-	return err
+	return fsutil.WriteFile(dst, content, srcInfo.Mode())
 }
 
 // RemoveSkill removes a skill directory from Claude Code's skills directory.
@@ -0,0 +1,97 @@
+package skills
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/HartBrook/staghorn/internal/fsutil"
+)
+
+// Held is a skill change sync didn't install because it needs approval.
+type Held struct {
+	Skill       string    `json:"skill"`
+	Repo        string    `json:"repo"`
+	Fingerprint string    `json:"fingerprint"` // See Fingerprint; approval installs only this version
+	Risks       []Risk    `json:"risks"`
+	Held        time.Time `json:"held"`
+}
+
+// Pending is the set of skill changes awaiting 'staghorn approve'.
+type Pending struct {
+	Skills []Held `json:"skills"`
+}
+
+// LoadPending reads the pending list at path. A missing file is an empty list.
+func LoadPending(path string) (*Pending, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Pending{}, nil
+		}
+		return nil, err
+	}
+
+	var p Pending
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Save writes the pending list to path, removing the file when nothing is pending.
+func (p *Pending) Save(path string) error {
+	if len(p.Skills) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	sort.Slice(p.Skills, func(i, j int) bool { return p.Skills[i].Skill < p.Skills[j].Skill })
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Get returns the held change for a skill, or nil.
+func (p *Pending) Get(skill string) *Held {
+	for i := range p.Skills {
+		if p.Skills[i].Skill == skill {
+			return &p.Skills[i]
+		}
+	}
+	return nil
+}
+
+// Hold records a change awaiting approval, replacing any earlier one for the
+// same skill. The original hold time is kept if the fingerprint is unchanged.
+func (p *Pending) Hold(h Held) {
+	if prev := p.Get(h.Skill); prev != nil {
+		if prev.Fingerprint == h.Fingerprint {
+			h.Held = prev.Held
+		}
+		*prev = h
+		return
+	}
+	p.Skills = append(p.Skills, h)
+}
+
+// Remove drops the held change for a skill, if any.
+func (p *Pending) Remove(skill string) {
+	kept := p.Skills[:0]
+	for _, h := range p.Skills {
+		if h.Skill != skill {
+			kept = append(kept, h)
+		}
+	}
+	p.Skills = kept
+}
@@ -0,0 +1,200 @@
+package skills
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RiskKind is a category of skill change that can run code or widen what a skill may do.
+type RiskKind string
+
+const (
+	RiskHooks        RiskKind = "hooks"         // Pre/post hooks added or changed
+	RiskAllowedTools RiskKind = "allowed-tools" // Tools added to allowed-tools
+	RiskExecutable   RiskKind = "executable"    // Executable supporting file added or changed
+)
+
+// Risk is one risky change between two versions of a skill.
+type Risk struct {
+	Kind   RiskKind `json:"kind"`
+	Detail string   `json:"detail"`
+}
+
+// String renders the risk for display, e.g. "hooks: pre: ./setup.sh".
+func (r Risk) String() string {
+	return string(r.Kind) + ": " + r.Detail
+}
+
+// scriptExtensions are supporting files treated as executable even without the
+// executable bit, since fetching from GitHub doesn't preserve file modes.
+var scriptExtensions = map[string]bool{
+	".sh": true, ".bash": true, ".zsh": true, ".fish": true,
+	".py": true, ".rb": true, ".pl": true, ".js": true, ".mjs": true, ".ts": true,
+	".ps1": true, ".bat": true, ".cmd": true,
+}
+
+// readOnlyTools never prompt for permission in Claude Code, so allowing them
+// doesn't widen what a skill can do.
+var readOnlyTools = map[string]bool{"Read": true, "Grep": true, "Glob": true, "LS": true}
+
+// Profile is the part of a skill that review cares about.
+type Profile struct {
+	Hooks        Hooks
+	AllowedTools []string
+	Executables  map[string]string // Slash-separated relative path -> sha256 of content
+}
+
+// ProfileOf returns the review profile of a skill about to be installed.
+func ProfileOf(skill *Skill) (*Profile, error) {
+	p := &Profile{AllowedTools: skill.AllowedToolsList(), Executables: make(map[string]string)}
+	if skill.Hooks != nil {
+		p.Hooks = *skill.Hooks
+	}
+	for rel, path := range skill.SupportingFiles {
+		if err := p.addFile(filepath.ToSlash(rel), path); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// InstalledProfile returns the review profile of the skill installed under
+// claudeSkillsDir, or nil if it isn't installed.
+func InstalledProfile(claudeSkillsDir, name string) (*Profile, error) {
+	dir := filepath.Join(claudeSkillsDir, name)
+	fm, err := ReadFrontmatterOnly(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	p := &Profile{Executables: make(map[string]string)}
+	if fm.Hooks != nil {
+		p.Hooks = *fm.Hooks
+	}
+	if fm.AllowedTools != "" {
+		p.AllowedTools = strings.Fields(fm.AllowedTools)
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() == "SKILL.md" {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		return p.addFile(filepath.ToSlash(rel), path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// addFile records the file at path if it is executable.
+func (p *Profile) addFile(rel, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !isExecutable(rel, info.Mode(), content) {
+		return nil
+	}
+	sum := sha256.Sum256(content)
+	p.Executables[rel] = hex.EncodeToString(sum[:])
+	return nil
+}
+
+// isExecutable reports whether a supporting file can be run as a program:
+// it has an executable bit, a shebang, or a script extension.
+func isExecutable(rel string, mode fs.FileMode, content []byte) bool {
+	if mode&0111 != 0 || bytes.HasPrefix(content, []byte("#!")) {
+		return true
+	}
+	return scriptExtensions[strings.ToLower(filepath.Ext(rel))]
+}
+
+// Classify returns the risky changes going from prev to next. A nil prev
+// means the skill is new, so any hooks, executables, or allowed-tools beyond
+// read-only ones are risky.
+// Removing hooks, tools, or scripts is never risky.
+func Classify(prev, next *Profile) []Risk {
+	if prev == nil {
+		prev = &Profile{}
+	}
+	var risks []Risk
+
+	if next.Hooks.Pre != "" && next.Hooks.Pre != prev.Hooks.Pre {
+		risks = append(risks, Risk{Kind: RiskHooks, Detail: "pre: " + next.Hooks.Pre})
+	}
+	if next.Hooks.Post != "" && next.Hooks.Post != prev.Hooks.Post {
+		risks = append(risks, Risk{Kind: RiskHooks, Detail: "post: " + next.Hooks.Post})
+	}
+
+	had := make(map[string]bool, len(prev.AllowedTools))
+	for _, tool := range prev.AllowedTools {
+		had[tool] = true
+	}
+	var added []string
+	for _, tool := range next.AllowedTools {
+		if !had[tool] && !readOnlyTools[tool] {
+			added = append(added, tool)
+		}
+	}
+	if len(added) > 0 {
+		risks = append(risks, Risk{Kind: RiskAllowedTools, Detail: "adds " + strings.Join(added, " ")})
+	}
+
+	rels := make([]string, 0, len(next.Executables))
+	for rel := range next.Executables {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	for _, rel := range rels {
+		old, ok := prev.Executables[rel]
+		switch {
+		case !ok:
+			risks = append(risks, Risk{Kind: RiskExecutable, Detail: rel + " added"})
+		case old != next.Executables[rel]:
+			risks = append(risks, Risk{Kind: RiskExecutable, Detail: rel + " changed"})
+		}
+	}
+
+	return risks
+}
+
+// Fingerprint hashes everything a skill would install, so an approval applies
+// to exactly the version that was reviewed.
+func Fingerprint(skill *Skill) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "SKILL.md\x00%s\x00", ConvertToClaude(skill))
+
+	rels := make([]string, 0, len(skill.SupportingFiles))
+	for rel := range skill.SupportingFiles {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	for _, rel := range rels {
+		content, err := os.ReadFile(skill.SupportingFiles[rel])
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(content))
+		h.Write(content)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}